@@ -61,7 +61,7 @@ func run() error {
 	}
 
 	// start worker / task server
-	tsk := tasks.New(cfg, logr, dataStore, diContainer.GetCommitService(), diContainer.GetRepositoryService())
+	tsk := tasks.New(cfg, logr, dataStore, diContainer.GetCommitService(), diContainer.GetRepositoryService(), diContainer.GetPullRequestService(), diContainer.GetIssueService(), diContainer.GetReleaseService(), diContainer.GetPopularityService())
 	go func() {
 		if err := tasks.StartWorker(*tsk, cfg); err != nil {
 			// return err
@@ -0,0 +1,34 @@
+package main
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/babyfaceeasy/lema/config"
+	"github.com/babyfaceeasy/lema/internal/container"
+	"github.com/babyfaceeasy/lema/pkg/logger"
+)
+
+// runBackfillTrailers records the trailers of the commits stored so far.
+func runBackfillTrailers() error {
+	cfg, err := config.LoadConfig()
+	if err != nil {
+		return fmt.Errorf("failed to initialize: %w", err)
+	}
+	logr, err := logger.NewLogger(string(cfg.GetAppEnv()))
+	if err != nil {
+		return fmt.Errorf("failed to initialize logger: %w", err)
+	}
+	defer logr.Sync()
+
+	diContainer := container.NewContainer(cfg, logr)
+	defer diContainer.Close()
+
+	count, err := diContainer.GetCommitService().BackfillCommitTrailers(context.Background())
+	if err != nil {
+		return fmt.Errorf("failed to backfill commit trailers after %d commits: %w", count, err)
+	}
+
+	fmt.Printf("%d commits had their trailers recorded\n", count)
+	return nil
+}
@@ -0,0 +1,34 @@
+package main
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/babyfaceeasy/lema/config"
+	"github.com/babyfaceeasy/lema/internal/container"
+	"github.com/babyfaceeasy/lema/pkg/logger"
+)
+
+// runClassifyBots flags the stored authors matching the configured bot patterns.
+func runClassifyBots() error {
+	cfg, err := config.LoadConfig()
+	if err != nil {
+		return fmt.Errorf("failed to initialize: %w", err)
+	}
+	logr, err := logger.NewLogger(string(cfg.GetAppEnv()))
+	if err != nil {
+		return fmt.Errorf("failed to initialize logger: %w", err)
+	}
+	defer logr.Sync()
+
+	diContainer := container.NewContainer(cfg, logr)
+	defer diContainer.Close()
+
+	count, err := diContainer.GetCommitService().ClassifyBotAuthors(context.Background())
+	if err != nil {
+		return fmt.Errorf("failed to classify bot authors: %w", err)
+	}
+
+	fmt.Printf("%d authors flagged as bots\n", count)
+	return nil
+}
@@ -0,0 +1,68 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/babyfaceeasy/lema/config"
+	"github.com/babyfaceeasy/lema/internal/container"
+	"github.com/babyfaceeasy/lema/pkg/logger"
+)
+
+// runImportMbox imports the patches of every file named in args into a pseudo-repository of the
+// mailbox host, printing the messages that could not be read.
+func runImportMbox(args []string) error {
+	fs := flag.NewFlagSet("import-mbox", flag.ExitOnError)
+	owner := fs.String("owner", "", "owner of the pseudo-repository, e.g. the mailing list")
+	repo := fs.String("repo", "", "name of the pseudo-repository, e.g. the project")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if *repo == "" || fs.NArg() == 0 {
+		fs.Usage()
+		return fmt.Errorf("a -repo and at least one file are needed")
+	}
+	if *owner == "" {
+		*owner = *repo
+	}
+
+	cfg, err := config.LoadConfig()
+	if err != nil {
+		return fmt.Errorf("failed to initialize: %w", err)
+	}
+	logr, err := logger.NewLogger(string(cfg.GetAppEnv()))
+	if err != nil {
+		return fmt.Errorf("failed to initialize logger: %w", err)
+	}
+	defer logr.Sync()
+
+	diContainer := container.NewContainer(cfg, logr)
+	defer diContainer.Close()
+
+	malformed := 0
+	for _, filename := range fs.Args() {
+		file, err := os.Open(filename)
+		if err != nil {
+			return err
+		}
+		result, err := diContainer.GetCommitService().ImportMailbox(context.Background(), strings.ToLower(*owner), strings.ToLower(*repo), file)
+		file.Close()
+		if err != nil {
+			return fmt.Errorf("failed to import %s: %w", filename, err)
+		}
+
+		fmt.Printf("%s: %d imported, %d skipped, %d malformed\n", filename, result.Imported, result.Skipped, len(result.Errors))
+		for _, e := range result.Errors {
+			fmt.Printf("  message %d (line %d): %s\n", e.Message, e.Line, e.Error)
+		}
+		malformed += len(result.Errors)
+	}
+
+	if malformed > 0 {
+		return fmt.Errorf("%d malformed messages were not imported", malformed)
+	}
+	return nil
+}
@@ -0,0 +1,51 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"os"
+)
+
+const usage = `usage: cli <command> [arguments]
+
+commands:
+  seed         seed the database with fixed data (default)
+  import-mbox  import patches from mbox files or git format-patch output:
+               import-mbox [-owner OWNER] -repo NAME FILE...
+  backfill-authors
+               credit stored commits to their authors, committers and co-authors
+  backfill-trailers
+               record the trailers of stored commits
+  classify-bots
+               flag the stored authors matching the configured bot patterns`
+
+func main() {
+	command := "seed"
+	args := os.Args[1:]
+	if len(args) > 0 {
+		command, args = args[0], args[1:]
+	}
+
+	var err error
+	switch command {
+	case "seed":
+		err = runSeed()
+	case "import-mbox":
+		err = runImportMbox(args)
+	case "backfill-authors":
+		err = runBackfillAuthors()
+	case "backfill-trailers":
+		err = runBackfillTrailers()
+	case "classify-bots":
+		err = runClassifyBots()
+	case "-h", "--help", "help":
+		fmt.Println(usage)
+		return
+	default:
+		fmt.Fprintln(os.Stderr, usage)
+		os.Exit(2)
+	}
+	if err != nil {
+		log.Fatal(err)
+	}
+}
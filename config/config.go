@@ -2,8 +2,12 @@ package config
 
 import (
 	"fmt"
+	"os"
+	"strings"
 
+	"github.com/babyfaceeasy/lema/internal/domain"
 	"github.com/caarlos0/env/v11"
+	"gopkg.in/yaml.v2"
 )
 
 type Env string
@@ -13,6 +17,11 @@ const (
 	Env_Dev  = "dev"
 )
 
+const (
+	GithubCommitsApiRest    = "rest"
+	GithubCommitsApiGraphQL = "graphql"
+)
+
 // tempConfig to load configs solely
 type tempConfig struct {
 	// Server
@@ -39,10 +48,111 @@ type tempConfig struct {
 	AppEnv        Env    `env:"APP_ENV" envDefault:"dev"`
 	ProjectRoot   string `env:"PROJECT_ROOT"`
 	CorsWhiteList string `env:"CORS_WHITELIST"`
+	AdminApiToken string `env:"ADMIN_API_TOKEN"`
 
 	// Github
-	GithubBaseUrl string `env:"GITHUB_BASE_URL"`
-	GithubToken   string `env:"GITHUB_TOKEN"`
+	GithubBaseUrl          string   `env:"GITHUB_BASE_URL"`
+	GithubToken            string   `env:"GITHUB_TOKEN"`
+	GithubTokens           []string `env:"GITHUB_TOKENS" envSeparator:","`
+	GithubRateLimitInRedis bool     `env:"GITHUB_RATE_LIMIT_IN_REDIS" envDefault:"false"`
+	GithubCommitsApi       string   `env:"GITHUB_COMMITS_API" envDefault:"rest"`
+	GithubFetchCommitStats bool     `env:"GITHUB_FETCH_COMMIT_STATS" envDefault:"false"`
+	GithubWebhookSecret    string   `env:"GITHUB_WEBHOOK_SECRET"`
+
+	// Github App
+	GithubApiUrl            string `env:"GITHUB_API_URL" envDefault:"https://api.github.com"`
+	GithubAppID             int64  `env:"GITHUB_APP_ID"`
+	GithubAppPrivateKeyPath string `env:"GITHUB_APP_PRIVATE_KEY_PATH"`
+	GithubAppInstallationID int64  `env:"GITHUB_APP_INSTALLATION_ID"`
+
+	// Gitlab
+	GitlabBaseUrl string `env:"GITLAB_BASE_URL" envDefault:"https://gitlab.com/api/v4"`
+	GitlabToken   string `env:"GITLAB_TOKEN"`
+
+	// Bitbucket
+	BitbucketBaseUrl     string `env:"BITBUCKET_BASE_URL" envDefault:"https://api.bitbucket.org/2.0"`
+	BitbucketUsername    string `env:"BITBUCKET_USERNAME"`
+	BitbucketAppPassword string `env:"BITBUCKET_APP_PASSWORD"`
+
+	// Authors
+	BotAuthorPatterns []string `env:"BOT_AUTHOR_PATTERNS" envSeparator:"," envDefault:"autoroll,\\.gserviceaccount\\.com$"`
+
+	// Other hosts, e.g. GitHub Enterprise Servers
+	ProviderHostsFile string `env:"PROVIDER_HOSTS_FILE"`
+	// GithubHostsFile is the former name of ProviderHostsFile, still read when it is not set.
+	GithubHostsFile string `env:"GITHUB_HOSTS_FILE"`
+}
+
+// ProviderHost describes a host repositories can be monitored on besides github.com, gitlab.com
+// and bitbucket.org, such as a GitHub Enterprise Server, a self-managed GitLab instance or a
+// Gitea or Forgejo instance.
+type ProviderHost struct {
+	Host string `yaml:"host"`
+	// Provider is "github" (the default), "gitlab", "gitea", "gerrit" or "local", "forgejo"
+	// standing for "gitea".
+	Provider string `yaml:"provider"`
+	// BaseUrl is the root of the repositories API, e.g. https://ghe.corp/api/v3/repos or
+	// https://gitlab.corp/api/v4 or https://gitea.corp/api/v1, the root of a Gerrit server, or
+	// the directory holding the local repositories as owner/name.
+	BaseUrl string `yaml:"base_url"`
+	// ApiUrl is the root of the GitHub API, derived from BaseUrl when empty.
+	ApiUrl string `yaml:"api_url"`
+	// GraphqlUrl is the GraphQL endpoint, https://{host}/api/graphql on a GitHub Enterprise Server.
+	GraphqlUrl string `yaml:"graphql_url"`
+	// Tokens of a Gerrit server are written as username:http-password.
+	Tokens []string `yaml:"tokens"`
+}
+
+type providerHostsFile struct {
+	Hosts []ProviderHost `yaml:"hosts"`
+}
+
+// loadProviderHosts reads the additional hosts from a YAML file. Environment variables
+// referenced as ${NAME} are expanded so tokens do not need to be written in the file.
+func loadProviderHosts(filename string) ([]ProviderHost, error) {
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read provider hosts file: %w", err)
+	}
+
+	var f providerHostsFile
+	if err := yaml.Unmarshal([]byte(os.ExpandEnv(string(data))), &f); err != nil {
+		return nil, fmt.Errorf("failed to parse provider hosts file: %w", err)
+	}
+
+	for i, h := range f.Hosts {
+		if h.Host == "" || h.BaseUrl == "" {
+			return nil, fmt.Errorf("provider host #%d needs both a host and a base_url", i+1)
+		}
+		f.Hosts[i].Host = strings.ToLower(h.Host)
+
+		switch h.Provider {
+		case "", domain.ProviderGithub:
+			f.Hosts[i].Provider = domain.ProviderGithub
+		case domain.ProviderGitlab:
+			continue
+		case domain.ProviderGitea, "forgejo":
+			f.Hosts[i].Provider = domain.ProviderGitea
+			continue
+		case domain.ProviderGerrit, domain.ProviderLocal:
+			continue
+		default:
+			return nil, fmt.Errorf("provider host %s has an unknown provider %q", h.Host, h.Provider)
+		}
+
+		if h.ApiUrl == "" {
+			f.Hosts[i].ApiUrl = strings.TrimSuffix(strings.TrimSuffix(h.BaseUrl, "/"), "/repos")
+		}
+		if h.GraphqlUrl == "" {
+			apiUrl := f.Hosts[i].ApiUrl
+			if strings.HasSuffix(apiUrl, "/api/v3") {
+				f.Hosts[i].GraphqlUrl = strings.TrimSuffix(apiUrl, "/v3") + "/graphql"
+			} else {
+				f.Hosts[i].GraphqlUrl = apiUrl + "/graphql"
+			}
+		}
+	}
+	return f.Hosts, nil
 }
 
 type Config struct {
@@ -70,10 +180,37 @@ type Config struct {
 	appEnv        Env    `env:"APP_ENV" envDefault:"dev"`
 	projectRoot   string `env:"PROJECT_ROOT"`
 	corsWhiteList string `env:"CORS_WHITELIST"`
+	adminApiToken string `env:"ADMIN_API_TOKEN"`
 
 	// Github
-	githubBaseUrl string `env:"GITHUB_BASE_URL"`
-	githubToken   string `env:"GITHUB_TOKEN"`
+	githubBaseUrl          string   `env:"GITHUB_BASE_URL"`
+	githubToken            string   `env:"GITHUB_TOKEN"`
+	githubTokens           []string `env:"GITHUB_TOKENS" envSeparator:","`
+	githubRateLimitInRedis bool     `env:"GITHUB_RATE_LIMIT_IN_REDIS" envDefault:"false"`
+	githubCommitsApi       string   `env:"GITHUB_COMMITS_API" envDefault:"rest"`
+	githubFetchCommitStats bool     `env:"GITHUB_FETCH_COMMIT_STATS" envDefault:"false"`
+	githubWebhookSecret    string   `env:"GITHUB_WEBHOOK_SECRET"`
+
+	// Github App
+	githubApiUrl            string `env:"GITHUB_API_URL" envDefault:"https://api.github.com"`
+	githubAppID             int64  `env:"GITHUB_APP_ID"`
+	githubAppPrivateKeyPath string `env:"GITHUB_APP_PRIVATE_KEY_PATH"`
+	githubAppInstallationID int64  `env:"GITHUB_APP_INSTALLATION_ID"`
+
+	// Gitlab
+	gitlabBaseUrl string `env:"GITLAB_BASE_URL" envDefault:"https://gitlab.com/api/v4"`
+	gitlabToken   string `env:"GITLAB_TOKEN"`
+
+	// Bitbucket
+	bitbucketBaseUrl     string `env:"BITBUCKET_BASE_URL" envDefault:"https://api.bitbucket.org/2.0"`
+	bitbucketUsername    string `env:"BITBUCKET_USERNAME"`
+	bitbucketAppPassword string `env:"BITBUCKET_APP_PASSWORD"`
+
+	// Authors
+	botAuthorPatterns []string `env:"BOT_AUTHOR_PATTERNS" envSeparator:"," envDefault:"autoroll,\\.gserviceaccount\\.com$"`
+
+	// Other hosts
+	providerHosts []ProviderHost
 }
 
 func LoadConfig() (*Config, error) {
@@ -82,6 +219,19 @@ func LoadConfig() (*Config, error) {
 		return nil, err
 	}
 
+	hostsFile := tc.ProviderHostsFile
+	if hostsFile == "" {
+		hostsFile = tc.GithubHostsFile
+	}
+
+	var providerHosts []ProviderHost
+	if hostsFile != "" {
+		var err error
+		if providerHosts, err = loadProviderHosts(hostsFile); err != nil {
+			return nil, err
+		}
+	}
+
 	return &Config{
 		// server
 		apiServerPort: tc.ApiServerPort,
@@ -107,10 +257,37 @@ func LoadConfig() (*Config, error) {
 		appEnv:        tc.AppEnv,
 		projectRoot:   tc.ProjectRoot,
 		corsWhiteList: tc.CorsWhiteList,
+		adminApiToken: tc.AdminApiToken,
 
 		// Github
-		githubBaseUrl: tc.GithubBaseUrl,
-		githubToken:   tc.GithubToken,
+		githubBaseUrl:          tc.GithubBaseUrl,
+		githubToken:            tc.GithubToken,
+		githubTokens:           tc.GithubTokens,
+		githubRateLimitInRedis: tc.GithubRateLimitInRedis,
+		githubCommitsApi:       tc.GithubCommitsApi,
+		githubFetchCommitStats: tc.GithubFetchCommitStats,
+		githubWebhookSecret:    tc.GithubWebhookSecret,
+
+		// Github App
+		githubApiUrl:            tc.GithubApiUrl,
+		githubAppID:             tc.GithubAppID,
+		githubAppPrivateKeyPath: tc.GithubAppPrivateKeyPath,
+		githubAppInstallationID: tc.GithubAppInstallationID,
+
+		// Gitlab
+		gitlabBaseUrl: tc.GitlabBaseUrl,
+		gitlabToken:   tc.GitlabToken,
+
+		// Bitbucket
+		bitbucketBaseUrl:     tc.BitbucketBaseUrl,
+		bitbucketUsername:    tc.BitbucketUsername,
+		bitbucketAppPassword: tc.BitbucketAppPassword,
+
+		// Authors
+		botAuthorPatterns: tc.BotAuthorPatterns,
+
+		// Other hosts
+		providerHosts: providerHosts,
 	}, nil
 }
 
@@ -174,6 +351,11 @@ func (c *Config) GetCorsWhiteList() string {
 	return c.corsWhiteList
 }
 
+// GetAdminApiToken returns the bearer token the admin endpoints require, which are refused without one.
+func (c *Config) GetAdminApiToken() string {
+	return c.adminApiToken
+}
+
 func (c *Config) GetGithubBaseUrl() string {
 	return c.githubBaseUrl
 }
@@ -182,6 +364,109 @@ func (c *Config) GetGithubToken() string {
 	return c.githubToken
 }
 
+// GetGithubTokens returns every configured GitHub token, GITHUB_TOKEN first.
+func (c *Config) GetGithubTokens() []string {
+	tokens := make([]string, 0, len(c.githubTokens)+1)
+	if c.githubToken != "" {
+		tokens = append(tokens, c.githubToken)
+	}
+	return append(tokens, c.githubTokens...)
+}
+
+// GetGithubRateLimitInRedis reports whether the GitHub rate limit budget is shared through Redis.
+func (c *Config) GetGithubRateLimitInRedis() bool {
+	return c.githubRateLimitInRedis
+}
+
+// GetGithubCommitsApi returns which GitHub API commit history is fetched with: "rest" or "graphql".
+func (c *Config) GetGithubCommitsApi() string {
+	return c.githubCommitsApi
+}
+
+// UsesGithubGraphQL reports whether commit history is fetched with the GraphQL API.
+func (c *Config) UsesGithubGraphQL() bool {
+	return c.githubCommitsApi == GithubCommitsApiGraphQL
+}
+
+// GetGithubFetchCommitStats reports whether line statistics are fetched for every ingested commit.
+func (c *Config) GetGithubFetchCommitStats() bool {
+	return c.githubFetchCommitStats
+}
+
+// GetGithubWebhookSecret returns the secret GitHub webhooks are signed with, webhooks being refused without one.
+func (c *Config) GetGithubWebhookSecret() string {
+	return c.githubWebhookSecret
+}
+
+// GetGithubApiUrl returns the root of the GitHub REST API, used for endpoints outside /repos.
+func (c *Config) GetGithubApiUrl() string {
+	return c.githubApiUrl
+}
+
+func (c *Config) GetGithubAppID() int64 {
+	return c.githubAppID
+}
+
+func (c *Config) GetGithubAppPrivateKeyPath() string {
+	return c.githubAppPrivateKeyPath
+}
+
+// GetGithubAppInstallationID returns the installation used when a repository's own cannot be resolved.
+func (c *Config) GetGithubAppInstallationID() int64 {
+	return c.githubAppInstallationID
+}
+
+func (c *Config) GetGitlabBaseUrl() string {
+	return c.gitlabBaseUrl
+}
+
+func (c *Config) GetGitlabToken() string {
+	return c.gitlabToken
+}
+
+func (c *Config) GetBitbucketBaseUrl() string {
+	return c.bitbucketBaseUrl
+}
+
+func (c *Config) GetBitbucketUsername() string {
+	return c.bitbucketUsername
+}
+
+func (c *Config) GetBitbucketAppPassword() string {
+	return c.bitbucketAppPassword
+}
+
+// GetBotAuthorPatterns returns the regular expressions flagging the names and emails of bot
+// authors, besides GitHub Apps.
+func (c *Config) GetBotAuthorPatterns() []string {
+	return c.botAuthorPatterns
+}
+
+// GetProviderHosts returns the hosts configured besides github.com, gitlab.com and bitbucket.org.
+func (c *Config) GetProviderHosts() []ProviderHost {
+	return c.providerHosts
+}
+
+// HostProvider returns the provider serving host, and false when repositories on host cannot be monitored.
+func (c *Config) HostProvider(host string) (string, bool) {
+	switch host {
+	case domain.DefaultHost:
+		return domain.ProviderGithub, true
+	case domain.GitlabHost:
+		return domain.ProviderGitlab, true
+	case domain.BitbucketHost:
+		return domain.ProviderBitbucket, true
+	case domain.MailboxHost:
+		return domain.ProviderMailbox, true
+	}
+	for _, h := range c.providerHosts {
+		if h.Host == host {
+			return h.Provider, true
+		}
+	}
+	return "", false
+}
+
 func New() (*Config, error) {
 	var cfg Config
 	cfg, err := env.ParseAs[Config]()
@@ -0,0 +1,11 @@
+-- +goose Up
+CREATE TABLE IF NOT EXISTS github_etags (
+    id bigserial NOT NULL PRIMARY KEY,
+    url TEXT NOT NULL UNIQUE,
+    etag VARCHAR(200) NOT NULL DEFAULT '',
+    last_modified VARCHAR(200) NOT NULL DEFAULT '',
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+-- +goose Down
+DROP TABLE IF EXISTS github_etags;
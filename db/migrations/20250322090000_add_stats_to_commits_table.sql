@@ -0,0 +1,17 @@
+-- +goose Up
+ALTER TABLE commits
+    ADD COLUMN IF NOT EXISTS additions INTEGER NOT NULL DEFAULT 0,
+    ADD COLUMN IF NOT EXISTS deletions INTEGER NOT NULL DEFAULT 0,
+    ADD COLUMN IF NOT EXISTS changed_files INTEGER NOT NULL DEFAULT 0,
+    ADD COLUMN IF NOT EXISTS stats_fetched_at TIMESTAMPTZ;
+
+-- Index to quickly find commits whose statistics still have to be fetched
+CREATE INDEX IF NOT EXISTS idx_commits_stats_pending ON commits(repository_id) WHERE stats_fetched_at IS NULL;
+
+-- +goose Down
+DROP INDEX IF EXISTS idx_commits_stats_pending;
+ALTER TABLE commits
+    DROP COLUMN IF EXISTS additions,
+    DROP COLUMN IF EXISTS deletions,
+    DROP COLUMN IF EXISTS changed_files,
+    DROP COLUMN IF EXISTS stats_fetched_at;
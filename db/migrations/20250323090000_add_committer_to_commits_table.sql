@@ -0,0 +1,15 @@
+-- +goose Up
+ALTER TABLE commits
+    ADD COLUMN IF NOT EXISTS committer_id BIGINT REFERENCES authors(id) ON DELETE SET NULL,
+    ADD COLUMN IF NOT EXISTS committer_date TIMESTAMPTZ;
+
+-- Commits stored before the committer was tracked are attributed to their author
+UPDATE commits SET committer_id = author_id, committer_date = commit_date WHERE committer_id IS NULL;
+
+CREATE INDEX IF NOT EXISTS idx_commits_committer_date ON commits(committer_date);
+
+-- +goose Down
+DROP INDEX IF EXISTS idx_commits_committer_date;
+ALTER TABLE commits
+    DROP COLUMN IF EXISTS committer_id,
+    DROP COLUMN IF EXISTS committer_date;
@@ -0,0 +1,28 @@
+-- +goose Up
+ALTER TABLE repositories
+    ADD COLUMN IF NOT EXISTS default_branch VARCHAR(255) NOT NULL DEFAULT '';
+
+CREATE TABLE IF NOT EXISTS repository_branches (
+    id bigserial NOT NULL PRIMARY KEY,
+    uid UUID NOT NULL UNIQUE DEFAULT gen_random_uuid(),
+    repository_id BIGINT NOT NULL REFERENCES repositories(id) ON DELETE CASCADE,
+    name VARCHAR(255) NOT NULL,
+    -- watermark of the last successful sync, NULL until the branch was synced once
+    since_date TIMESTAMPTZ,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE(repository_id, name)
+);
+
+-- A commit can be reachable from many branches
+CREATE TABLE IF NOT EXISTS commit_branches (
+    commit_id BIGINT NOT NULL REFERENCES commits(id) ON DELETE CASCADE,
+    branch_id BIGINT NOT NULL REFERENCES repository_branches(id) ON DELETE CASCADE,
+    PRIMARY KEY (commit_id, branch_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_commit_branches_branch_id ON commit_branches(branch_id);
+
+-- +goose Down
+DROP TABLE IF EXISTS commit_branches;
+DROP TABLE IF EXISTS repository_branches;
+ALTER TABLE repositories DROP COLUMN IF EXISTS default_branch;
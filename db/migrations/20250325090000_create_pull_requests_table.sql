@@ -0,0 +1,28 @@
+-- +goose Up
+CREATE TABLE IF NOT EXISTS pull_requests (
+    id bigserial NOT NULL PRIMARY KEY,
+    uid UUID NOT NULL UNIQUE DEFAULT gen_random_uuid(),
+    repository_id BIGINT NOT NULL REFERENCES repositories(id) ON DELETE CASCADE,
+    number BIGINT NOT NULL,
+    title TEXT NOT NULL,
+    state VARCHAR(20) NOT NULL,
+    author_login VARCHAR(255) NOT NULL,
+    url VARCHAR(255) NOT NULL,
+    base_branch VARCHAR(255) NOT NULL,
+    head_branch VARCHAR(255) NOT NULL,
+    merge_commit_sha VARCHAR(100) NOT NULL DEFAULT '',
+    -- the stored commit the pull request was merged as, once it has been ingested
+    merge_commit_id BIGINT REFERENCES commits(id) ON DELETE SET NULL,
+    opened_at TIMESTAMPTZ NOT NULL,
+    updated_at TIMESTAMPTZ NOT NULL,
+    closed_at TIMESTAMPTZ,
+    merged_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE(repository_id, number)
+);
+
+CREATE INDEX IF NOT EXISTS idx_pull_requests_repository_opened_at ON pull_requests(repository_id, opened_at);
+CREATE INDEX IF NOT EXISTS idx_pull_requests_merge_commit_id ON pull_requests(merge_commit_id);
+
+-- +goose Down
+DROP TABLE IF EXISTS pull_requests;
@@ -0,0 +1,34 @@
+-- +goose Up
+CREATE TABLE IF NOT EXISTS issues (
+    id bigserial NOT NULL PRIMARY KEY,
+    uid UUID NOT NULL UNIQUE DEFAULT gen_random_uuid(),
+    repository_id BIGINT NOT NULL REFERENCES repositories(id) ON DELETE CASCADE,
+    number BIGINT NOT NULL,
+    title TEXT NOT NULL,
+    state VARCHAR(20) NOT NULL,
+    labels TEXT[] NOT NULL DEFAULT '{}',
+    author_login VARCHAR(255) NOT NULL,
+    url VARCHAR(255) NOT NULL,
+    opened_at TIMESTAMPTZ NOT NULL,
+    updated_at TIMESTAMPTZ NOT NULL,
+    closed_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE(repository_id, number)
+);
+
+-- Issues referenced by commit messages. The issue is kept by repository name and number as it
+-- may live in a repository that is not monitored, or not have been ingested yet.
+CREATE TABLE IF NOT EXISTS commit_issue_references (
+    commit_id BIGINT NOT NULL REFERENCES commits(id) ON DELETE CASCADE,
+    owner_name VARCHAR(200) NOT NULL,
+    repository_name VARCHAR(200) NOT NULL,
+    issue_number BIGINT NOT NULL,
+    keyword VARCHAR(20) NOT NULL,
+    PRIMARY KEY (commit_id, owner_name, repository_name, issue_number)
+);
+
+CREATE INDEX IF NOT EXISTS idx_commit_issue_references_issue ON commit_issue_references(lower(owner_name), lower(repository_name), issue_number);
+
+-- +goose Down
+DROP TABLE IF EXISTS commit_issue_references;
+DROP TABLE IF EXISTS issues;
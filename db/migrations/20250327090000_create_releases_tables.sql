@@ -0,0 +1,37 @@
+-- +goose Up
+CREATE TABLE IF NOT EXISTS tags (
+    id bigserial NOT NULL PRIMARY KEY,
+    uid UUID NOT NULL UNIQUE DEFAULT gen_random_uuid(),
+    repository_id BIGINT NOT NULL REFERENCES repositories(id) ON DELETE CASCADE,
+    name VARCHAR(255) NOT NULL,
+    sha VARCHAR(100) NOT NULL,
+    -- the stored commit the tag points at, once it has been ingested
+    commit_id BIGINT REFERENCES commits(id) ON DELETE SET NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE(repository_id, name)
+);
+
+CREATE TABLE IF NOT EXISTS releases (
+    id bigserial NOT NULL PRIMARY KEY,
+    uid UUID NOT NULL UNIQUE DEFAULT gen_random_uuid(),
+    repository_id BIGINT NOT NULL REFERENCES repositories(id) ON DELETE CASCADE,
+    github_id BIGINT NOT NULL,
+    tag_name VARCHAR(255) NOT NULL,
+    name TEXT NOT NULL,
+    -- resolved through the release tag, empty when the tag is unknown
+    sha VARCHAR(100) NOT NULL DEFAULT '',
+    commit_id BIGINT REFERENCES commits(id) ON DELETE SET NULL,
+    draft BOOLEAN NOT NULL DEFAULT FALSE,
+    prerelease BOOLEAN NOT NULL DEFAULT FALSE,
+    author_login VARCHAR(255) NOT NULL,
+    url VARCHAR(255) NOT NULL,
+    published_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE(repository_id, github_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_releases_repository_published_at ON releases(repository_id, published_at);
+
+-- +goose Down
+DROP TABLE IF EXISTS releases;
+DROP TABLE IF EXISTS tags;
@@ -0,0 +1,13 @@
+-- +goose Up
+ALTER TABLE repositories ADD COLUMN IF NOT EXISTS host VARCHAR(255) NOT NULL DEFAULT 'github.com';
+
+-- the same owner/name can now exist on several hosts
+ALTER TABLE repositories DROP CONSTRAINT IF EXISTS repositories_name_owner_name_key;
+DROP INDEX IF EXISTS idx_repositories_name_owner;
+CREATE UNIQUE INDEX IF NOT EXISTS idx_repositories_host_name_owner ON repositories(host, name, owner_name);
+
+-- +goose Down
+DROP INDEX IF EXISTS idx_repositories_host_name_owner;
+CREATE UNIQUE INDEX IF NOT EXISTS idx_repositories_name_owner ON repositories(name, owner_name);
+ALTER TABLE repositories ADD CONSTRAINT repositories_name_owner_name_key UNIQUE (name, owner_name);
+ALTER TABLE repositories DROP COLUMN IF EXISTS host;
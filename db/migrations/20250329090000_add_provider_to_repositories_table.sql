@@ -0,0 +1,5 @@
+-- +goose Up
+ALTER TABLE repositories ADD COLUMN IF NOT EXISTS provider VARCHAR(50) NOT NULL DEFAULT 'github';
+
+-- +goose Down
+ALTER TABLE repositories DROP COLUMN IF EXISTS provider;
@@ -0,0 +1,27 @@
+-- +goose Up
+-- Code reviews of commits, as recorded by review-based providers such as Gerrit. Accounts are
+-- kept by their ID on the review system as their email address may be hidden.
+CREATE TABLE IF NOT EXISTS commit_reviews (
+    commit_id BIGINT NOT NULL PRIMARY KEY REFERENCES commits(id) ON DELETE CASCADE,
+    change_number BIGINT NOT NULL,
+    change_id VARCHAR(255) NOT NULL,
+    url VARCHAR(255) NOT NULL,
+    owner_account_id BIGINT NOT NULL,
+    owner_name VARCHAR(200) NOT NULL,
+    owner_email VARCHAR(200) NOT NULL DEFAULT '',
+    opened_at TIMESTAMPTZ NOT NULL,
+    submitted_at TIMESTAMPTZ
+);
+
+CREATE TABLE IF NOT EXISTS commit_reviewers (
+    commit_id BIGINT NOT NULL REFERENCES commit_reviews(commit_id) ON DELETE CASCADE,
+    account_id BIGINT NOT NULL,
+    name VARCHAR(200) NOT NULL,
+    email VARCHAR(200) NOT NULL DEFAULT '',
+    code_review SMALLINT NOT NULL DEFAULT 0,
+    PRIMARY KEY (commit_id, account_id)
+);
+
+-- +goose Down
+DROP TABLE IF EXISTS commit_reviewers;
+DROP TABLE IF EXISTS commit_reviews;
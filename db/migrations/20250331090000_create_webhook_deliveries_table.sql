@@ -0,0 +1,13 @@
+-- +goose Up
+-- Deliveries of webhooks already handled, GitHub redelivering a webhook under the same ID.
+CREATE TABLE IF NOT EXISTS webhook_deliveries (
+    delivery_id VARCHAR(100) NOT NULL PRIMARY KEY,
+    event VARCHAR(50) NOT NULL,
+    received_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+ALTER TABLE repositories ADD COLUMN IF NOT EXISTS state VARCHAR(20) NOT NULL DEFAULT 'active';
+
+-- +goose Down
+ALTER TABLE repositories DROP COLUMN IF EXISTS state;
+DROP TABLE IF EXISTS webhook_deliveries;
@@ -0,0 +1,18 @@
+-- +goose Up
+-- Popularity counters of repositories over time. Snapshots taken by lema record every counter,
+-- while star history backfilled from the stargazers API only records stars.
+CREATE TABLE IF NOT EXISTS repository_snapshots (
+    id bigserial NOT NULL PRIMARY KEY,
+    repository_id BIGINT NOT NULL REFERENCES repositories(id) ON DELETE CASCADE,
+    source VARCHAR(20) NOT NULL DEFAULT 'snapshot',
+    stars_count INT,
+    forks_count INT,
+    watchers_count INT,
+    open_issues_count INT,
+    recorded_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_repository_snapshots_repository_recorded_at ON repository_snapshots(repository_id, recorded_at);
+
+-- +goose Down
+DROP TABLE IF EXISTS repository_snapshots;
@@ -0,0 +1,14 @@
+-- +goose Up
+-- Everyone credited with a commit and their role in it. Authors and committers are also kept on
+-- commits; co-authors come from "Co-authored-by:" trailers.
+CREATE TABLE IF NOT EXISTS commit_authors (
+    commit_id BIGINT NOT NULL REFERENCES commits(id) ON DELETE CASCADE,
+    author_id BIGINT NOT NULL REFERENCES authors(id) ON DELETE CASCADE,
+    role VARCHAR(20) NOT NULL,
+    PRIMARY KEY (commit_id, author_id, role)
+);
+
+CREATE INDEX IF NOT EXISTS idx_commit_authors_author_role ON commit_authors(author_id, role);
+
+-- +goose Down
+DROP TABLE IF EXISTS commit_authors;
@@ -0,0 +1,15 @@
+-- +goose Up
+-- Trailers ending commit messages, e.g. "Bug: 399354986" or "Reviewed-by: Jane Doe <jane@chromium.org>",
+-- in the order they appear.
+CREATE TABLE IF NOT EXISTS commit_trailers (
+    commit_id BIGINT NOT NULL REFERENCES commits(id) ON DELETE CASCADE,
+    position SMALLINT NOT NULL,
+    key TEXT NOT NULL,
+    value TEXT NOT NULL,
+    PRIMARY KEY (commit_id, position)
+);
+
+CREATE INDEX IF NOT EXISTS idx_commit_trailers_key_value ON commit_trailers(lower(key), value);
+
+-- +goose Down
+DROP TABLE IF EXISTS commit_trailers;
@@ -0,0 +1,28 @@
+-- +goose Up
+-- Identities of the same person, e.g. a work and a personal email, resolve to a canonical author.
+ALTER TABLE authors
+    ADD COLUMN IF NOT EXISTS canonical_id BIGINT REFERENCES authors(id) ON DELETE SET NULL,
+    ADD COLUMN IF NOT EXISTS canonical_source VARCHAR(20);
+
+CREATE INDEX IF NOT EXISTS idx_authors_canonical_id ON authors(canonical_id);
+CREATE INDEX IF NOT EXISTS idx_authors_lower_email ON authors(lower(email));
+
+-- .mailmap rules of repositories, applied to authors stored later too.
+CREATE TABLE IF NOT EXISTS mailmap_entries (
+    id bigserial NOT NULL PRIMARY KEY,
+    repository_id BIGINT NOT NULL REFERENCES repositories(id) ON DELETE CASCADE,
+    proper_name TEXT NOT NULL DEFAULT '',
+    proper_email TEXT NOT NULL DEFAULT '',
+    commit_name TEXT NOT NULL DEFAULT '',
+    commit_email TEXT NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_mailmap_entries_commit_email ON mailmap_entries(lower(commit_email));
+
+-- +goose Down
+DROP TABLE IF EXISTS mailmap_entries;
+DROP INDEX IF EXISTS idx_authors_lower_email;
+ALTER TABLE authors
+    DROP COLUMN IF EXISTS canonical_source,
+    DROP COLUMN IF EXISTS canonical_id;
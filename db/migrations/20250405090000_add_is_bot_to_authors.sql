@@ -0,0 +1,8 @@
+-- +goose Up
+-- Bot and automation accounts, flagged when they are stored. GitHub Apps commit as "name[bot]".
+ALTER TABLE authors ADD COLUMN IF NOT EXISTS is_bot BOOLEAN NOT NULL DEFAULT false;
+
+UPDATE authors SET is_bot = true WHERE name LIKE '%[bot]' OR email LIKE '%[bot]@%';
+
+-- +goose Down
+ALTER TABLE authors DROP COLUMN IF EXISTS is_bot;
@@ -0,0 +1,9 @@
+-- +goose Up
+-- The numeric ID GitHub gives a repository, which survives renames and transfers.
+ALTER TABLE repositories ADD COLUMN IF NOT EXISTS github_id BIGINT;
+
+CREATE INDEX IF NOT EXISTS idx_repositories_host_github_id ON repositories (host, github_id);
+
+-- +goose Down
+DROP INDEX IF EXISTS idx_repositories_host_github_id;
+ALTER TABLE repositories DROP COLUMN IF EXISTS github_id;
@@ -0,0 +1,6 @@
+-- +goose Up
+-- Set when GitHub cannot return the statistics of a commit, so the backfill stops asking for them.
+ALTER TABLE commits ADD COLUMN IF NOT EXISTS stats_unavailable_at TIMESTAMPTZ;
+
+-- +goose Down
+ALTER TABLE commits DROP COLUMN IF EXISTS stats_unavailable_at;
@@ -0,0 +1,9 @@
+-- +goose Up
+-- Watermark of the last complete pull request sync: the latest update it stored.
+ALTER TABLE repositories ADD COLUMN IF NOT EXISTS pull_requests_synced_until TIMESTAMPTZ;
+
+UPDATE repositories r
+SET pull_requests_synced_until = (SELECT MAX(p.updated_at) FROM pull_requests p WHERE p.repository_id = r.id);
+
+-- +goose Down
+ALTER TABLE repositories DROP COLUMN IF EXISTS pull_requests_synced_until;
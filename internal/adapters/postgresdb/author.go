@@ -0,0 +1,242 @@
+package postgresdb
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/babyfaceeasy/lema/internal/domain"
+	"github.com/babyfaceeasy/lema/internal/repositories"
+	"github.com/babyfaceeasy/lema/pkg/mailmap"
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+)
+
+type authorStore struct {
+	db *sqlx.DB
+}
+
+func NewAuthorStore(db *sql.DB) repositories.AuthorRepository {
+	return &authorStore{db: sqlx.NewDb(db, "postgres")}
+}
+
+// ByUID returns the author with uid, or nil if there is none.
+func (s *authorStore) ByUID(ctx context.Context, uid uuid.UUID) (*domain.Author, error) {
+	var author domain.Author
+	err := s.db.GetContext(ctx, &author, `SELECT id, uid, name, email, is_bot FROM authors WHERE uid = $1`, uid)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to fetch author %s: %w", uid, err)
+	}
+	return &author, nil
+}
+
+// GetIdentity returns the canonical author authorID resolves to, with its aliases.
+func (s *authorStore) GetIdentity(ctx context.Context, authorID int) (*domain.AuthorIdentity, error) {
+	identity := domain.AuthorIdentity{Aliases: []domain.Author{}}
+
+	query := `
+		SELECT a.id, a.uid, a.name, a.email, a.is_bot
+		FROM authors x
+		JOIN authors a ON a.id = COALESCE(x.canonical_id, x.id)
+		WHERE x.id = $1
+	`
+	if err := s.db.GetContext(ctx, &identity.Author, query, authorID); err != nil {
+		return nil, fmt.Errorf("failed to fetch identity of author %d: %w", authorID, err)
+	}
+
+	query = `SELECT id, uid, name, email, is_bot FROM authors WHERE canonical_id = $1 ORDER BY id`
+	if err := s.db.SelectContext(ctx, &identity.Aliases, query, identity.ID); err != nil {
+		return nil, fmt.Errorf("failed to fetch aliases of author %d: %w", identity.ID, err)
+	}
+
+	return &identity, nil
+}
+
+// Merge resolves sourceID, and the identities resolved to it, to the canonical author of targetID,
+// and returns the ID of that canonical author. Merging an author into one of its aliases makes
+// the alias canonical.
+func (s *authorStore) Merge(ctx context.Context, sourceID int, targetID int) (int, error) {
+	tx, err := s.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return 0, fmt.Errorf("begin transaction: %w", err)
+	}
+
+	var canonicalID int
+	query := `SELECT COALESCE(canonical_id, id) FROM authors WHERE id = $1`
+	if err := tx.GetContext(ctx, &canonicalID, query, targetID); err != nil {
+		_ = tx.Rollback()
+		return 0, fmt.Errorf("failed to resolve author %d: %w", targetID, err)
+	}
+
+	if canonicalID == sourceID {
+		canonicalID = targetID
+		query = `UPDATE authors SET canonical_id = NULL, canonical_source = $2 WHERE id = $1`
+		if _, err := tx.ExecContext(ctx, query, canonicalID, domain.AuthorSourceManual); err != nil {
+			_ = tx.Rollback()
+			return 0, fmt.Errorf("failed to make author %d canonical: %w", canonicalID, err)
+		}
+	}
+
+	query = `
+		UPDATE authors SET canonical_id = $1, canonical_source = $3
+		WHERE (id = $2 OR canonical_id = $2) AND id <> $1
+	`
+	if _, err := tx.ExecContext(ctx, query, canonicalID, sourceID, domain.AuthorSourceManual); err != nil {
+		_ = tx.Rollback()
+		return 0, fmt.Errorf("failed to merge author %d into %d: %w", sourceID, canonicalID, err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, fmt.Errorf("commit transaction: %w", err)
+	}
+	return canonicalID, nil
+}
+
+// Split makes authorID canonical again, along with its aliases if it has any.
+func (s *authorStore) Split(ctx context.Context, authorID int) error {
+	query := `
+		UPDATE authors SET canonical_id = NULL, canonical_source = $2
+		WHERE id = $1 OR canonical_id = $1
+	`
+	if _, err := s.db.ExecContext(ctx, query, authorID, domain.AuthorSourceManual); err != nil {
+		return fmt.Errorf("failed to split author %d: %w", authorID, err)
+	}
+	return nil
+}
+
+// ReplaceMailmap replaces the .mailmap rules of a repository and applies them to the stored
+// authors, returning how many identities they resolved. Identities merged by rules that are no
+// longer listed stay merged.
+func (s *authorStore) ReplaceMailmap(ctx context.Context, repositoryID int, entries []mailmap.Entry) (int, error) {
+	tx, err := s.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return 0, fmt.Errorf("begin transaction: %w", err)
+	}
+
+	if _, err := tx.ExecContext(ctx, `DELETE FROM mailmap_entries WHERE repository_id = $1`, repositoryID); err != nil {
+		_ = tx.Rollback()
+		return 0, fmt.Errorf("failed to delete mailmap entries: %w", err)
+	}
+
+	aliases := 0
+	for _, entry := range entries {
+		query := `
+			INSERT INTO mailmap_entries (repository_id, proper_name, proper_email, commit_name, commit_email)
+			VALUES ($1, $2, $3, $4, $5)
+		`
+		if _, err := tx.ExecContext(ctx, query, repositoryID, entry.ProperName, entry.ProperEmail, entry.CommitName, entry.CommitEmail); err != nil {
+			_ = tx.Rollback()
+			return 0, fmt.Errorf("failed to insert mailmap entry for %s: %w", entry.CommitEmail, err)
+		}
+
+		count, err := applyMailmapEntry(ctx, tx, entry)
+		if err != nil {
+			_ = tx.Rollback()
+			return 0, err
+		}
+		aliases += count
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, fmt.Errorf("commit transaction: %w", err)
+	}
+	return aliases, nil
+}
+
+type mailmapEntryRow struct {
+	ProperName  string `db:"proper_name"`
+	ProperEmail string `db:"proper_email"`
+	CommitName  string `db:"commit_name"`
+	CommitEmail string `db:"commit_email"`
+}
+
+// applyStoredMailmap applies the stored .mailmap rules matching email, e.g. to a new author.
+func applyStoredMailmap(ctx context.Context, tx *sqlx.Tx, email string) error {
+	var rows []mailmapEntryRow
+	query := `
+		SELECT proper_name, proper_email, commit_name, commit_email
+		FROM mailmap_entries
+		WHERE lower(commit_email) = lower($1)
+		ORDER BY id
+	`
+	if err := tx.SelectContext(ctx, &rows, query, email); err != nil {
+		return fmt.Errorf("failed to fetch mailmap entries for %s: %w", email, err)
+	}
+
+	for _, row := range rows {
+		if _, err := applyMailmapEntry(ctx, tx, mailmap.Entry(row)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// applyMailmapEntry resolves the authors matching entry to the author with its proper email,
+// creating it if needed, and returns how many were. Emails are compared case-insensitively and
+// names exactly, as git does. Identities merged or split by hand are left alone.
+func applyMailmapEntry(ctx context.Context, tx *sqlx.Tx, entry mailmap.Entry) (int, error) {
+	var matches []domain.Author
+	query := `
+		SELECT id, uid, name, email FROM authors
+		WHERE lower(email) = lower($1) AND ($2 = '' OR name = $2) AND canonical_source IS DISTINCT FROM $3
+		ORDER BY id
+	`
+	if err := tx.SelectContext(ctx, &matches, query, entry.CommitEmail, entry.CommitName, domain.AuthorSourceManual); err != nil {
+		return 0, fmt.Errorf("failed to match authors of %s: %w", entry.CommitEmail, err)
+	}
+	if len(matches) == 0 {
+		return 0, nil
+	}
+
+	properEmail := entry.ProperEmail
+	if properEmail == "" {
+		properEmail = entry.CommitEmail
+	}
+
+	var canonicalID int
+	query = `SELECT COALESCE(canonical_id, id) FROM authors WHERE lower(email) = lower($1) ORDER BY id LIMIT 1`
+	err := tx.GetContext(ctx, &canonicalID, query, properEmail)
+	if errors.Is(err, sql.ErrNoRows) {
+		name := entry.ProperName
+		if name == "" {
+			name = matches[0].Name
+		}
+		query = `INSERT INTO authors (uid, name, email) VALUES ($1, $2, $3) RETURNING id`
+		err = tx.GetContext(ctx, &canonicalID, query, uuid.New(), name, properEmail)
+	}
+	if err != nil {
+		return 0, fmt.Errorf("failed to find or create author %s: %w", properEmail, err)
+	}
+
+	if entry.ProperName != "" {
+		query = `UPDATE authors SET name = $2 WHERE id = $1`
+		if _, err := tx.ExecContext(ctx, query, canonicalID, entry.ProperName); err != nil {
+			return 0, fmt.Errorf("failed to rename author %d: %w", canonicalID, err)
+		}
+	}
+
+	ids := make([]int64, 0, len(matches))
+	for _, match := range matches {
+		if match.ID != canonicalID {
+			ids = append(ids, int64(match.ID))
+		}
+	}
+	if len(ids) == 0 {
+		return 0, nil
+	}
+
+	query = `
+		UPDATE authors
+		SET canonical_id = $1, canonical_source = CASE WHEN id = ANY($2) THEN $3 ELSE canonical_source END
+		WHERE (id = ANY($2) OR canonical_id = ANY($2)) AND id <> $1
+	`
+	if _, err := tx.ExecContext(ctx, query, canonicalID, pq.Array(ids), domain.AuthorSourceMailmap); err != nil {
+		return 0, fmt.Errorf("failed to resolve authors of %s: %w", entry.CommitEmail, err)
+	}
+	return len(ids), nil
+}
@@ -0,0 +1,93 @@
+package postgresdb
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/babyfaceeasy/lema/internal/domain"
+	"github.com/babyfaceeasy/lema/internal/repositories"
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+)
+
+type branchStore struct {
+	db *sqlx.DB
+}
+
+func NewBranchStore(db *sql.DB) repositories.BranchRepository {
+	return &branchStore{db: sqlx.NewDb(db, "postgres")}
+}
+
+// Create starts monitoring a branch. Creating a branch that is already monitored is a no-op.
+func (s *branchStore) Create(ctx context.Context, branch domain.Branch) error {
+	if branch.UID == uuid.Nil {
+		branch.UID = uuid.New()
+	}
+	if branch.CreatedAt.IsZero() {
+		branch.CreatedAt = time.Now()
+	}
+
+	query := `
+		INSERT INTO repository_branches (uid, repository_id, name, since_date, created_at)
+		VALUES (:uid, :repository_id, :name, :since_date, :created_at)
+		ON CONFLICT (repository_id, name) DO NOTHING
+	`
+	if _, err := s.db.NamedExecContext(ctx, query, branch); err != nil {
+		return fmt.Errorf("failed to create branch %s: %w", branch.Name, err)
+	}
+	return nil
+}
+
+// ByRepositoryID returns the monitored branches of a repository.
+func (s *branchStore) ByRepositoryID(ctx context.Context, repositoryID int) ([]domain.Branch, error) {
+	const query = `
+		SELECT id, uid, repository_id, name, since_date, created_at
+		FROM repository_branches
+		WHERE repository_id = $1
+		ORDER BY name
+	`
+
+	var branches []domain.Branch
+	if err := s.db.SelectContext(ctx, &branches, query, repositoryID); err != nil {
+		return nil, fmt.Errorf("failed to fetch branches of repository %d: %w", repositoryID, err)
+	}
+	return branches, nil
+}
+
+// UpdateSinceDate moves the sync watermark of a branch.
+func (s *branchStore) UpdateSinceDate(ctx context.Context, branchID int, newSinceDate time.Time) error {
+	query := `UPDATE repository_branches SET since_date = $1 WHERE id = $2`
+	if _, err := s.db.ExecContext(ctx, query, newSinceDate, branchID); err != nil {
+		return fmt.Errorf("failed to update since_date for branch %d: %w", branchID, err)
+	}
+	return nil
+}
+
+// ResetSinceDates clears the watermarks of every branch of a repository so the next sync
+// fetches their whole history again.
+func (s *branchStore) ResetSinceDates(ctx context.Context, repositoryID int) error {
+	query := `UPDATE repository_branches SET since_date = NULL WHERE repository_id = $1`
+	if _, err := s.db.ExecContext(ctx, query, repositoryID); err != nil {
+		return fmt.Errorf("failed to reset branches of repository %d: %w", repositoryID, err)
+	}
+	return nil
+}
+
+// AddUnbranchedCommits records every commit of a repository that belongs to no monitored branch
+// as reachable from branchID. Such commits were stored before the repository's default branch
+// was known, by syncs that always walk the default branch.
+func (s *branchStore) AddUnbranchedCommits(ctx context.Context, branchID int, repositoryID int) error {
+	query := `
+		INSERT INTO commit_branches (commit_id, branch_id)
+		SELECT c.id, $1 FROM commits c
+		WHERE c.repository_id = $2
+			AND NOT EXISTS (SELECT 1 FROM commit_branches cb WHERE cb.commit_id = c.id)
+		ON CONFLICT DO NOTHING
+	`
+	if _, err := s.db.ExecContext(ctx, query, branchID, repositoryID); err != nil {
+		return fmt.Errorf("failed to add unbranched commits of repository %d to branch %d: %w", repositoryID, branchID, err)
+	}
+	return nil
+}
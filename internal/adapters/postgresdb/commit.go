@@ -3,33 +3,47 @@ package postgresdb
 import (
 	"context"
 	"database/sql"
+	"errors"
 	"fmt"
+	"strings"
 	"time"
 
 	"github.com/babyfaceeasy/lema/internal/domain"
 	"github.com/babyfaceeasy/lema/internal/repositories"
+	"github.com/babyfaceeasy/lema/pkg/botdetect"
+	"github.com/babyfaceeasy/lema/pkg/commitmsg"
 	"github.com/babyfaceeasy/lema/pkg/pagination"
 	"github.com/google/uuid"
 	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
 )
 
 type commitStore struct {
-	db *sqlx.DB
+	db   *sqlx.DB
+	bots *botdetect.Classifier
 }
 
-func NewCommitStore(db *sql.DB) repositories.CommitRepository {
-	return &commitStore{db: sqlx.NewDb(db, "postgres")}
+// NewCommitStore creates a CommitRepository flagging the authors bots classifies as bots when
+// they are first stored.
+func NewCommitStore(db *sql.DB, bots *botdetect.Classifier) repositories.CommitRepository {
+	return &commitStore{db: sqlx.NewDb(db, "postgres"), bots: bots}
 }
 
-// getOrCreateRepository checks if a repository exists based on its Name Field.
+// getOrCreateRepository checks if a repository exists based on its Host, OwnerName and Name Fields.
 func (s *commitStore) getOrCreateRepository(ctx context.Context, tx *sqlx.Tx, repo *domain.Repository) (int, error) {
 	if repo.UID == uuid.Nil {
 		repo.UID = uuid.New()
 	}
+	if repo.Host == "" {
+		repo.Host = domain.HostFromContext(ctx)
+	}
+	if repo.Provider == "" {
+		repo.Provider = domain.ProviderGithub
+	}
 
 	var id int
-	query := `SELECT id FROM repositories WHERE name = $1 LIMIT 1`
-	err := tx.GetContext(ctx, &id, query, repo.Name)
+	query := `SELECT id FROM repositories WHERE host = $1 AND name = $2 AND owner_name = $3 LIMIT 1`
+	err := tx.GetContext(ctx, &id, query, repo.Host, repo.Name, repo.OwnerName)
 	if err != nil {
 		if err == sql.ErrNoRows {
 			now := time.Now()
@@ -42,9 +56,9 @@ func (s *commitStore) getOrCreateRepository(ctx context.Context, tx *sqlx.Tx, re
 
 			insertQuery := `
 				INSERT INTO repositories 
-				(uid, name, owner_name, description, url, programming_language, forks_count, stars_count, watchers_count, open_issues_count, since_date, created_at, until_date)
+				(uid, host, provider, name, owner_name, description, url, programming_language, forks_count, stars_count, watchers_count, open_issues_count, since_date, created_at, until_date)
 				VALUES 
-				(:uid, :name, :owner_name, :description, :url, :programming_language, :forks_count, :stars_count, :watchers_count, :open_issues_count, :since_date, :created_at, :until_date)
+				(:uid, :host, :provider, :name, :owner_name, :description, :url, :programming_language, :forks_count, :stars_count, :watchers_count, :open_issues_count, :since_date, :created_at, :until_date)
 				RETURNING id
 			`
 			stmt, err := tx.PrepareNamedContext(ctx, insertQuery)
@@ -63,7 +77,9 @@ func (s *commitStore) getOrCreateRepository(ctx context.Context, tx *sqlx.Tx, re
 	return id, nil
 }
 
-// getOrCreateAuthor checks if an author exists based on its Name Field.
+// getOrCreateAuthor checks if an author exists based on its Email Field. New authors are resolved
+// by the stored .mailmap rules and flagged as bots when classified as such. Existing authors are
+// only flagged when the provider reports a bot.
 func (s *commitStore) getOrCreateAuthor(ctx context.Context, tx *sqlx.Tx, author *domain.Author) (int, error) {
 	if author.UID == uuid.Nil {
 		author.UID = uuid.New()
@@ -72,13 +88,19 @@ func (s *commitStore) getOrCreateAuthor(ctx context.Context, tx *sqlx.Tx, author
 	var id int
 	query := `SELECT id FROM authors WHERE email = $1 LIMIT 1`
 	err := tx.GetContext(ctx, &id, query, author.Email)
+	if err == nil && author.IsBot {
+		if _, err := tx.ExecContext(ctx, `UPDATE authors SET is_bot = true WHERE id = $1 AND NOT is_bot`, id); err != nil {
+			return 0, fmt.Errorf("failed to flag author as bot: %w", err)
+		}
+	}
 	if err != nil {
 		if err == sql.ErrNoRows {
+			author.IsBot = author.IsBot || s.bots.IsBot(author.Name, author.Email)
 			insertQuery := `
 				INSERT INTO authors 
-				(uid, name, email)
+				(uid, name, email, is_bot)
 				VALUES 
-				(:uid, :name, :email)
+				(:uid, :name, :email, :is_bot)
 				RETURNING id
 			`
 
@@ -91,6 +113,9 @@ func (s *commitStore) getOrCreateAuthor(ctx context.Context, tx *sqlx.Tx, author
 			if err != nil {
 				return 0, fmt.Errorf("failed to insert author: %w", err)
 			}
+			if err := applyStoredMailmap(ctx, tx, author.Email); err != nil {
+				return 0, err
+			}
 		} else {
 			return 0, fmt.Errorf("failed to check author existence: %w", err)
 		}
@@ -98,6 +123,174 @@ func (s *commitStore) getOrCreateAuthor(ctx context.Context, tx *sqlx.Tx, author
 	return id, nil
 }
 
+// setCommitter resolves the committer of a commit to an author row. Commits without a known
+// committer are attributed to their author.
+func (s *commitStore) setCommitter(ctx context.Context, tx *sqlx.Tx, commit *domain.Commit) error {
+	if commit.CommitterDate.IsZero() {
+		commit.CommitterDate = commit.CommitDate
+	}
+
+	if commit.Committer == nil || commit.Committer.Email == "" {
+		commit.CommitterID = commit.AuthorID
+		return nil
+	}
+
+	committerID, err := s.getOrCreateAuthor(ctx, tx, commit.Committer)
+	if err != nil {
+		return err
+	}
+	commit.CommitterID = committerID
+	return nil
+}
+
+// addToBranch records that a stored commit is reachable from its monitored branch.
+func (s *commitStore) addToBranch(ctx context.Context, tx *sqlx.Tx, commit *domain.Commit) error {
+	if commit.BranchID == 0 {
+		return nil
+	}
+
+	query := `
+		INSERT INTO commit_branches (commit_id, branch_id)
+		SELECT id, $1 FROM commits WHERE repository_id = $2 AND sha = $3
+		ON CONFLICT DO NOTHING
+	`
+	if _, err := tx.ExecContext(ctx, query, commit.BranchID, commit.RepositoryID, commit.SHA); err != nil {
+		return fmt.Errorf("adding commit %s to branch %d: %w", commit.SHA, commit.BranchID, err)
+	}
+	return nil
+}
+
+// addIssueReferences records the issues the message of a stored commit says it closes.
+func (s *commitStore) addIssueReferences(ctx context.Context, tx *sqlx.Tx, commit *domain.Commit) error {
+	refs := commitmsg.IssueReferences(commit.Message, commit.Repository.OwnerName, commit.Repository.Name)
+	if len(refs) == 0 {
+		return nil
+	}
+
+	query := `
+		INSERT INTO commit_issue_references (commit_id, owner_name, repository_name, issue_number, keyword)
+		SELECT id, $1, $2, $3, $4 FROM commits WHERE repository_id = $5 AND sha = $6
+		ON CONFLICT DO NOTHING
+	`
+	for _, ref := range refs {
+		if _, err := tx.ExecContext(ctx, query, ref.Owner, ref.Repository, ref.Number, ref.Keyword, commit.RepositoryID, commit.SHA); err != nil {
+			return fmt.Errorf("adding issue reference %s/%s#%d of commit %s: %w", ref.Owner, ref.Repository, ref.Number, commit.SHA, err)
+		}
+	}
+	return nil
+}
+
+// addReview records the code review of a stored commit, whose ID is set, replacing its reviewers.
+func (s *commitStore) addReview(ctx context.Context, tx *sqlx.Tx, commit *domain.Commit) error {
+	review := commit.Review
+	if review == nil {
+		return nil
+	}
+	commitID := commit.ID
+
+	query := `
+		INSERT INTO commit_reviews (commit_id, change_number, change_id, url, owner_account_id, owner_name, owner_email, opened_at, submitted_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+		ON CONFLICT (commit_id) DO UPDATE SET
+			change_number = EXCLUDED.change_number,
+			change_id = EXCLUDED.change_id,
+			url = EXCLUDED.url,
+			owner_account_id = EXCLUDED.owner_account_id,
+			owner_name = EXCLUDED.owner_name,
+			owner_email = EXCLUDED.owner_email,
+			opened_at = EXCLUDED.opened_at,
+			submitted_at = EXCLUDED.submitted_at
+	`
+	if _, err := tx.ExecContext(ctx, query, commitID, review.ChangeNumber, review.ChangeID, review.URL,
+		review.Owner.AccountID, review.Owner.Name, review.Owner.Email, review.OpenedAt, review.SubmittedAt); err != nil {
+		return fmt.Errorf("storing review of commit %s: %w", commit.SHA, err)
+	}
+
+	if _, err := tx.ExecContext(ctx, `DELETE FROM commit_reviewers WHERE commit_id = $1`, commitID); err != nil {
+		return fmt.Errorf("clearing reviewers of commit %s: %w", commit.SHA, err)
+	}
+	for _, reviewer := range review.Reviewers {
+		if _, err := tx.ExecContext(ctx, `
+			INSERT INTO commit_reviewers (commit_id, account_id, name, email, code_review)
+			VALUES ($1, $2, $3, $4, $5)
+			ON CONFLICT DO NOTHING
+		`, commitID, reviewer.AccountID, reviewer.Name, reviewer.Email, reviewer.CodeReview); err != nil {
+			return fmt.Errorf("storing reviewer %d of commit %s: %w", reviewer.AccountID, commit.SHA, err)
+		}
+	}
+	return nil
+}
+
+// addAuthors credits a stored commit, whose ID is set, to its author, its committer and the
+// co-authors named in its message.
+func (s *commitStore) addAuthors(ctx context.Context, tx *sqlx.Tx, commit *domain.Commit) error {
+	if err := s.linkAuthors(ctx, tx, commit.ID, commit.AuthorID, commit.CommitterID, commit.Message); err != nil {
+		return fmt.Errorf("crediting authors of commit %s: %w", commit.SHA, err)
+	}
+	return nil
+}
+
+// linkAuthors replaces the people credited with the commit commitID. Authors naming themselves as
+// co-authors are only credited as authors.
+func (s *commitStore) linkAuthors(ctx context.Context, tx *sqlx.Tx, commitID, authorID, committerID int, message string) error {
+	if _, err := tx.ExecContext(ctx, `DELETE FROM commit_authors WHERE commit_id = $1`, commitID); err != nil {
+		return err
+	}
+
+	query := `
+		INSERT INTO commit_authors (commit_id, author_id, role)
+		VALUES ($1, $2, $3)
+		ON CONFLICT DO NOTHING
+	`
+	if _, err := tx.ExecContext(ctx, query, commitID, authorID, domain.CommitAuthorRoleAuthor); err != nil {
+		return err
+	}
+	if committerID != 0 {
+		if _, err := tx.ExecContext(ctx, query, commitID, committerID, domain.CommitAuthorRoleCommitter); err != nil {
+			return err
+		}
+	}
+
+	for _, person := range commitmsg.CoAuthors(message) {
+		coAuthorID, err := s.getOrCreateAuthor(ctx, tx, &domain.Author{Name: person.Name, Email: person.Email})
+		if err != nil {
+			return err
+		}
+		if coAuthorID == authorID {
+			continue
+		}
+		if _, err := tx.ExecContext(ctx, query, commitID, coAuthorID, domain.CommitAuthorRoleCoAuthor); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// addTrailers records the trailers of the message of a stored commit, whose ID is set, replacing
+// those recorded before.
+func (s *commitStore) addTrailers(ctx context.Context, tx *sqlx.Tx, commit *domain.Commit) error {
+	if err := s.storeTrailers(ctx, tx, commit.ID, commit.Message); err != nil {
+		return fmt.Errorf("storing trailers of commit %s: %w", commit.SHA, err)
+	}
+	return nil
+}
+
+func (s *commitStore) storeTrailers(ctx context.Context, tx *sqlx.Tx, commitID int, message string) error {
+	if _, err := tx.ExecContext(ctx, `DELETE FROM commit_trailers WHERE commit_id = $1`, commitID); err != nil {
+		return err
+	}
+
+	for i, trailer := range commitmsg.Trailers(message) {
+		if _, err := tx.ExecContext(ctx, `
+			INSERT INTO commit_trailers (commit_id, position, key, value)
+			VALUES ($1, $2, $3, $4)
+		`, commitID, i, trailer.Key, trailer.Value); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 // StoreCommits inserts a list of commits into the database.
 func (s *commitStore) StoreCommits(ctx context.Context, commits []domain.Commit) error {
 	if len(commits) == 0 {
@@ -111,10 +304,18 @@ func (s *commitStore) StoreCommits(ctx context.Context, commits []domain.Commit)
 
 	commitQuery := `
         INSERT INTO commits 
-            (uid, repository_id, author_id, url, sha, message, commit_date, created_at)
+            (uid, repository_id, author_id, committer_id, url, sha, message, commit_date, committer_date, created_at, additions, deletions, changed_files, stats_fetched_at)
         VALUES 
-            (:uid, :repository_id, :author_id, :url, :sha, :message, :commit_date, :created_at)
+            (:uid, :repository_id, :author_id, :committer_id, :url, :sha, :message, :commit_date, :committer_date, :created_at, :additions, :deletions, :changed_files, :stats_fetched_at)
+        RETURNING id
     `
+	commitStmt, err := tx.PrepareNamedContext(ctx, commitQuery)
+	if err != nil {
+		_ = tx.Rollback()
+		return fmt.Errorf("preparing commit insert: %w", err)
+	}
+	defer commitStmt.Close()
+
 	for _, commit := range commits {
 		repoID, err := s.getOrCreateRepository(ctx, tx, &commit.Repository)
 		if err != nil {
@@ -130,6 +331,11 @@ func (s *commitStore) StoreCommits(ctx context.Context, commits []domain.Commit)
 		}
 		commit.AuthorID = authorID
 
+		if err := s.setCommitter(ctx, tx, &commit); err != nil {
+			_ = tx.Rollback()
+			return fmt.Errorf("processing commit insert committer %v: %w", commit.URL, err)
+		}
+
 		// Set default values.
 		if commit.UID == uuid.Nil {
 			commit.UID = uuid.New()
@@ -139,35 +345,117 @@ func (s *commitStore) StoreCommits(ctx context.Context, commits []domain.Commit)
 			commit.CreatedAt = now
 		}
 
-		_, err = tx.NamedExecContext(ctx, commitQuery, commit)
-		if err != nil {
+		if err := commitStmt.GetContext(ctx, &commit.ID, commit); err != nil {
 			_ = tx.Rollback()
 			return fmt.Errorf("inserting commit %v: %w", commit.URL, err)
 		}
+
+		if err := s.addToBranch(ctx, tx, &commit); err != nil {
+			_ = tx.Rollback()
+			return err
+		}
+
+		if err := s.addIssueReferences(ctx, tx, &commit); err != nil {
+			_ = tx.Rollback()
+			return err
+		}
+
+		if err := s.addReview(ctx, tx, &commit); err != nil {
+			_ = tx.Rollback()
+			return err
+		}
+
+		if err := s.addAuthors(ctx, tx, &commit); err != nil {
+			_ = tx.Rollback()
+			return err
+		}
+
+		if err := s.addTrailers(ctx, tx, &commit); err != nil {
+			_ = tx.Rollback()
+			return err
+		}
 	}
 
 	return tx.Commit()
 }
 
-// GetCommitsByRepositoryName returns all commits for the repository with the given name.
-func (s *commitStore) GetCommitsByRepositoryName(ctx context.Context, ownerName, repositoryName string, page, pageSize int) ([]domain.Commit, int, error) {
+// commitConditions returns the date column filter applies to, and the conditions, with their
+// arguments, keeping the commits c of repository r matching filter.
+func commitConditions(ctx context.Context, ownerName, repositoryName string, filter domain.CommitFilter) (string, string, []any) {
+	dateColumn := "c.commit_date"
+	if filter.DateField == domain.CommitDateCommitter {
+		dateColumn = "COALESCE(c.committer_date, c.commit_date)"
+	}
+
+	conditions := []string{"r.name = $1", "r.owner_name = $2", "r.host = $3"}
+	args := []any{repositoryName, ownerName, domain.HostFromContext(ctx)}
+	if filter.Since != nil {
+		args = append(args, *filter.Since)
+		conditions = append(conditions, fmt.Sprintf("%s >= $%d", dateColumn, len(args)))
+	}
+	if filter.Until != nil {
+		args = append(args, *filter.Until)
+		conditions = append(conditions, fmt.Sprintf("%s <= $%d", dateColumn, len(args)))
+	}
+	if filter.Branch != "" {
+		args = append(args, filter.Branch)
+		conditions = append(conditions, fmt.Sprintf(`EXISTS (
+		SELECT 1 FROM commit_branches cb
+		JOIN repository_branches b ON b.id = cb.branch_id
+		WHERE cb.commit_id = c.id AND b.name = $%d)`, len(args)))
+	}
+	if filter.Issue != 0 {
+		args = append(args, filter.Issue)
+		conditions = append(conditions, fmt.Sprintf(`EXISTS (
+		SELECT 1 FROM commit_issue_references ir
+		WHERE ir.commit_id = c.id AND lower(ir.owner_name) = lower(r.owner_name)
+			AND lower(ir.repository_name) = lower(r.name) AND ir.issue_number = $%d)`, len(args)))
+	}
+	if filter.TrailerKey != "" {
+		args = append(args, filter.TrailerKey)
+		trailer := fmt.Sprintf("t.commit_id = c.id AND lower(t.key) = lower($%d)", len(args))
+		if filter.TrailerValue != "" {
+			args = append(args, filter.TrailerValue)
+			trailer += fmt.Sprintf(" AND t.value = $%d", len(args))
+		}
+		conditions = append(conditions, "EXISTS (SELECT 1 FROM commit_trailers t WHERE "+trailer+")")
+	}
+	if isBot := isBotArg(filter.Bots); isBot != nil {
+		args = append(args, *isBot)
+		conditions = append(conditions, fmt.Sprintf("c.author_id IN (SELECT id FROM authors WHERE is_bot = $%d)", len(args)))
+	}
+	return dateColumn, strings.Join(conditions, " AND "), args
+}
+
+// GetCommitsByRepositoryName returns the commits for the repository with the given name matching filter.
+func (s *commitStore) GetCommitsByRepositoryName(ctx context.Context, ownerName, repositoryName string, filter domain.CommitFilter, page, pageSize int) ([]domain.Commit, int, error) {
 	var commits []domain.Commit
 
+	dateColumn, where, args := commitConditions(ctx, ownerName, repositoryName, filter)
+
 	query := `
 	SELECT 
 		c.id,
 		c.uid,
 		c.repository_id,
 		c.author_id,
+		COALESCE(c.committer_id, c.author_id) AS committer_id,
 		c.url,
 		c.sha,
 		c.message,
 		c.commit_date,
+		COALESCE(c.committer_date, c.commit_date) AS committer_date,
 		c.created_at,
+		c.additions,
+		c.deletions,
+		c.changed_files,
+		c.stats_fetched_at,
 		-- c.updated_at,
 		-- Repository fields with "Repository." prefix
 		r.id AS "Repository.id",
 		r.uid AS "Repository.uid",
+		r.host AS "Repository.host",
+		r.provider AS "Repository.provider",
 		r.name AS "Repository.name",
 		r.owner_name AS "Repository.owner_name",
 		r.description AS "Repository.description",
@@ -185,36 +473,94 @@ func (s *commitStore) GetCommitsByRepositoryName(ctx context.Context, ownerName,
 		a.id AS "Author.id",
 		a.uid AS "Author.uid",
 		a.name AS "Author.name",
-		a.email AS "Author.email"
+		a.email AS "Author.email",
+		a.is_bot AS "Author.is_bot",
+		-- Committer fields with "Committer." prefix, falling back to the author
+		COALESCE(ca.id, a.id) AS "Committer.id",
+		COALESCE(ca.uid, a.uid) AS "Committer.uid",
+		COALESCE(ca.name, a.name) AS "Committer.name",
+		COALESCE(ca.email, a.email) AS "Committer.email",
+		COALESCE(ca.is_bot, a.is_bot) AS "Committer.is_bot"
 	FROM commits c
 	JOIN repositories r ON c.repository_id = r.id
 	JOIN authors a ON c.author_id = a.id
-	WHERE r.name = $1 AND r.owner_name = $2
-	ORDER BY c.commit_date DESC
+	LEFT JOIN authors ca ON c.committer_id = ca.id
+	WHERE ` + where + `
+	ORDER BY ` + dateColumn + ` DESC
 	`
 
 	paginatedQuery := pagination.ApplyToQuery(query, page, pageSize)
 
-	err := s.db.SelectContext(ctx, &commits, paginatedQuery, repositoryName, ownerName)
+	err := s.db.SelectContext(ctx, &commits, paginatedQuery, args...)
 	if err != nil {
 		return nil, 0, fmt.Errorf("failed to fetch commits for repository %s: %w", repositoryName, err)
 	}
 
 	// total Items query
 	var totalItems int
-	countQuery := `SELECT COUNT(*) FROM commits
-                   JOIN repositories ON commits.repository_id = repositories.id
-                   WHERE repositories.name = $1 AND repositories.owner_name = $2`
-	if err := s.db.GetContext(ctx, &totalItems, countQuery, repositoryName, ownerName); err != nil {
+	countQuery := `SELECT COUNT(*) FROM commits c
+                   JOIN repositories r ON c.repository_id = r.id
+                   WHERE ` + where
+	if err := s.db.GetContext(ctx, &totalItems, countQuery, args...); err != nil {
 		return nil, 0, fmt.Errorf("failed to count total commits: %w", err)
 	}
 
 	return commits, totalItems, nil
 }
 
-// GetTopCommitAuthors returns the top N commit authors by commit count.
-func (s *commitStore) GetTopCommitAuthors(ctx context.Context, limit int) ([]domain.CommitAuthor, error) {
+// isBotArg turns a filter on bots into the is_bot value to keep, NULL keeping both.
+func isBotArg(bots string) *bool {
+	if bots == "" {
+		return nil
+	}
+	isBot := bots == domain.BotsOnly
+	return &isBot
+}
+
+// GetTopCommitAuthors returns the top N commit authors by commit count, counting the commits of
+// aliases for their canonical author. With filter.IncludeCoAuthors, co-authored commits count for
+// their co-authors too, as recorded in commit_authors. filter.Bots applies to the identity
+// credited with each commit.
+func (s *commitStore) GetTopCommitAuthors(ctx context.Context, filter domain.AuthorFilter, limit int) ([]domain.CommitAuthor, error) {
 	var authors []domain.CommitAuthor
+	isBot := isBotArg(filter.Bots)
+
+	if filter.IncludeCoAuthors {
+		// A commit credits a person once, even when several of their identities are credited.
+		query := `
+			WITH credits AS (
+				SELECT
+					ca.commit_id,
+					COALESCE(x.canonical_id, x.id) AS author_id,
+					bool_and(ca.role = $2) AS co_authored
+				FROM commit_authors ca
+				JOIN authors x ON x.id = ca.author_id
+				WHERE ca.role IN ($2, $3) AND ($4::boolean IS NULL OR x.is_bot = $4)
+				GROUP BY ca.commit_id, COALESCE(x.canonical_id, x.id)
+			)
+			SELECT
+				a.id,
+				a.uid,
+				a.name,
+				a.email,
+				a.is_bot,
+				COUNT(c.id) AS commit_count,
+				COUNT(c.id) FILTER (WHERE cr.co_authored) AS co_authored_count,
+				COALESCE(SUM(c.additions), 0) AS lines_added,
+				COALESCE(SUM(c.deletions), 0) AS lines_removed
+			FROM credits cr
+			JOIN authors a ON a.id = cr.author_id
+			JOIN commits c ON c.id = cr.commit_id
+			GROUP BY a.id, a.uid, a.name, a.email, a.is_bot
+			ORDER BY commit_count DESC
+			LIMIT $1
+		`
+
+		if err := s.db.SelectContext(ctx, &authors, query, limit, domain.CommitAuthorRoleCoAuthor, domain.CommitAuthorRoleAuthor, isBot); err != nil {
+			return nil, fmt.Errorf("failed to fetch top commit authors: %w", err)
+		}
+		return authors, nil
+	}
 
 	query := `
 		SELECT 
@@ -222,15 +568,20 @@ func (s *commitStore) GetTopCommitAuthors(ctx context.Context, limit int) ([]dom
 			a.uid,
 			a.name,
 			a.email,
-			COUNT(c.id) AS commit_count
-		FROM authors a
-		JOIN commits c ON c.author_id = a.id
-		GROUP BY a.id, a.uid, a.name, a.email
+			a.is_bot,
+			COUNT(c.id) AS commit_count,
+			COALESCE(SUM(c.additions), 0) AS lines_added,
+			COALESCE(SUM(c.deletions), 0) AS lines_removed
+		FROM commits c
+		JOIN authors x ON x.id = c.author_id
+		JOIN authors a ON a.id = COALESCE(x.canonical_id, x.id)
+		WHERE $2::boolean IS NULL OR x.is_bot = $2
+		GROUP BY a.id, a.uid, a.name, a.email, a.is_bot
 		ORDER BY commit_count DESC
 		LIMIT $1
 	`
 
-	if err := s.db.SelectContext(ctx, &authors, query, limit); err != nil {
+	if err := s.db.SelectContext(ctx, &authors, query, limit, isBot); err != nil {
 		return nil, fmt.Errorf("failed to fetch top commit authors: %w", err)
 	}
 
@@ -246,14 +597,28 @@ func (s *commitStore) UpsertCommits(ctx context.Context, commits []domain.Commit
 
 	query := `
 		INSERT INTO commits 
-			(uid, repository_id, author_id, sha, url, message, commit_date, created_at)
+			(uid, repository_id, author_id, committer_id, sha, url, message, commit_date, committer_date, created_at, additions, deletions, changed_files, stats_fetched_at)
 		VALUES 
-			(:uid, :repository_id, :author_id, :sha, :url, :message, :commit_date, :created_at)
+			(:uid, :repository_id, :author_id, :committer_id, :sha, :url, :message, :commit_date, :committer_date, :created_at, :additions, :deletions, :changed_files, :stats_fetched_at)
 		ON CONFLICT (repository_id, sha) DO UPDATE SET 
 			url = EXCLUDED.url,
 			message = EXCLUDED.message,
-			date = EXCLUDED.date
+			commit_date = EXCLUDED.commit_date,
+			committer_id = EXCLUDED.committer_id,
+			committer_date = EXCLUDED.committer_date,
+			-- keep previously fetched statistics when the new row has none
+			additions = CASE WHEN EXCLUDED.stats_fetched_at IS NULL THEN commits.additions ELSE EXCLUDED.additions END,
+			deletions = CASE WHEN EXCLUDED.stats_fetched_at IS NULL THEN commits.deletions ELSE EXCLUDED.deletions END,
+			changed_files = CASE WHEN EXCLUDED.stats_fetched_at IS NULL THEN commits.changed_files ELSE EXCLUDED.changed_files END,
+			stats_fetched_at = COALESCE(EXCLUDED.stats_fetched_at, commits.stats_fetched_at)
+		RETURNING id
 	`
+	stmt, err := tx.PrepareNamedContext(ctx, query)
+	if err != nil {
+		_ = tx.Rollback()
+		return fmt.Errorf("preparing commit upsert: %w", err)
+	}
+	defer stmt.Close()
 
 	for _, commit := range commits {
 		if commit.RepositoryID == 0 {
@@ -274,6 +639,13 @@ func (s *commitStore) UpsertCommits(ctx context.Context, commits []domain.Commit
 			commit.AuthorID = authorID
 		}
 
+		if commit.CommitterID == 0 {
+			if err := s.setCommitter(ctx, tx, &commit); err != nil {
+				_ = tx.Rollback()
+				return fmt.Errorf("upserting commit %s: %w", commit.SHA, err)
+			}
+		}
+
 		if commit.UID == uuid.Nil {
 			commit.UID = uuid.New()
 		}
@@ -282,10 +654,35 @@ func (s *commitStore) UpsertCommits(ctx context.Context, commits []domain.Commit
 			commit.CreatedAt = time.Now()
 		}
 
-		if _, err := tx.NamedExecContext(ctx, query, commit); err != nil {
+		if err := stmt.GetContext(ctx, &commit.ID, commit); err != nil {
 			_ = tx.Rollback()
 			return fmt.Errorf("upserting commit %s: %w", commit.SHA, err)
 		}
+
+		if err := s.addToBranch(ctx, tx, &commit); err != nil {
+			_ = tx.Rollback()
+			return err
+		}
+
+		if err := s.addIssueReferences(ctx, tx, &commit); err != nil {
+			_ = tx.Rollback()
+			return err
+		}
+
+		if err := s.addReview(ctx, tx, &commit); err != nil {
+			_ = tx.Rollback()
+			return err
+		}
+
+		if err := s.addAuthors(ctx, tx, &commit); err != nil {
+			_ = tx.Rollback()
+			return err
+		}
+
+		if err := s.addTrailers(ctx, tx, &commit); err != nil {
+			_ = tx.Rollback()
+			return err
+		}
 	}
 
 	if err := tx.Commit(); err != nil {
@@ -304,3 +701,285 @@ func (s *commitStore) DeleteCommitsByRepositoryID(ctx context.Context, repositor
 	}
 	return nil
 }
+
+// GetCommitsWithoutStats returns up to limit commits of the repository whose line statistics
+// have not been fetched yet, oldest first. Commits GitHub has no statistics for are left out.
+func (s *commitStore) GetCommitsWithoutStats(ctx context.Context, repositoryID int, limit int) ([]domain.Commit, error) {
+	var commits []domain.Commit
+
+	query := `
+		SELECT id, uid, repository_id, author_id, url, sha, message, commit_date, created_at,
+			additions, deletions, changed_files, stats_fetched_at
+		FROM commits
+		WHERE repository_id = $1 AND stats_fetched_at IS NULL AND stats_unavailable_at IS NULL
+		ORDER BY commit_date ASC
+		LIMIT $2
+	`
+	if err := s.db.SelectContext(ctx, &commits, query, repositoryID, limit); err != nil {
+		return nil, fmt.Errorf("failed to fetch commits without stats: %w", err)
+	}
+	return commits, nil
+}
+
+// CountCommitsWithoutStats returns how many commits of the repository still lack line statistics.
+func (s *commitStore) CountCommitsWithoutStats(ctx context.Context, repositoryID int) (int, error) {
+	var count int
+	query := `SELECT COUNT(*) FROM commits WHERE repository_id = $1 AND stats_fetched_at IS NULL AND stats_unavailable_at IS NULL`
+	if err := s.db.GetContext(ctx, &count, query, repositoryID); err != nil {
+		return 0, fmt.Errorf("failed to count commits without stats: %w", err)
+	}
+	return count, nil
+}
+
+// UpdateCommitStats stores the line statistics of a commit and marks them as fetched.
+func (s *commitStore) UpdateCommitStats(ctx context.Context, commitID int, stats domain.CommitStats) error {
+	query := `
+		UPDATE commits
+		SET additions = $1, deletions = $2, changed_files = $3, stats_fetched_at = $4
+		WHERE id = $5
+	`
+	if _, err := s.db.ExecContext(ctx, query, stats.Additions, stats.Deletions, stats.ChangedFiles, time.Now(), commitID); err != nil {
+		return fmt.Errorf("failed to update stats of commit %d: %w", commitID, err)
+	}
+	return nil
+}
+
+// MarkCommitStatsUnavailable records that the line statistics of a commit cannot be fetched,
+// so that the backfill skips it.
+func (s *commitStore) MarkCommitStatsUnavailable(ctx context.Context, commitID int) error {
+	query := `UPDATE commits SET stats_unavailable_at = $1 WHERE id = $2`
+	if _, err := s.db.ExecContext(ctx, query, time.Now(), commitID); err != nil {
+		return fmt.Errorf("failed to mark stats of commit %d as unavailable: %w", commitID, err)
+	}
+	return nil
+}
+
+// GetCommitReview returns the code review of a stored commit, or nil when none was recorded.
+func (s *commitStore) GetCommitReview(ctx context.Context, repositoryID int, sha string) (*domain.Review, error) {
+	query := `
+		SELECT cr.change_number, cr.change_id, cr.url, cr.opened_at, cr.submitted_at,
+			cr.owner_account_id AS "Owner.account_id",
+			cr.owner_name AS "Owner.name",
+			cr.owner_email AS "Owner.email"
+		FROM commit_reviews cr
+		JOIN commits c ON c.id = cr.commit_id
+		WHERE c.repository_id = $1 AND c.sha = $2
+	`
+	var review domain.Review
+	if err := s.db.GetContext(ctx, &review, query, repositoryID, sha); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to fetch review of commit %s: %w", sha, err)
+	}
+
+	reviewersQuery := `
+		SELECT rv.account_id, rv.name, rv.email, rv.code_review
+		FROM commit_reviewers rv
+		JOIN commits c ON c.id = rv.commit_id
+		WHERE c.repository_id = $1 AND c.sha = $2
+		ORDER BY rv.code_review DESC, rv.name
+	`
+	if err := s.db.SelectContext(ctx, &review.Reviewers, reviewersQuery, repositoryID, sha); err != nil {
+		return nil, fmt.Errorf("failed to fetch reviewers of commit %s: %w", sha, err)
+	}
+	return &review, nil
+}
+
+// LatestCommitSHA returns the SHA of the most recently committed stored commit of the repository,
+// restricted to the monitored branch when branchID is not 0. It is empty when none is stored.
+func (s *commitStore) LatestCommitSHA(ctx context.Context, repositoryID int, branchID int) (string, error) {
+	query := `
+		SELECT c.sha FROM commits c
+		WHERE c.repository_id = $1
+			AND ($2 = 0 OR EXISTS (SELECT 1 FROM commit_branches cb WHERE cb.commit_id = c.id AND cb.branch_id = $2))
+		ORDER BY COALESCE(c.committer_date, c.commit_date) DESC
+		LIMIT 1
+	`
+	var sha string
+	if err := s.db.GetContext(ctx, &sha, query, repositoryID, branchID); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return "", nil
+		}
+		return "", fmt.Errorf("failed to fetch latest commit of repository %d: %w", repositoryID, err)
+	}
+	return sha, nil
+}
+
+// LinkCommitAuthors credits up to limit stored commits with an ID above afterID to their authors,
+// committers and co-authors, e.g. for commits stored before co-authors were recorded. It returns
+// the ID of the last commit handled and how many were.
+func (s *commitStore) LinkCommitAuthors(ctx context.Context, afterID int, limit int) (int, int, error) {
+	var commits []struct {
+		ID          int           `db:"id"`
+		AuthorID    int           `db:"author_id"`
+		CommitterID sql.NullInt64 `db:"committer_id"`
+		Message     string        `db:"message"`
+	}
+	query := `
+		SELECT id, author_id, committer_id, message FROM commits
+		WHERE id > $1 AND author_id IS NOT NULL
+		ORDER BY id
+		LIMIT $2
+	`
+	if err := s.db.SelectContext(ctx, &commits, query, afterID, limit); err != nil {
+		return afterID, 0, fmt.Errorf("failed to fetch commits to credit: %w", err)
+	}
+	if len(commits) == 0 {
+		return afterID, 0, nil
+	}
+
+	tx, err := s.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return afterID, 0, fmt.Errorf("begin transaction: %w", err)
+	}
+	for _, commit := range commits {
+		if err := s.linkAuthors(ctx, tx, commit.ID, commit.AuthorID, int(commit.CommitterID.Int64), commit.Message); err != nil {
+			_ = tx.Rollback()
+			return afterID, 0, fmt.Errorf("crediting authors of commit %d: %w", commit.ID, err)
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		return afterID, 0, fmt.Errorf("commit transaction: %w", err)
+	}
+
+	return commits[len(commits)-1].ID, len(commits), nil
+}
+
+// GetTopReviewers returns the top N reviewers by reviewed commit count, as credited by the
+// "Reviewed-by:" trailers of commits. Reviewers are told apart by email, resolved to the canonical
+// author of a known email, and named as in their latest review otherwise. Only commits of
+// repositories on the host ctx is scoped to are counted, and only those of
+// ownerName/repositoryName when repositoryName is not empty.
+func (s *commitStore) GetTopReviewers(ctx context.Context, ownerName, repositoryName string, limit int) ([]domain.TopReviewer, error) {
+	var reviewers []domain.TopReviewer
+
+	query := `
+		WITH reviews AS (
+			SELECT
+				t.commit_id,
+				lower(substring(t.value from '<([^<>]+@[^<>]+)>')) AS email,
+				trim(split_part(t.value, '<', 1)) AS name,
+				c.commit_date
+			FROM commit_trailers t
+			JOIN commits c ON c.id = t.commit_id
+			JOIN repositories repo ON repo.id = c.repository_id
+			WHERE lower(t.key) = 'reviewed-by'
+				AND repo.host = $2
+				AND ($3::text = '' OR (repo.name = $3 AND repo.owner_name = $4))
+		),
+		reviewers AS (
+			SELECT
+				r.commit_id,
+				r.commit_date,
+				COALESCE(lower(a.email), r.email) AS email,
+				COALESCE(a.name, r.name) AS name
+			FROM reviews r
+			LEFT JOIN LATERAL (
+				SELECT COALESCE(x.canonical_id, x.id) AS id
+				FROM authors x
+				WHERE lower(x.email) = r.email
+				ORDER BY x.id
+				LIMIT 1
+			) known ON true
+			LEFT JOIN authors a ON a.id = known.id
+			WHERE r.email IS NOT NULL
+		)
+		SELECT
+			email,
+			(array_agg(name ORDER BY commit_date DESC))[1] AS name,
+			COUNT(DISTINCT commit_id) AS review_count
+		FROM reviewers
+		GROUP BY email
+		ORDER BY review_count DESC
+		LIMIT $1
+	`
+
+	if err := s.db.SelectContext(ctx, &reviewers, query, limit, domain.HostFromContext(ctx), repositoryName, ownerName); err != nil {
+		return nil, fmt.Errorf("failed to fetch top reviewers: %w", err)
+	}
+
+	return reviewers, nil
+}
+
+// StoreCommitTrailers records the trailers of up to limit stored commits with an ID above afterID,
+// e.g. for commits stored before trailers were recorded. It returns the ID of the last commit
+// handled and how many were.
+func (s *commitStore) StoreCommitTrailers(ctx context.Context, afterID int, limit int) (int, int, error) {
+	var commits []struct {
+		ID      int    `db:"id"`
+		Message string `db:"message"`
+	}
+	query := `SELECT id, message FROM commits WHERE id > $1 ORDER BY id LIMIT $2`
+	if err := s.db.SelectContext(ctx, &commits, query, afterID, limit); err != nil {
+		return afterID, 0, fmt.Errorf("failed to fetch commits to read trailers of: %w", err)
+	}
+	if len(commits) == 0 {
+		return afterID, 0, nil
+	}
+
+	tx, err := s.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return afterID, 0, fmt.Errorf("begin transaction: %w", err)
+	}
+	for _, commit := range commits {
+		if err := s.storeTrailers(ctx, tx, commit.ID, commit.Message); err != nil {
+			_ = tx.Rollback()
+			return afterID, 0, fmt.Errorf("storing trailers of commit %d: %w", commit.ID, err)
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		return afterID, 0, fmt.Errorf("commit transaction: %w", err)
+	}
+
+	return commits[len(commits)-1].ID, len(commits), nil
+}
+
+// GetBotShare splits the commits of a repository matching filter, and their authors, between bots
+// and people. Aliases count as their canonical author.
+func (s *commitStore) GetBotShare(ctx context.Context, ownerName, repositoryName string, filter domain.CommitFilter) (*domain.BotShare, error) {
+	_, where, args := commitConditions(ctx, ownerName, repositoryName, filter)
+
+	query := `
+		SELECT
+			COUNT(c.id) AS total_commits,
+			COUNT(c.id) FILTER (WHERE x.is_bot) AS bot_commits,
+			COUNT(c.id) FILTER (WHERE NOT x.is_bot) AS human_commits,
+			COUNT(DISTINCT COALESCE(x.canonical_id, x.id)) AS total_authors,
+			COUNT(DISTINCT COALESCE(x.canonical_id, x.id)) FILTER (WHERE x.is_bot) AS bot_authors,
+			COUNT(DISTINCT COALESCE(x.canonical_id, x.id)) FILTER (WHERE NOT x.is_bot) AS human_authors
+		FROM commits c
+		JOIN repositories r ON c.repository_id = r.id
+		JOIN authors x ON x.id = c.author_id
+		WHERE ` + where
+
+	var share domain.BotShare
+	if err := s.db.GetContext(ctx, &share, query, args...); err != nil {
+		return nil, fmt.Errorf("failed to compute bot share of repository %s: %w", repositoryName, err)
+	}
+	return &share, nil
+}
+
+// ClassifyBotAuthors flags the stored authors classified as bots, e.g. after the bot patterns
+// changed, and returns how many were newly flagged. Authors are never unflagged.
+func (s *commitStore) ClassifyBotAuthors(ctx context.Context) (int, error) {
+	var authors []domain.Author
+	if err := s.db.SelectContext(ctx, &authors, `SELECT id, uid, name, email, is_bot FROM authors WHERE NOT is_bot`); err != nil {
+		return 0, fmt.Errorf("failed to fetch authors to classify: %w", err)
+	}
+
+	var ids []int64
+	for _, author := range authors {
+		if s.bots.IsBot(author.Name, author.Email) {
+			ids = append(ids, int64(author.ID))
+		}
+	}
+	if len(ids) == 0 {
+		return 0, nil
+	}
+
+	if _, err := s.db.ExecContext(ctx, `UPDATE authors SET is_bot = true WHERE id = ANY($1)`, pq.Array(ids)); err != nil {
+		return 0, fmt.Errorf("failed to flag bot authors: %w", err)
+	}
+	return len(ids), nil
+}
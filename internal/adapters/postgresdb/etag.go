@@ -0,0 +1,56 @@
+package postgresdb
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/babyfaceeasy/lema/internal/domain"
+	"github.com/babyfaceeasy/lema/internal/repositories"
+	"github.com/jmoiron/sqlx"
+)
+
+type etagStore struct {
+	db *sqlx.DB
+}
+
+func NewETagStore(db *sql.DB) repositories.ETagRepository {
+	return &etagStore{db: sqlx.NewDb(db, "postgres")}
+}
+
+// ByURL returns the cached validators for the given request URL, or nil if none are stored.
+func (s *etagStore) ByURL(ctx context.Context, url string) (*domain.ETag, error) {
+	const query = `SELECT id, url, etag, last_modified, updated_at FROM github_etags WHERE url = $1`
+
+	var etag domain.ETag
+	if err := s.db.GetContext(ctx, &etag, query, url); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to find etag by url: %w", err)
+	}
+
+	return &etag, nil
+}
+
+// Save inserts or replaces the validators stored for etag.URL.
+func (s *etagStore) Save(ctx context.Context, etag domain.ETag) error {
+	if etag.UpdatedAt.IsZero() {
+		etag.UpdatedAt = time.Now()
+	}
+
+	query := `
+		INSERT INTO github_etags (url, etag, last_modified, updated_at)
+		VALUES (:url, :etag, :last_modified, :updated_at)
+		ON CONFLICT (url) DO UPDATE SET
+			etag = EXCLUDED.etag,
+			last_modified = EXCLUDED.last_modified,
+			updated_at = EXCLUDED.updated_at
+	`
+	if _, err := s.db.NamedExecContext(ctx, query, etag); err != nil {
+		return fmt.Errorf("failed to save etag for %s: %w", etag.URL, err)
+	}
+	return nil
+}
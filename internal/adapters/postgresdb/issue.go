@@ -0,0 +1,138 @@
+package postgresdb
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/babyfaceeasy/lema/internal/domain"
+	"github.com/babyfaceeasy/lema/internal/repositories"
+	"github.com/babyfaceeasy/lema/pkg/pagination"
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+)
+
+type issueStore struct {
+	db *sqlx.DB
+}
+
+func NewIssueStore(db *sql.DB) repositories.IssueRepository {
+	return &issueStore{db: sqlx.NewDb(db, "postgres")}
+}
+
+// UpsertIssues inserts or updates issues by repository and number.
+func (s *issueStore) UpsertIssues(ctx context.Context, issues []domain.Issue) error {
+	if len(issues) == 0 {
+		return nil
+	}
+
+	tx, err := s.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("begin transaction: %w", err)
+	}
+
+	query := `
+		INSERT INTO issues
+			(uid, repository_id, number, title, state, labels, author_login, url, opened_at, updated_at, closed_at, created_at)
+		VALUES
+			(:uid, :repository_id, :number, :title, :state, :labels, :author_login, :url, :opened_at, :updated_at, :closed_at, :created_at)
+		ON CONFLICT (repository_id, number) DO UPDATE SET
+			title = EXCLUDED.title,
+			state = EXCLUDED.state,
+			labels = EXCLUDED.labels,
+			updated_at = EXCLUDED.updated_at,
+			closed_at = EXCLUDED.closed_at
+	`
+
+	for _, issue := range issues {
+		if issue.UID == uuid.Nil {
+			issue.UID = uuid.New()
+		}
+		if issue.CreatedAt.IsZero() {
+			issue.CreatedAt = time.Now()
+		}
+		if issue.Labels == nil {
+			issue.Labels = []string{}
+		}
+
+		if _, err := tx.NamedExecContext(ctx, query, issue); err != nil {
+			_ = tx.Rollback()
+			return fmt.Errorf("upserting issue #%d: %w", issue.Number, err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("commit transaction: %w", err)
+	}
+	return nil
+}
+
+// GetIssues returns a page of the issues of a repository matching filter, newest first.
+func (s *issueStore) GetIssues(ctx context.Context, repositoryID int, filter domain.IssueFilter, page, pageSize int) ([]domain.Issue, int, error) {
+	conditions := []string{"repository_id = $1"}
+	args := []any{repositoryID}
+	if filter.State != "" {
+		args = append(args, filter.State)
+		conditions = append(conditions, fmt.Sprintf("state = $%d", len(args)))
+	}
+	if filter.Label != "" {
+		args = append(args, filter.Label)
+		conditions = append(conditions, fmt.Sprintf("$%d = ANY(labels)", len(args)))
+	}
+	where := strings.Join(conditions, " AND ")
+
+	query := `
+		SELECT id, uid, repository_id, number, title, state, labels, author_login, url, opened_at, updated_at, closed_at, created_at
+		FROM issues
+		WHERE ` + where + `
+		ORDER BY opened_at DESC
+	`
+
+	var issues []domain.Issue
+	if err := s.db.SelectContext(ctx, &issues, pagination.ApplyToQuery(query, page, pageSize), args...); err != nil {
+		return nil, 0, fmt.Errorf("failed to fetch issues of repository %d: %w", repositoryID, err)
+	}
+
+	var totalItems int
+	if err := s.db.GetContext(ctx, &totalItems, `SELECT COUNT(*) FROM issues WHERE `+where, args...); err != nil {
+		return nil, 0, fmt.Errorf("failed to count issues: %w", err)
+	}
+
+	return issues, totalItems, nil
+}
+
+// LatestUpdatedAt returns when the most recently updated stored issue of a repository changed,
+// or nil when none is stored.
+func (s *issueStore) LatestUpdatedAt(ctx context.Context, repositoryID int) (*time.Time, error) {
+	var latest sql.NullTime
+	if err := s.db.GetContext(ctx, &latest, `SELECT MAX(updated_at) FROM issues WHERE repository_id = $1`, repositoryID); err != nil {
+		return nil, fmt.Errorf("failed to get latest issue update: %w", err)
+	}
+	if !latest.Valid {
+		return nil, nil
+	}
+	return &latest.Time, nil
+}
+
+// GetReferencedIssues returns the issues referenced by the message of a stored commit, with their
+// details when they have been ingested.
+func (s *issueStore) GetReferencedIssues(ctx context.Context, repositoryID int, sha string) ([]domain.IssueReference, error) {
+	query := `
+		SELECT ir.owner_name, ir.repository_name, ir.issue_number, ir.keyword, i.title, i.state, i.url
+		FROM commit_issue_references ir
+		JOIN commits c ON c.id = ir.commit_id
+		JOIN repositories src ON src.id = c.repository_id
+		LEFT JOIN repositories r ON r.host = src.host AND lower(r.owner_name) = lower(ir.owner_name) AND lower(r.name) = lower(ir.repository_name)
+		LEFT JOIN issues i ON i.repository_id = r.id AND i.number = ir.issue_number
+		WHERE c.repository_id = $1 AND c.sha = $2
+		ORDER BY ir.owner_name, ir.repository_name, ir.issue_number
+	`
+
+	var refs []domain.IssueReference
+	if err := s.db.SelectContext(ctx, &refs, query, repositoryID, sha); err != nil {
+		return nil, fmt.Errorf("failed to fetch issues referenced by commit %s: %w", sha, err)
+	}
+	return refs, nil
+}
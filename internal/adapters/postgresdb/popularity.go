@@ -0,0 +1,93 @@
+package postgresdb
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/babyfaceeasy/lema/internal/domain"
+	"github.com/babyfaceeasy/lema/internal/repositories"
+	"github.com/jmoiron/sqlx"
+)
+
+type popularityStore struct {
+	db *sqlx.DB
+}
+
+func NewPopularityStore(db *sql.DB) repositories.PopularityRepository {
+	return &popularityStore{db: sqlx.NewDb(db, "postgres")}
+}
+
+const insertSnapshotQuery = `
+	INSERT INTO repository_snapshots (repository_id, source, stars_count, forks_count, watchers_count, open_issues_count, recorded_at)
+	VALUES (:repository_id, :source, :stars_count, :forks_count, :watchers_count, :open_issues_count, :recorded_at)
+`
+
+// SaveSnapshot stores a snapshot of the counters of a repository.
+func (s *popularityStore) SaveSnapshot(ctx context.Context, snapshot domain.RepositorySnapshot) error {
+	if snapshot.Source == "" {
+		snapshot.Source = domain.SnapshotSourceSnapshot
+	}
+	if snapshot.RecordedAt.IsZero() {
+		snapshot.RecordedAt = time.Now()
+	}
+
+	if _, err := s.db.NamedExecContext(ctx, insertSnapshotQuery, snapshot); err != nil {
+		return fmt.Errorf("failed to save snapshot of repository %d: %w", snapshot.RepositoryID, err)
+	}
+	return nil
+}
+
+// ReplaceStarHistory replaces the star history backfilled for a repository with snapshots.
+func (s *popularityStore) ReplaceStarHistory(ctx context.Context, repositoryID int, snapshots []domain.RepositorySnapshot) error {
+	tx, err := s.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("begin transaction: %w", err)
+	}
+
+	if _, err := tx.ExecContext(ctx, `DELETE FROM repository_snapshots WHERE repository_id = $1 AND source = $2`, repositoryID, domain.SnapshotSourceStargazers); err != nil {
+		_ = tx.Rollback()
+		return fmt.Errorf("failed to delete star history of repository %d: %w", repositoryID, err)
+	}
+
+	for _, snapshot := range snapshots {
+		snapshot.RepositoryID = repositoryID
+		snapshot.Source = domain.SnapshotSourceStargazers
+		if _, err := tx.NamedExecContext(ctx, insertSnapshotQuery, snapshot); err != nil {
+			_ = tx.Rollback()
+			return fmt.Errorf("failed to save star history of repository %d: %w", repositoryID, err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("commit transaction: %w", err)
+	}
+	return nil
+}
+
+// GetHistory returns the popularity history of a repository, oldest first, with one point per
+// bucket of the filter interval holding the latest counters recorded within it. Counters of
+// snapshots taken by lema are preferred over backfilled ones.
+func (s *popularityStore) GetHistory(ctx context.Context, repositoryID int, filter domain.PopularityFilter) ([]domain.PopularityPoint, error) {
+	query := `
+		SELECT
+			date_trunc($2, recorded_at AT TIME ZONE 'UTC') AT TIME ZONE 'UTC' AS bucket,
+			(array_agg(stars_count ORDER BY source = $5 DESC, recorded_at DESC) FILTER (WHERE stars_count IS NOT NULL))[1] AS stars_count,
+			(array_agg(forks_count ORDER BY recorded_at DESC) FILTER (WHERE forks_count IS NOT NULL))[1] AS forks_count,
+			(array_agg(watchers_count ORDER BY recorded_at DESC) FILTER (WHERE watchers_count IS NOT NULL))[1] AS watchers_count,
+			(array_agg(open_issues_count ORDER BY recorded_at DESC) FILTER (WHERE open_issues_count IS NOT NULL))[1] AS open_issues_count
+		FROM repository_snapshots
+		WHERE repository_id = $1
+			AND ($3::timestamptz IS NULL OR recorded_at >= $3)
+			AND ($4::timestamptz IS NULL OR recorded_at < $4)
+		GROUP BY bucket
+		ORDER BY bucket
+	`
+
+	var points []domain.PopularityPoint
+	if err := s.db.SelectContext(ctx, &points, query, repositoryID, filter.Interval, filter.Since, filter.Until, domain.SnapshotSourceSnapshot); err != nil {
+		return nil, fmt.Errorf("failed to get popularity history of repository %d: %w", repositoryID, err)
+	}
+	return points, nil
+}
@@ -0,0 +1,202 @@
+package postgresdb
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/babyfaceeasy/lema/internal/domain"
+	"github.com/babyfaceeasy/lema/internal/repositories"
+	"github.com/babyfaceeasy/lema/pkg/pagination"
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+)
+
+type pullRequestStore struct {
+	db *sqlx.DB
+}
+
+func NewPullRequestStore(db *sql.DB) repositories.PullRequestRepository {
+	return &pullRequestStore{db: sqlx.NewDb(db, "postgres")}
+}
+
+// UpsertPullRequests inserts or updates pull requests by repository and number, linking merged
+// ones to their merge commit when it is already stored.
+func (s *pullRequestStore) UpsertPullRequests(ctx context.Context, pullRequests []domain.PullRequest) error {
+	if len(pullRequests) == 0 {
+		return nil
+	}
+
+	tx, err := s.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("begin transaction: %w", err)
+	}
+
+	query := `
+		INSERT INTO pull_requests
+			(uid, repository_id, number, title, state, author_login, url, base_branch, head_branch, merge_commit_sha, merge_commit_id, opened_at, updated_at, closed_at, merged_at, created_at)
+		VALUES
+			(:uid, :repository_id, :number, :title, :state, :author_login, :url, :base_branch, :head_branch, :merge_commit_sha,
+			(SELECT id FROM commits WHERE repository_id = :repository_id AND sha = :merge_commit_sha AND :state = 'merged'),
+			:opened_at, :updated_at, :closed_at, :merged_at, :created_at)
+		ON CONFLICT (repository_id, number) DO UPDATE SET
+			title = EXCLUDED.title,
+			state = EXCLUDED.state,
+			base_branch = EXCLUDED.base_branch,
+			head_branch = EXCLUDED.head_branch,
+			merge_commit_sha = EXCLUDED.merge_commit_sha,
+			merge_commit_id = EXCLUDED.merge_commit_id,
+			updated_at = EXCLUDED.updated_at,
+			closed_at = EXCLUDED.closed_at,
+			merged_at = EXCLUDED.merged_at
+	`
+
+	for _, pr := range pullRequests {
+		if pr.UID == uuid.Nil {
+			pr.UID = uuid.New()
+		}
+		if pr.CreatedAt.IsZero() {
+			pr.CreatedAt = time.Now()
+		}
+
+		if _, err := tx.NamedExecContext(ctx, query, pr); err != nil {
+			_ = tx.Rollback()
+			return fmt.Errorf("upserting pull request #%d: %w", pr.Number, err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("commit transaction: %w", err)
+	}
+	return nil
+}
+
+// pullRequestConditions turns filter into SQL conditions on the pull_requests table aliased as p.
+func pullRequestConditions(repositoryID int, filter domain.PullRequestFilter) (string, []any) {
+	conditions := []string{"p.repository_id = $1"}
+	args := []any{repositoryID}
+
+	if filter.State != "" {
+		args = append(args, filter.State)
+		conditions = append(conditions, fmt.Sprintf("p.state = $%d", len(args)))
+	}
+	if filter.Author != "" {
+		args = append(args, filter.Author)
+		conditions = append(conditions, fmt.Sprintf("p.author_login ILIKE $%d", len(args)))
+	}
+	if filter.BaseBranch != "" {
+		args = append(args, filter.BaseBranch)
+		conditions = append(conditions, fmt.Sprintf("p.base_branch = $%d", len(args)))
+	}
+	if filter.Since != nil {
+		args = append(args, *filter.Since)
+		conditions = append(conditions, fmt.Sprintf("p.opened_at >= $%d", len(args)))
+	}
+	if filter.Until != nil {
+		args = append(args, *filter.Until)
+		conditions = append(conditions, fmt.Sprintf("p.opened_at <= $%d", len(args)))
+	}
+
+	return strings.Join(conditions, " AND "), args
+}
+
+// GetPullRequests returns a page of the pull requests of a repository matching filter, newest first.
+func (s *pullRequestStore) GetPullRequests(ctx context.Context, repositoryID int, filter domain.PullRequestFilter, page, pageSize int) ([]domain.PullRequest, int, error) {
+	where, args := pullRequestConditions(repositoryID, filter)
+
+	query := `
+		SELECT
+			p.id, p.uid, p.repository_id, p.number, p.title, p.state, p.author_login, p.url,
+			p.base_branch, p.head_branch, p.merge_commit_sha, p.merge_commit_id, c.uid AS merge_commit_uid,
+			p.opened_at, p.updated_at, p.closed_at, p.merged_at, p.created_at
+		FROM pull_requests p
+		LEFT JOIN commits c ON c.id = p.merge_commit_id
+		WHERE ` + where + `
+		ORDER BY p.opened_at DESC
+	`
+
+	var pullRequests []domain.PullRequest
+	if err := s.db.SelectContext(ctx, &pullRequests, pagination.ApplyToQuery(query, page, pageSize), args...); err != nil {
+		return nil, 0, fmt.Errorf("failed to fetch pull requests of repository %d: %w", repositoryID, err)
+	}
+
+	var totalItems int
+	if err := s.db.GetContext(ctx, &totalItems, `SELECT COUNT(*) FROM pull_requests p WHERE `+where, args...); err != nil {
+		return nil, 0, fmt.Errorf("failed to count pull requests: %w", err)
+	}
+
+	return pullRequests, totalItems, nil
+}
+
+// GetPullRequestStats counts the pull requests of a repository matching filter by state and
+// summarises how long the merged ones took from opening to merge.
+func (s *pullRequestStore) GetPullRequestStats(ctx context.Context, repositoryID int, filter domain.PullRequestFilter) (*domain.PullRequestStats, error) {
+	where, args := pullRequestConditions(repositoryID, filter)
+
+	query := `
+		SELECT
+			COUNT(*) AS total,
+			COUNT(*) FILTER (WHERE p.state = 'open') AS open,
+			COUNT(*) FILTER (WHERE p.state = 'closed') AS closed,
+			COUNT(*) FILTER (WHERE p.state = 'merged') AS merged,
+			COALESCE(AVG(m.hours), 0) AS average_hours_to_merge,
+			COALESCE(percentile_cont(0.5) WITHIN GROUP (ORDER BY m.hours), 0) AS median_hours_to_merge,
+			COALESCE(percentile_cont(0.9) WITHIN GROUP (ORDER BY m.hours), 0) AS p90_hours_to_merge
+		FROM pull_requests p
+		LEFT JOIN LATERAL (
+			SELECT EXTRACT(EPOCH FROM (p.merged_at - p.opened_at)) / 3600 AS hours
+			WHERE p.merged_at IS NOT NULL
+		) m ON true
+		WHERE ` + where
+
+	var stats domain.PullRequestStats
+	if err := s.db.GetContext(ctx, &stats, query, args...); err != nil {
+		return nil, fmt.Errorf("failed to compute pull request stats of repository %d: %w", repositoryID, err)
+	}
+	return &stats, nil
+}
+
+// SyncedUntil returns the watermark of the last complete pull request sync of a repository, or
+// nil when none completed.
+func (s *pullRequestStore) SyncedUntil(ctx context.Context, repositoryID int) (*time.Time, error) {
+	var syncedUntil sql.NullTime
+	if err := s.db.GetContext(ctx, &syncedUntil, `SELECT pull_requests_synced_until FROM repositories WHERE id = $1`, repositoryID); err != nil {
+		return nil, fmt.Errorf("failed to get pull request watermark: %w", err)
+	}
+	if !syncedUntil.Valid {
+		return nil, nil
+	}
+	return &syncedUntil.Time, nil
+}
+
+// UpdateSyncedUntil moves the pull request watermark of a repository.
+func (s *pullRequestStore) UpdateSyncedUntil(ctx context.Context, repositoryID int, syncedUntil time.Time) error {
+	query := `UPDATE repositories SET pull_requests_synced_until = $1 WHERE id = $2`
+	if _, err := s.db.ExecContext(ctx, query, syncedUntil, repositoryID); err != nil {
+		return fmt.Errorf("failed to update pull request watermark of repository %d: %w", repositoryID, err)
+	}
+	return nil
+}
+
+// LinkMergeCommits links merged pull requests whose merge commit was ingested after them and
+// returns how many were linked.
+func (s *pullRequestStore) LinkMergeCommits(ctx context.Context, repositoryID int) (int, error) {
+	query := `
+		UPDATE pull_requests p
+		SET merge_commit_id = c.id
+		FROM commits c
+		WHERE p.repository_id = $1 AND p.state = 'merged' AND p.merge_commit_id IS NULL
+			AND c.repository_id = p.repository_id AND c.sha = p.merge_commit_sha
+	`
+	res, err := s.db.ExecContext(ctx, query, repositoryID)
+	if err != nil {
+		return 0, fmt.Errorf("failed to link merge commits: %w", err)
+	}
+	linked, err := res.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("failed to get linked merge commits: %w", err)
+	}
+	return int(linked), nil
+}
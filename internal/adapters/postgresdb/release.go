@@ -0,0 +1,174 @@
+package postgresdb
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/babyfaceeasy/lema/internal/domain"
+	"github.com/babyfaceeasy/lema/internal/repositories"
+	"github.com/babyfaceeasy/lema/pkg/pagination"
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+)
+
+type releaseStore struct {
+	db *sqlx.DB
+}
+
+func NewReleaseStore(db *sql.DB) repositories.ReleaseRepository {
+	return &releaseStore{db: sqlx.NewDb(db, "postgres")}
+}
+
+// UpsertTags inserts or moves tags by repository and name, linking them to the stored commit
+// they point at.
+func (s *releaseStore) UpsertTags(ctx context.Context, tags []domain.Tag) error {
+	if len(tags) == 0 {
+		return nil
+	}
+
+	tx, err := s.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("begin transaction: %w", err)
+	}
+
+	query := `
+		INSERT INTO tags (uid, repository_id, name, sha, commit_id, created_at)
+		VALUES (:uid, :repository_id, :name, :sha,
+			(SELECT id FROM commits WHERE repository_id = :repository_id AND sha = :sha), :created_at)
+		ON CONFLICT (repository_id, name) DO UPDATE SET
+			sha = EXCLUDED.sha,
+			commit_id = EXCLUDED.commit_id
+	`
+
+	for _, tag := range tags {
+		if tag.UID == uuid.Nil {
+			tag.UID = uuid.New()
+		}
+		if tag.CreatedAt.IsZero() {
+			tag.CreatedAt = time.Now()
+		}
+
+		if _, err := tx.NamedExecContext(ctx, query, tag); err != nil {
+			_ = tx.Rollback()
+			return fmt.Errorf("upserting tag %s: %w", tag.Name, err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("commit transaction: %w", err)
+	}
+	return nil
+}
+
+// UpsertReleases inserts or updates releases by repository and GitHub ID. Their commit is
+// resolved through the stored tag of the same name.
+func (s *releaseStore) UpsertReleases(ctx context.Context, releases []domain.Release) error {
+	if len(releases) == 0 {
+		return nil
+	}
+
+	tx, err := s.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("begin transaction: %w", err)
+	}
+
+	query := `
+		INSERT INTO releases
+			(uid, repository_id, github_id, tag_name, name, sha, commit_id, draft, prerelease, author_login, url, published_at, created_at)
+		VALUES
+			(:uid, :repository_id, :github_id, :tag_name, :name,
+			COALESCE((SELECT sha FROM tags WHERE repository_id = :repository_id AND name = :tag_name), ''),
+			(SELECT commit_id FROM tags WHERE repository_id = :repository_id AND name = :tag_name),
+			:draft, :prerelease, :author_login, :url, :published_at, :created_at)
+		ON CONFLICT (repository_id, github_id) DO UPDATE SET
+			tag_name = EXCLUDED.tag_name,
+			name = EXCLUDED.name,
+			sha = EXCLUDED.sha,
+			commit_id = EXCLUDED.commit_id,
+			draft = EXCLUDED.draft,
+			prerelease = EXCLUDED.prerelease,
+			published_at = EXCLUDED.published_at
+	`
+
+	for _, release := range releases {
+		if release.UID == uuid.Nil {
+			release.UID = uuid.New()
+		}
+		if release.CreatedAt.IsZero() {
+			release.CreatedAt = time.Now()
+		}
+
+		if _, err := tx.NamedExecContext(ctx, query, release); err != nil {
+			_ = tx.Rollback()
+			return fmt.Errorf("upserting release %s: %w", release.TagName, err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("commit transaction: %w", err)
+	}
+	return nil
+}
+
+// GetReleases returns a page of the published releases of a repository, newest first.
+func (s *releaseStore) GetReleases(ctx context.Context, repositoryID int, includePrereleases bool, page, pageSize int) ([]domain.Release, int, error) {
+	where := `rl.repository_id = $1 AND NOT rl.draft AND ($2 OR NOT rl.prerelease)`
+
+	query := `
+		SELECT
+			rl.id, rl.uid, rl.repository_id, rl.github_id, rl.tag_name, rl.name, rl.sha, rl.commit_id, c.uid AS commit_uid,
+			rl.draft, rl.prerelease, rl.author_login, rl.url, rl.published_at, rl.created_at
+		FROM releases rl
+		LEFT JOIN commits c ON c.id = rl.commit_id
+		WHERE ` + where + `
+		ORDER BY rl.published_at DESC NULLS LAST
+	`
+
+	var releases []domain.Release
+	if err := s.db.SelectContext(ctx, &releases, pagination.ApplyToQuery(query, page, pageSize), repositoryID, includePrereleases); err != nil {
+		return nil, 0, fmt.Errorf("failed to fetch releases of repository %d: %w", repositoryID, err)
+	}
+
+	var totalItems int
+	if err := s.db.GetContext(ctx, &totalItems, `SELECT COUNT(*) FROM releases rl WHERE `+where, repositoryID, includePrereleases); err != nil {
+		return nil, 0, fmt.Errorf("failed to count releases: %w", err)
+	}
+
+	return releases, totalItems, nil
+}
+
+// GetReleaseCadence computes the time between consecutive published releases of a repository and
+// how many stored commits were authored in between.
+func (s *releaseStore) GetReleaseCadence(ctx context.Context, repositoryID int, includePrereleases bool) (*domain.ReleaseCadence, error) {
+	query := `
+		WITH published AS (
+			SELECT published_at, LAG(published_at) OVER (ORDER BY published_at) AS previous_at
+			FROM releases
+			WHERE repository_id = $1 AND NOT draft AND published_at IS NOT NULL AND ($2 OR NOT prerelease)
+		),
+		gaps AS (
+			SELECT
+				EXTRACT(EPOCH FROM (p.published_at - p.previous_at)) / 86400 AS days,
+				(SELECT COUNT(*) FROM commits c
+					WHERE c.repository_id = $1 AND c.commit_date > p.previous_at AND c.commit_date <= p.published_at) AS commits
+			FROM published p
+			WHERE p.previous_at IS NOT NULL
+		)
+		SELECT
+			(SELECT COUNT(*) FROM published) AS releases,
+			(SELECT MAX(published_at) FROM published) AS last_release_at,
+			COALESCE(AVG(days), 0) AS average_days_between_releases,
+			COALESCE(percentile_cont(0.5) WITHIN GROUP (ORDER BY days), 0) AS median_days_between_releases,
+			COALESCE(AVG(commits), 0) AS average_commits_per_release,
+			COALESCE(percentile_cont(0.5) WITHIN GROUP (ORDER BY commits), 0) AS median_commits_per_release
+		FROM gaps
+	`
+
+	var cadence domain.ReleaseCadence
+	if err := s.db.GetContext(ctx, &cadence, query, repositoryID, includePrereleases); err != nil {
+		return nil, fmt.Errorf("failed to compute release cadence of repository %d: %w", repositoryID, err)
+	}
+	return &cadence, nil
+}
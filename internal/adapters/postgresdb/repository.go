@@ -22,12 +22,12 @@ func NewRepositoryStore(db *sql.DB) repositories.RepositoryRepository {
 	return &repositoryStore{db: sqlx.NewDb(db, "postgres")}
 }
 
-// ByName returns the repository details if it exists in the system.
+// ByName returns the repository details if it exists in the system, on the host ctx is scoped to.
 func (s *repositoryStore) ByName(ctx context.Context, ownerName, repoName string) (*domain.Repository, error) {
-	const query = `SELECT * FROM repositories WHERE name ILIKE $1 AND owner_name ILIKE $2`
+	const query = `SELECT * FROM repositories WHERE name ILIKE $1 AND owner_name ILIKE $2 AND host = $3`
 
 	var repo domain.Repository
-	if err := s.db.GetContext(ctx, &repo, query, repoName, ownerName); err != nil {
+	if err := s.db.GetContext(ctx, &repo, query, repoName, ownerName, domain.HostFromContext(ctx)); err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			log.Printf("not found name = %s and owner_name = %s\n", repoName, ownerName)
 			return nil, nil
@@ -55,8 +55,8 @@ func (s *repositoryStore) GetAll(ctx context.Context) ([]domain.Repository, erro
 // Exists returns true if a repository with the given name exists.
 func (s *repositoryStore) Exists(ctx context.Context, owner, name string) (bool, error) {
 	var exists bool
-	query := `SELECT exists(SELECT 1 FROM repositories WHERE name = $1 and owner_name = $2)`
-	err := s.db.GetContext(ctx, &exists, query, name, owner)
+	query := `SELECT exists(SELECT 1 FROM repositories WHERE name = $1 and owner_name = $2 and host = $3)`
+	err := s.db.GetContext(ctx, &exists, query, name, owner, domain.HostFromContext(ctx))
 	if err != nil {
 		return false, fmt.Errorf("failed to check repository existence: %w", err)
 	}
@@ -68,9 +68,9 @@ func (s *repositoryStore) UpdateSinceDate(ctx context.Context, repositoryName st
 	query := `
 		UPDATE repositories 
 		SET since_date = $1
-		WHERE name = $2 and owner_name = $3
+		WHERE name = $2 and owner_name = $3 and host = $4
 	`
-	_, err := s.db.ExecContext(ctx, query, newSinceDate, repositoryName, ownerName)
+	_, err := s.db.ExecContext(ctx, query, newSinceDate, repositoryName, ownerName, domain.HostFromContext(ctx))
 	if err != nil {
 		return fmt.Errorf("failed to update since_date for repository %s: %w", repositoryName, err)
 	}
@@ -150,17 +150,28 @@ func (s *repositoryStore) CreateOrUpdateOLD(ctx context.Context, repo domain.Rep
 	return nil
 }
 
-// CreateOrUpdate creates a new repository record if one does not exist (by name),
-// or updates the existing record with the provided fields.
+// CreateOrUpdate creates a new repository record if one does not exist (by host and name),
+// or updates the existing record with the provided fields. Repositories without a host
+// belong to the host ctx is scoped to, and repositories without a state are active.
 func (s *repositoryStore) CreateOrUpdate(ctx context.Context, repo domain.Repository) error {
 	log.Printf("repo being passed: %+v\n", repo)
+	if repo.Host == "" {
+		repo.Host = domain.HostFromContext(ctx)
+	}
+	if repo.Provider == "" {
+		repo.Provider = domain.ProviderGithub
+	}
+	if repo.State == "" {
+		repo.State = domain.RepositoryStateActive
+	}
+
 	var id int
 	// Check if repository exists using its name.
 	err := s.db.GetContext(ctx, &id, `
 		SELECT id FROM repositories 
-		WHERE name = $1 and owner_name = $2
+		WHERE name = $1 and owner_name = $2 and host = $3
 		LIMIT 1
-	`, repo.Name, repo.OwnerName)
+	`, repo.Name, repo.OwnerName, repo.Host)
 	if err != nil {
 		if err == sql.ErrNoRows {
 			// Repository does not exist: insert a new one.
@@ -178,9 +189,9 @@ func (s *repositoryStore) CreateOrUpdate(ctx context.Context, repo domain.Reposi
 
 			insertQuery := `
 				INSERT INTO repositories 
-					(uid, name, owner_name, description, url, programming_language, forks_count, stars_count, watchers_count, open_issues_count, created_at, since_date, until_date)
+					(uid, github_id, host, provider, name, owner_name, description, url, programming_language, forks_count, stars_count, watchers_count, open_issues_count, default_branch, state, created_at, since_date, until_date)
 				VALUES 
-					(:uid, :name, :owner_name, :description, :url, :programming_language, :forks_count, :stars_count, :watchers_count, :open_issues_count, :created_at, :since_date, :until_date)
+					(:uid, :github_id, :host, :provider, :name, :owner_name, :description, :url, :programming_language, :forks_count, :stars_count, :watchers_count, :open_issues_count, :default_branch, :state, :created_at, :since_date, :until_date)
 				RETURNING id
 			`
 			stmt, err := s.db.PrepareNamedContext(ctx, insertQuery)
@@ -203,6 +214,7 @@ func (s *repositoryStore) CreateOrUpdate(ctx context.Context, repo domain.Reposi
 		}
 		updateQuery := `
 			UPDATE repositories SET 
+				github_id = COALESCE(:github_id, github_id),
 				description = :description,
 				url = :url,
 				owner_name = :owner_name,
@@ -211,9 +223,11 @@ func (s *repositoryStore) CreateOrUpdate(ctx context.Context, repo domain.Reposi
 				stars_count = :stars_count,
 				watchers_count = :watchers_count,
 				open_issues_count = :open_issues_count,
+				default_branch = :default_branch,
+				state = :state,
 				since_date = :since_date,
 				until_date = :until_date
-			WHERE name = :name and owner_name = :owner_name
+			WHERE name = :name and owner_name = :owner_name and host = :host
 		`
 		res, err := s.db.NamedExecContext(ctx, updateQuery, repo)
 		if err != nil {
@@ -242,11 +256,71 @@ func (s *repositoryStore) UpdateStartDate(ctx context.Context, ownerName string,
 	query := `
 		UPDATE repositories 
 		SET until_date = $1
-		WHERE name = $2 and owner_name = $3
+		WHERE name = $2 and owner_name = $3 and host = $4
 	`
-	_, err := s.db.ExecContext(ctx, query, dateParam, repositoryName, ownerName)
+	_, err := s.db.ExecContext(ctx, query, dateParam, repositoryName, ownerName, domain.HostFromContext(ctx))
 	if err != nil {
 		return fmt.Errorf("failed to update since_date for repository %s: %w", repositoryName, err)
 	}
 	return nil
 }
+
+// Rename moves the repository to its new owner and name, e.g. after it was renamed or transferred.
+func (s *repositoryStore) Rename(ctx context.Context, repositoryID int, newOwner, newName, url string) error {
+	query := `
+		UPDATE repositories
+		SET owner_name = $1, name = $2, url = COALESCE(NULLIF($3, ''), url)
+		WHERE id = $4
+	`
+	_, err := s.db.ExecContext(ctx, query, newOwner, newName, url, repositoryID)
+	if err != nil {
+		return fmt.Errorf("failed to rename repository %d to %s/%s: %w", repositoryID, newOwner, newName, err)
+	}
+	return nil
+}
+
+// UpdateState updates the state of the repository, e.g. once it is archived.
+func (s *repositoryStore) UpdateState(ctx context.Context, repositoryID int, state string) error {
+	query := `UPDATE repositories SET state = $1 WHERE id = $2`
+	_, err := s.db.ExecContext(ctx, query, state, repositoryID)
+	if err != nil {
+		return fmt.Errorf("failed to update state of repository %d: %w", repositoryID, err)
+	}
+	return nil
+}
+
+// UpdateCounters replaces the popularity counters of the repository with those of repo.
+func (s *repositoryStore) UpdateCounters(ctx context.Context, repositoryID int, repo domain.Repository) error {
+	query := `
+		UPDATE repositories SET
+			forks_count = $1,
+			stars_count = $2,
+			watchers_count = $3,
+			open_issues_count = $4
+		WHERE id = $5
+	`
+	_, err := s.db.ExecContext(ctx, query, repo.ForksCount, repo.StarsCount, repo.WatchersCount, repo.OpenIssuesCount, repositoryID)
+	if err != nil {
+		return fmt.Errorf("failed to update counters of repository %d: %w", repositoryID, err)
+	}
+	return nil
+}
+
+// UpdateDefaultBranch records the default branch of a repository.
+func (s *repositoryStore) UpdateDefaultBranch(ctx context.Context, repositoryID int, branch string) error {
+	query := `UPDATE repositories SET default_branch = $1 WHERE id = $2`
+	if _, err := s.db.ExecContext(ctx, query, branch, repositoryID); err != nil {
+		return fmt.Errorf("failed to update default branch of repository %d: %w", repositoryID, err)
+	}
+	return nil
+}
+
+// UpdateGithubID records the numeric ID GitHub gave the repository.
+func (s *repositoryStore) UpdateGithubID(ctx context.Context, repositoryID int, githubID int64) error {
+	query := `UPDATE repositories SET github_id = $1 WHERE id = $2`
+	_, err := s.db.ExecContext(ctx, query, githubID, repositoryID)
+	if err != nil {
+		return fmt.Errorf("failed to update github id of repository %d: %w", repositoryID, err)
+	}
+	return nil
+}
@@ -0,0 +1,45 @@
+package postgresdb
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/babyfaceeasy/lema/internal/repositories"
+	"github.com/jmoiron/sqlx"
+)
+
+type webhookStore struct {
+	db *sqlx.DB
+}
+
+func NewWebhookStore(db *sql.DB) repositories.WebhookRepository {
+	return &webhookStore{db: sqlx.NewDb(db, "postgres")}
+}
+
+// RecordDelivery records a webhook delivery, returning false if it was already recorded.
+func (s *webhookStore) RecordDelivery(ctx context.Context, deliveryID, event string) (bool, error) {
+	query := `
+		INSERT INTO webhook_deliveries (delivery_id, event)
+		VALUES ($1, $2)
+		ON CONFLICT (delivery_id) DO NOTHING
+	`
+	res, err := s.db.ExecContext(ctx, query, deliveryID, event)
+	if err != nil {
+		return false, fmt.Errorf("failed to record webhook delivery %s: %w", deliveryID, err)
+	}
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to get rows affected when recording webhook delivery %s: %w", deliveryID, err)
+	}
+	return affected == 1, nil
+}
+
+// ForgetDelivery removes a recorded delivery so that it is handled again when redelivered.
+func (s *webhookStore) ForgetDelivery(ctx context.Context, deliveryID string) error {
+	query := `DELETE FROM webhook_deliveries WHERE delivery_id = $1`
+	if _, err := s.db.ExecContext(ctx, query, deliveryID); err != nil {
+		return fmt.Errorf("failed to forget webhook delivery %s: %w", deliveryID, err)
+	}
+	return nil
+}
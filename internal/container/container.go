@@ -3,26 +3,51 @@ package container
 import (
 	"database/sql"
 	"net/http"
+	"strings"
 	"time"
 
 	"github.com/babyfaceeasy/lema/config"
 	"github.com/babyfaceeasy/lema/db"
 	"github.com/babyfaceeasy/lema/internal/adapters/postgresdb"
 	"github.com/babyfaceeasy/lema/internal/domain"
+	"github.com/babyfaceeasy/lema/internal/integrations/bitbucketapi"
+	"github.com/babyfaceeasy/lema/internal/integrations/gerritapi"
+	"github.com/babyfaceeasy/lema/internal/integrations/giteaapi"
 	"github.com/babyfaceeasy/lema/internal/integrations/githubapi"
+	"github.com/babyfaceeasy/lema/internal/integrations/gitlabapi"
+	"github.com/babyfaceeasy/lema/internal/integrations/localgit"
 	"github.com/babyfaceeasy/lema/internal/queue"
+	"github.com/babyfaceeasy/lema/internal/services/authorservice"
+	"github.com/babyfaceeasy/lema/internal/services/bitbucketservice"
 	"github.com/babyfaceeasy/lema/internal/services/commitsservice"
+	"github.com/babyfaceeasy/lema/internal/services/gerritservice"
+	"github.com/babyfaceeasy/lema/internal/services/giteaservice"
 	"github.com/babyfaceeasy/lema/internal/services/githubservice"
+	"github.com/babyfaceeasy/lema/internal/services/gitlabservice"
+	"github.com/babyfaceeasy/lema/internal/services/issueservice"
+	"github.com/babyfaceeasy/lema/internal/services/localgitservice"
+	"github.com/babyfaceeasy/lema/internal/services/popularityservice"
+	"github.com/babyfaceeasy/lema/internal/services/pullrequestservice"
+	"github.com/babyfaceeasy/lema/internal/services/releaseservice"
 	"github.com/babyfaceeasy/lema/internal/services/repositoryservice"
+	"github.com/babyfaceeasy/lema/internal/services/webhookservice"
+	"github.com/babyfaceeasy/lema/pkg/botdetect"
 	"go.uber.org/zap"
 )
 
 type Container struct {
-	config            *config.Config
-	dbConn            *sql.DB
-	commitService     domain.CommitService
-	repositoryService domain.RepositoryService
-	taskQueue         queue.TaskQueue
+	config             *config.Config
+	dbConn             *sql.DB
+	commitService      domain.CommitService
+	repositoryService  domain.RepositoryService
+	pullRequestService domain.PullRequestService
+	issueService       domain.IssueService
+	releaseService     domain.ReleaseService
+	webhookService     domain.WebhookService
+	popularityService  domain.PopularityService
+	authorService      domain.AuthorService
+	githubService      githubservice.GitHubService
+	taskQueue          queue.TaskQueue
 }
 
 func NewContainer(config *config.Config, logger *zap.Logger) *Container {
@@ -33,27 +58,145 @@ func NewContainer(config *config.Config, logger *zap.Logger) *Container {
 	}
 
 	// Repositories
-	commitRepo := postgresdb.NewCommitStore(dbConn)
+	bots, err := botdetect.New(config.GetBotAuthorPatterns())
+	if err != nil {
+		logger.Panic("Error reading bot author patterns", zap.Error(err))
+	}
+	commitRepo := postgresdb.NewCommitStore(dbConn, bots)
 	repositoryRepo := postgresdb.NewRepositoryStore(dbConn)
+	etagRepo := postgresdb.NewETagStore(dbConn)
+	branchRepo := postgresdb.NewBranchStore(dbConn)
+	pullRequestRepo := postgresdb.NewPullRequestStore(dbConn)
+	issueRepo := postgresdb.NewIssueStore(dbConn)
+	releaseRepo := postgresdb.NewReleaseStore(dbConn)
+	webhookRepo := postgresdb.NewWebhookStore(dbConn)
+	popularityRepo := postgresdb.NewPopularityStore(dbConn)
+	authorRepo := postgresdb.NewAuthorStore(dbConn)
 
 	// Clients
-	githubClient := githubapi.NewClient(config.GetGithubBaseUrl(), &http.Client{Timeout: 10 * time.Second}, logger, config)
+	sharedGithubOpts := []githubapi.ClientOption{
+		githubapi.WithETagStore(etagRepo),
+	}
+	if config.GetGithubRateLimitInRedis() {
+		redisClient, err := db.NewRedisDb(config)
+		if err != nil {
+			logger.Panic("Error connecting to redis", zap.Error(err))
+		}
+		sharedGithubOpts = append(sharedGithubOpts, githubapi.WithRateLimiter(githubapi.NewRedisRateLimiter(redisClient)))
+	}
+
+	githubOpts := append([]githubapi.ClientOption{githubapi.WithGraphQLURL(config.GetGithubApiUrl() + "/graphql")}, sharedGithubOpts...)
+	if config.GetGithubAppID() != 0 {
+		appAuth, err := githubapi.LoadAppAuth(
+			config.GetGithubAppID(),
+			config.GetGithubAppPrivateKeyPath(),
+			config.GetGithubApiUrl(),
+			config.GetGithubAppInstallationID(),
+			&http.Client{Timeout: 10 * time.Second},
+		)
+		if err != nil {
+			logger.Panic("Error loading github app credentials", zap.Error(err))
+		}
+		githubOpts = append(githubOpts, githubapi.WithAppAuth(appAuth))
+	}
+	githubClient := githubapi.NewClient(
+		config.GetGithubBaseUrl(),
+		&http.Client{Timeout: 10 * time.Second},
+		logger,
+		config,
+		githubOpts...,
+	)
+
+	// Every host gets its own client, with its own credentials.
+	providers := map[string]domain.Provider{
+		domain.DefaultHost:   newGithubService(config, githubClient, logger),
+		domain.GitlabHost:    newGitlabService(config.GetGitlabBaseUrl(), config.GetGitlabToken(), logger.With(zap.String("host", domain.GitlabHost))),
+		domain.BitbucketHost: newBitbucketService(config, logger.With(zap.String("host", domain.BitbucketHost))),
+	}
+	for _, host := range config.GetProviderHosts() {
+		hostLogger := logger.With(zap.String("host", host.Host))
+		switch host.Provider {
+		case domain.ProviderGitlab:
+			providers[host.Host] = newGitlabService(host.BaseUrl, firstToken(host.Tokens), hostLogger)
+			continue
+		case domain.ProviderGitea:
+			giteaClient := giteaapi.NewClient(host.BaseUrl, firstToken(host.Tokens), &http.Client{Timeout: 10 * time.Second}, hostLogger)
+			providers[host.Host] = giteaservice.NewGiteaService(giteaClient, hostLogger)
+			continue
+		case domain.ProviderGerrit:
+			username, password, _ := strings.Cut(firstToken(host.Tokens), ":")
+			gerritClient := gerritapi.NewClient(host.BaseUrl, username, password, &http.Client{Timeout: 10 * time.Second}, hostLogger)
+			providers[host.Host] = gerritservice.NewGerritService(gerritClient, hostLogger)
+			continue
+		case domain.ProviderLocal:
+			providers[host.Host] = localgitservice.NewLocalGitService(localgit.NewClient(host.BaseUrl, hostLogger), hostLogger)
+			continue
+		}
+
+		hostOpts := append([]githubapi.ClientOption{
+			githubapi.WithGraphQLURL(host.GraphqlUrl),
+			githubapi.WithTokenPool(githubapi.NewTokenPool(host.Tokens)),
+		}, sharedGithubOpts...)
+		hostClient := githubapi.NewClient(host.BaseUrl, &http.Client{Timeout: 10 * time.Second}, hostLogger, config, hostOpts...)
+		providers[host.Host] = newGithubService(config, hostClient, hostLogger)
+	}
 
 	// Services
-	githubSvc := githubservice.NewGithubService(githubClient, logger)
-	repositorySvc := repositoryservice.NewRepositoryService(logger, repositoryRepo, githubSvc)
+	githubSvc := githubservice.NewHostRouter(providers)
+	repositorySvc := repositoryservice.NewRepositoryService(logger, repositoryRepo, branchRepo, githubSvc)
 	commitSvc := commitsservice.NewCommitService(githubSvc, commitRepo, logger, repositorySvc)
+	pullRequestSvc := pullrequestservice.NewPullRequestService(githubSvc, pullRequestRepo, logger, repositorySvc)
+	issueSvc := issueservice.NewIssueService(githubSvc, issueRepo, logger, repositorySvc)
+	releaseSvc := releaseservice.NewReleaseService(githubSvc, releaseRepo, logger, repositorySvc)
+	webhookSvc := webhookservice.NewWebhookService(webhookRepo, commitRepo, logger, repositorySvc)
+	popularitySvc := popularityservice.NewPopularityService(githubSvc, popularityRepo, logger, repositorySvc)
+	authorSvc := authorservice.NewAuthorService(githubSvc, authorRepo, logger, repositorySvc)
 
 	// Queue
 	inMemQueue := queue.NewInMemoryQueue(5, 100, logger, commitSvc, repositorySvc)
 
 	return &Container{
-		config:            config,
-		dbConn:            dbConn,
-		commitService:     commitSvc,
-		repositoryService: repositorySvc,
-		taskQueue:         inMemQueue,
+		config:             config,
+		dbConn:             dbConn,
+		commitService:      commitSvc,
+		repositoryService:  repositorySvc,
+		pullRequestService: pullRequestSvc,
+		issueService:       issueSvc,
+		releaseService:     releaseSvc,
+		webhookService:     webhookSvc,
+		popularityService:  popularitySvc,
+		authorService:      authorSvc,
+		githubService:      githubSvc,
+		taskQueue:          inMemQueue,
+	}
+}
+
+// newGithubService wraps client in the GitHubService fetching commit history with the configured API.
+func newGithubService(config *config.Config, client *githubapi.Client, logger *zap.Logger) githubservice.GitHubService {
+	if config.UsesGithubGraphQL() {
+		return githubservice.NewGithubGraphQLService(client, logger)
+	}
+	return githubservice.NewGithubService(client, logger)
+}
+
+// newGitlabService creates the provider of the GitLab instance whose API is rooted at baseURL.
+func newGitlabService(baseURL, token string, logger *zap.Logger) domain.Provider {
+	client := gitlabapi.NewClient(baseURL, token, &http.Client{Timeout: 10 * time.Second}, logger)
+	return gitlabservice.NewGitlabService(client, logger)
+}
+
+// newBitbucketService creates the provider of Bitbucket Cloud, authenticating with an app password.
+func newBitbucketService(config *config.Config, logger *zap.Logger) domain.Provider {
+	client := bitbucketapi.NewClient(config.GetBitbucketBaseUrl(), config.GetBitbucketUsername(), config.GetBitbucketAppPassword(), &http.Client{Timeout: 10 * time.Second}, logger)
+	return bitbucketservice.NewBitbucketService(client, logger)
+}
+
+// firstToken returns the first of tokens, GitLab, Gitea and Gerrit hosts being accessed with a single token.
+func firstToken(tokens []string) string {
+	if len(tokens) == 0 {
+		return ""
 	}
+	return tokens[0]
 }
 
 func (c *Container) GetCommitService() domain.CommitService {
@@ -64,6 +207,34 @@ func (c *Container) GetRepositoryService() domain.RepositoryService {
 	return c.repositoryService
 }
 
+func (c *Container) GetPullRequestService() domain.PullRequestService {
+	return c.pullRequestService
+}
+
+func (c *Container) GetIssueService() domain.IssueService {
+	return c.issueService
+}
+
+func (c *Container) GetReleaseService() domain.ReleaseService {
+	return c.releaseService
+}
+
+func (c *Container) GetWebhookService() domain.WebhookService {
+	return c.webhookService
+}
+
+func (c *Container) GetPopularityService() domain.PopularityService {
+	return c.popularityService
+}
+
+func (c *Container) GetAuthorService() domain.AuthorService {
+	return c.authorService
+}
+
+func (c *Container) GetGithubService() githubservice.GitHubService {
+	return c.githubService
+}
+
 func (c *Container) GetTaskQueue() queue.TaskQueue {
 	return c.taskQueue
 }
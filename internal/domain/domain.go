@@ -2,17 +2,61 @@ package domain
 
 import (
 	"context"
+	"io"
 	"time"
 
 	"github.com/babyfaceeasy/lema/pkg/pagination"
+	"github.com/google/uuid"
 )
 
+// Provider fetches repositories and their commit history from a code hosting service such as
+// GitHub or GitLab. Commits are streamed through commitCh, newest first.
+type Provider interface {
+	GetRepositoryDetails(ctx context.Context, repositoryName, ownerName string) (*Repository, error)
+	GetCommitsNew(ctx context.Context, repositoryName, ownerName string, since, until *time.Time, pageSize int, commitCh chan<- Commit) error
+}
+
 type CommitService interface {
-	GetTopCommitAuthors(ctx context.Context, owner, name string, limit int) ([]CommitAuthor, error)
-	GetCommitsByRepositoryName(ctx context.Context, owner, name string, page, pageSize int) ([]Commit, *pagination.Pagination, error)
+	GetTopCommitAuthors(ctx context.Context, owner, name string, filter AuthorFilter, limit int) ([]CommitAuthor, error)
+	GetCommitsByRepositoryName(ctx context.Context, owner, name string, filter CommitFilter, page, pageSize int) ([]Commit, *pagination.Pagination, error)
 	LoadCommits(ctx context.Context, owner string, name string) error
 	GetLatestCommitsNew(ctx context.Context, owner string, name string) error
 	ResetCommits(ctx context.Context, owner string, name string) error
+	LoadCommitStats(ctx context.Context, owner string, name string, limit int) (*CommitStatsProgress, error)
+	SyncBranchCommits(ctx context.Context, owner string, name string, branch string) error
+	GetCommitReview(ctx context.Context, owner, name, sha string) (*Review, error)
+	ImportMailbox(ctx context.Context, owner, name string, mbox io.Reader) (*MailboxImport, error)
+	BackfillCommitAuthors(ctx context.Context) (int, error)
+	GetTopReviewers(ctx context.Context, ownerName, repoName string, limit int) ([]TopReviewer, error)
+	BackfillCommitTrailers(ctx context.Context) (int, error)
+	GetBotShare(ctx context.Context, owner, name string, filter CommitFilter) (*BotShare, error)
+	ClassifyBotAuthors(ctx context.Context) (int, error)
+}
+
+type AuthorService interface {
+	GetAuthorIdentity(ctx context.Context, uid uuid.UUID) (*AuthorIdentity, error)
+	MergeAuthors(ctx context.Context, sourceUID, targetUID uuid.UUID) (*AuthorIdentity, error)
+	SplitAuthor(ctx context.Context, uid uuid.UUID) (*AuthorIdentity, error)
+	ImportMailmap(ctx context.Context, owner, name string, mailmap string) (*MailmapImport, error)
+	SyncMailmap(ctx context.Context, owner, name string) (*MailmapImport, error)
+}
+
+type PullRequestService interface {
+	SyncPullRequests(ctx context.Context, owner string, name string) error
+	GetPullRequests(ctx context.Context, owner, name string, filter PullRequestFilter, page, pageSize int) ([]PullRequest, *pagination.Pagination, error)
+	GetPullRequestStats(ctx context.Context, owner, name string, filter PullRequestFilter) (*PullRequestStats, error)
+}
+
+type IssueService interface {
+	SyncIssues(ctx context.Context, owner string, name string) error
+	GetIssues(ctx context.Context, owner, name string, filter IssueFilter, page, pageSize int) ([]Issue, *pagination.Pagination, error)
+	GetCommitIssues(ctx context.Context, owner, name, sha string) ([]IssueReference, error)
+}
+
+type ReleaseService interface {
+	SyncReleases(ctx context.Context, owner string, name string) error
+	GetReleases(ctx context.Context, owner, name string, includePrereleases bool, page, pageSize int) ([]Release, *pagination.Pagination, error)
+	GetReleaseCadence(ctx context.Context, owner, name string, includePrereleases bool) (*ReleaseCadence, error)
 }
 
 type RepositoryService interface {
@@ -21,4 +65,22 @@ type RepositoryService interface {
 	SaveRepository(ctx context.Context, ownerName string, repoName string, startTime *time.Time) error
 	UpdateRepositorySinceDate(ctx context.Context, ownerName string, repoName string, startTime time.Time) error
 	UpdateRepositoryStartDate(ctx context.Context, ownerName string, repoName string, startTime time.Time) error
+	AddRepositoryBranches(ctx context.Context, ownerName string, repoName string, branches []string) error
+	GetRepositoryBranches(ctx context.Context, ownerName string, repoName string) ([]Branch, error)
+	UpdateBranchSinceDate(ctx context.Context, branchID int, sinceTime time.Time) error
+	ResetRepositoryBranches(ctx context.Context, ownerName string, repoName string) error
+	EnsureDefaultBranch(ctx context.Context, ownerName string, repoName string) (*Branch, error)
+	RenameRepository(ctx context.Context, ownerName string, repoName string, newOwnerName string, newRepoName string, url string) error
+	UpdateRepositoryState(ctx context.Context, ownerName string, repoName string, state string) error
+	RefreshRepository(ctx context.Context, ownerName string, repoName string) (*Repository, error)
+}
+
+type PopularityService interface {
+	SnapshotRepository(ctx context.Context, owner string, name string) error
+	BackfillStarHistory(ctx context.Context, owner string, name string) (int, error)
+	GetPopularityHistory(ctx context.Context, owner, name string, filter PopularityFilter) ([]PopularityPoint, error)
+}
+
+type WebhookService interface {
+	HandleGithubWebhook(ctx context.Context, event, deliveryID string, payload []byte) (*WebhookResult, error)
 }
@@ -0,0 +1,18 @@
+package domain
+
+import "errors"
+
+// ErrNotModified is returned when an upstream resource has not changed since it was last fetched.
+var ErrNotModified = errors.New("resource not modified since last fetch")
+
+// ErrUnsupportedByProvider is returned when the provider hosting a repository has no equivalent of a request.
+var ErrUnsupportedByProvider = errors.New("not supported by the repository provider")
+
+// ErrInvalidWebhookPayload is returned when the payload of a webhook cannot be read.
+var ErrInvalidWebhookPayload = errors.New("invalid webhook payload")
+
+// ErrNotFound is returned when a requested resource does not exist.
+var ErrNotFound = errors.New("not found")
+
+// ErrInvalidAuthorMerge is returned when an author identity cannot be merged into another, e.g. into itself.
+var ErrInvalidAuthorMerge = errors.New("invalid author merge")
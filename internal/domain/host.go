@@ -0,0 +1,98 @@
+package domain
+
+import (
+	"context"
+	"strings"
+)
+
+// DefaultHost is the host of repositories monitored without naming one.
+const DefaultHost = "github.com"
+
+// Providers repositories can be hosted by.
+const (
+	ProviderGithub    = "github"
+	ProviderGitlab    = "gitlab"
+	ProviderBitbucket = "bitbucket"
+	// ProviderGitea serves Gitea and Forgejo instances, which share the same API.
+	ProviderGitea = "gitea"
+	// ProviderGerrit serves Gerrit Code Review servers, whose merged changes stand for commits.
+	ProviderGerrit = "gerrit"
+	// ProviderLocal reads clones and bare mirrors on the local filesystem, without any hosting API.
+	ProviderLocal = "local"
+	// ProviderMailbox holds patches imported from mailboxes, which are never synced.
+	ProviderMailbox = "mailbox"
+)
+
+// MailboxHost is the pseudo host of the repositories patches are imported into.
+const MailboxHost = "mailbox"
+
+// Hosts of the public instances of the providers besides github.com.
+const (
+	GitlabHost    = "gitlab.com"
+	BitbucketHost = "bitbucket.org"
+)
+
+// PublicHost returns the host of the public instance of provider.
+func PublicHost(provider string) string {
+	switch provider {
+	case ProviderGitlab:
+		return GitlabHost
+	case ProviderBitbucket:
+		return BitbucketHost
+	}
+	return DefaultHost
+}
+
+type hostKey struct{}
+
+// WithHost scopes ctx to the repositories served by host, such as a GitHub Enterprise Server.
+// Repository lookups and GitHub calls made with ctx use that host. An empty host means DefaultHost.
+func WithHost(ctx context.Context, host string) context.Context {
+	return context.WithValue(ctx, hostKey{}, NormalizeHost(host))
+}
+
+// HostFromContext returns the host ctx is scoped to, DefaultHost when it has none.
+func HostFromContext(ctx context.Context) string {
+	if host, ok := ctx.Value(hostKey{}).(string); ok {
+		return host
+	}
+	return DefaultHost
+}
+
+type branchKey struct{}
+
+// WithBranch makes commit history fetched with ctx walk branch instead of the default branch.
+func WithBranch(ctx context.Context, branch string) context.Context {
+	return context.WithValue(ctx, branchKey{}, branch)
+}
+
+// BranchFromContext returns the branch commit history fetched with ctx walks, empty for the default branch.
+func BranchFromContext(ctx context.Context) string {
+	v, _ := ctx.Value(branchKey{}).(string)
+	return v
+}
+
+type syncedSHAKey struct{}
+
+// WithSyncedSHA tells providers able to walk history from a commit, such as local clones, that
+// commits reachable from sha are already stored.
+func WithSyncedSHA(ctx context.Context, sha string) context.Context {
+	return context.WithValue(ctx, syncedSHAKey{}, sha)
+}
+
+// SyncedSHAFromContext returns the SHA set with WithSyncedSHA, empty when none was.
+func SyncedSHAFromContext(ctx context.Context) string {
+	v, _ := ctx.Value(syncedSHAKey{}).(string)
+	return v
+}
+
+// NormalizeHost lower-cases host and strips any scheme or trailing slash from it.
+func NormalizeHost(host string) string {
+	host = strings.ToLower(strings.TrimSpace(host))
+	host = strings.TrimPrefix(strings.TrimPrefix(host, "https://"), "http://")
+	host = strings.TrimSuffix(host, "/")
+	if host == "" {
+		return DefaultHost
+	}
+	return host
+}
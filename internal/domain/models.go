@@ -4,11 +4,15 @@ import (
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/lib/pq"
 )
 
 type Repository struct {
 	ID                  int        `db:"id" json:"-"`
 	UID                 uuid.UUID  `db:"uid" json:"id,omitempty"`
+	GithubID            *int64     `db:"github_id" json:"github_id,omitempty"`
+	Host                string     `db:"host" json:"host"`
+	Provider            string     `db:"provider" json:"provider"`
 	Name                string     `db:"name" json:"name"`
 	OwnerName           string     `db:"owner_name" json:"owner_name"` // todo: change this to owner later
 	Description         string     `db:"description" json:"description"`
@@ -18,11 +22,35 @@ type Repository struct {
 	StarsCount          int        `db:"stars_count" json:"stars_count"`
 	WatchersCount       int        `db:"watchers_count" json:"watchers_count"`
 	OpenIssuesCount     int        `db:"open_issues_count" json:"open_issues_count"`
+	DefaultBranch       string     `db:"default_branch" json:"default_branch"`
+	State               string     `db:"state" json:"state"`
 	UntilDate           *time.Time `db:"until_date" json:"-"`
 	SinceDate           time.Time  `db:"since_date" json:"-"`
 	CreatedAt           time.Time  `db:"created_at" json:"-"`
 }
 
+const (
+	RepositoryStateActive   = "active"
+	RepositoryStateArchived = "archived"
+	RepositoryStateDeleted  = "deleted"
+)
+
+// Active reports whether the repository is still synced, archived and deleted repositories being left alone.
+func (r Repository) Active() bool {
+	return r.State == "" || r.State == RepositoryStateActive
+}
+
+// Imported reports whether the commits of the repository are imported rather than synced.
+func (r Repository) Imported() bool {
+	return r.Provider == ProviderMailbox
+}
+
+// HostedOnGithub reports whether the repository is served by GitHub, which alone provides pull
+// requests, issues, releases and per-commit statistics.
+func (r Repository) HostedOnGithub() bool {
+	return r.Provider == "" || r.Provider == ProviderGithub
+}
+
 type Commit struct {
 	ID           int        `db:"id" json:"-"`
 	UID          uuid.UUID  `db:"uid" json:"id,omitempty"`
@@ -35,6 +63,242 @@ type Commit struct {
 	CreatedAt    time.Time  `db:"created_at" json:"-"`
 	Repository   Repository `db:"Repository" json:"repository"`
 	Author       Author     `db:"Author" json:"author"`
+
+	// Line statistics, only meaningful once StatsFetchedAt is set.
+	Additions      int        `db:"additions" json:"additions"`
+	Deletions      int        `db:"deletions" json:"deletions"`
+	ChangedFiles   int        `db:"changed_files" json:"changed_files"`
+	StatsFetchedAt *time.Time `db:"stats_fetched_at" json:"stats_fetched_at,omitempty"`
+
+	// Committer is who applied the commit, e.g. the CQ bot or whoever rebased it.
+	// CommitDate stays the author date.
+	CommitterID   int       `db:"committer_id" json:"-"`
+	CommitterDate time.Time `db:"committer_date" json:"committer_date"`
+	Committer     *Author   `db:"Committer" json:"committer,omitempty"`
+
+	// Only filled in by fetchers that provide them, e.g. the GraphQL one.
+	Parents []string `db:"-" json:"parents,omitempty"`
+
+	// BranchID records the monitored branch the commit was fetched from, if any.
+	BranchID int `db:"-" json:"-"`
+
+	// Review is only filled in by providers built around code review, e.g. Gerrit.
+	Review *Review `db:"-" json:"review,omitempty"`
+}
+
+// Review is the code review a commit went through before being merged.
+type Review struct {
+	ChangeNumber int           `db:"change_number" json:"change_number"`
+	ChangeID     string        `db:"change_id" json:"change_id"`
+	URL          string        `db:"url" json:"url"`
+	Owner        ReviewAccount `db:"Owner" json:"owner"`
+	OpenedAt     time.Time     `db:"opened_at" json:"opened_at"`
+	SubmittedAt  *time.Time    `db:"submitted_at" json:"submitted_at,omitempty"`
+	Reviewers    []Reviewer    `db:"-" json:"reviewers"`
+}
+
+// ReviewAccount is an account of the review system, which may not have a visible email address.
+type ReviewAccount struct {
+	AccountID int    `db:"account_id" json:"account_id"`
+	Name      string `db:"name" json:"name"`
+	Email     string `db:"email" json:"email,omitempty"`
+}
+
+// Reviewer is an account asked to review a change, with the Code-Review vote they left, if any.
+type Reviewer struct {
+	ReviewAccount
+	CodeReview int `db:"code_review" json:"code_review"`
+}
+
+// Branch is a monitored branch of a repository. Each branch is synced on its own, starting
+// from SinceDate.
+type Branch struct {
+	ID           int        `db:"id" json:"-"`
+	UID          uuid.UUID  `db:"uid" json:"id,omitempty"`
+	RepositoryID int        `db:"repository_id" json:"-"`
+	Name         string     `db:"name" json:"name"`
+	SinceDate    *time.Time `db:"since_date" json:"last_synced_at"`
+	CreatedAt    time.Time  `db:"created_at" json:"created_at"`
+}
+
+const (
+	CommitDateAuthor    = "author"
+	CommitDateCommitter = "committer"
+)
+
+// CommitFilter narrows down and orders the commits of a repository.
+type CommitFilter struct {
+	// DateField selects which date Since, Until and the ordering apply to: CommitDateAuthor (default) or CommitDateCommitter.
+	DateField string
+	Since     *time.Time
+	Until     *time.Time
+	// Branch only keeps commits reachable from the named monitored branch.
+	Branch string
+	// Issue only keeps commits whose message references that issue number of the repository.
+	Issue int
+	// TrailerKey only keeps commits with a trailer of that key, compared case-insensitively, and
+	// of value TrailerValue unless it is empty.
+	TrailerKey   string
+	TrailerValue string
+	// Bots is BotsExclude or BotsOnly to leave out or only keep commits authored by bots.
+	Bots string
+}
+
+// CommitStats holds the size of a commit.
+type CommitStats struct {
+	Additions    int `json:"additions"`
+	Deletions    int `json:"deletions"`
+	ChangedFiles int `json:"changed_files"`
+}
+
+// CommitStatsProgress reports how far a commit statistics backfill got.
+type CommitStatsProgress struct {
+	Processed int `json:"processed"`
+	// Skipped counts the commits GitHub has no statistics for, which are not asked for again.
+	Skipped int `json:"skipped"`
+	Pending int `json:"pending"`
+	// ResumeAt is set when the backfill stopped early to preserve the rate limit budget.
+	ResumeAt time.Time `json:"resume_at"`
+}
+
+// MailboxImport reports the patches imported from a mailbox. Messages without a patch, such as
+// replies, are skipped, while messages that could not be read are listed in Errors.
+type MailboxImport struct {
+	Imported int                  `json:"imported"`
+	Skipped  int                  `json:"skipped"`
+	Errors   []MailboxImportError `json:"errors"`
+}
+
+type MailboxImportError struct {
+	Message int    `json:"message"`
+	Line    int    `json:"line"`
+	Error   string `json:"error"`
+}
+
+const (
+	PullRequestStateOpen   = "open"
+	PullRequestStateClosed = "closed"
+	PullRequestStateMerged = "merged"
+)
+
+type PullRequest struct {
+	ID             int        `db:"id" json:"-"`
+	UID            uuid.UUID  `db:"uid" json:"id,omitempty"`
+	RepositoryID   int        `db:"repository_id" json:"-"`
+	Number         int        `db:"number" json:"number"`
+	Title          string     `db:"title" json:"title"`
+	State          string     `db:"state" json:"state"`
+	AuthorLogin    string     `db:"author_login" json:"author"`
+	URL            string     `db:"url" json:"url"`
+	BaseBranch     string     `db:"base_branch" json:"base_branch"`
+	HeadBranch     string     `db:"head_branch" json:"head_branch"`
+	MergeCommitSHA string     `db:"merge_commit_sha" json:"merge_commit_sha,omitempty"`
+	MergeCommitID  *int       `db:"merge_commit_id" json:"-"`
+	MergeCommitUID *uuid.UUID `db:"merge_commit_uid" json:"merge_commit_id,omitempty"`
+	OpenedAt       time.Time  `db:"opened_at" json:"opened_at"`
+	UpdatedAt      time.Time  `db:"updated_at" json:"updated_at"`
+	ClosedAt       *time.Time `db:"closed_at" json:"closed_at"`
+	MergedAt       *time.Time `db:"merged_at" json:"merged_at"`
+	CreatedAt      time.Time  `db:"created_at" json:"-"`
+}
+
+// PullRequestFilter narrows down the pull requests of a repository. Since and Until apply to
+// the time the pull request was opened.
+type PullRequestFilter struct {
+	State      string
+	Author     string
+	BaseBranch string
+	Since      *time.Time
+	Until      *time.Time
+}
+
+// PullRequestStats summarises the review flow of a set of pull requests.
+type PullRequestStats struct {
+	Total               int     `db:"total" json:"total"`
+	Open                int     `db:"open" json:"open"`
+	Closed              int     `db:"closed" json:"closed"`
+	Merged              int     `db:"merged" json:"merged"`
+	AverageHoursToMerge float64 `db:"average_hours_to_merge" json:"average_hours_to_merge"`
+	MedianHoursToMerge  float64 `db:"median_hours_to_merge" json:"median_hours_to_merge"`
+	P90HoursToMerge     float64 `db:"p90_hours_to_merge" json:"p90_hours_to_merge"`
+}
+
+const (
+	IssueStateOpen   = "open"
+	IssueStateClosed = "closed"
+)
+
+type Issue struct {
+	ID           int            `db:"id" json:"-"`
+	UID          uuid.UUID      `db:"uid" json:"id,omitempty"`
+	RepositoryID int            `db:"repository_id" json:"-"`
+	Number       int            `db:"number" json:"number"`
+	Title        string         `db:"title" json:"title"`
+	State        string         `db:"state" json:"state"`
+	Labels       pq.StringArray `db:"labels" json:"labels"`
+	AuthorLogin  string         `db:"author_login" json:"author"`
+	URL          string         `db:"url" json:"url"`
+	OpenedAt     time.Time      `db:"opened_at" json:"opened_at"`
+	UpdatedAt    time.Time      `db:"updated_at" json:"updated_at"`
+	ClosedAt     *time.Time     `db:"closed_at" json:"closed_at"`
+	CreatedAt    time.Time      `db:"created_at" json:"-"`
+}
+
+// IssueFilter narrows down the issues of a repository.
+type IssueFilter struct {
+	State string
+	Label string
+}
+
+// IssueReference is an issue referenced by a commit message. The issue details are only set when
+// the issue has been ingested.
+type IssueReference struct {
+	OwnerName      string  `db:"owner_name" json:"owner_name"`
+	RepositoryName string  `db:"repository_name" json:"repository_name"`
+	Number         int     `db:"issue_number" json:"number"`
+	Keyword        string  `db:"keyword" json:"keyword"`
+	Title          *string `db:"title" json:"title,omitempty"`
+	State          *string `db:"state" json:"state,omitempty"`
+	URL            *string `db:"url" json:"url,omitempty"`
+}
+
+type Tag struct {
+	ID           int       `db:"id" json:"-"`
+	UID          uuid.UUID `db:"uid" json:"id,omitempty"`
+	RepositoryID int       `db:"repository_id" json:"-"`
+	Name         string    `db:"name" json:"name"`
+	SHA          string    `db:"sha" json:"sha"`
+	CommitID     *int      `db:"commit_id" json:"-"`
+	CreatedAt    time.Time `db:"created_at" json:"-"`
+}
+
+type Release struct {
+	ID           int        `db:"id" json:"-"`
+	UID          uuid.UUID  `db:"uid" json:"id,omitempty"`
+	RepositoryID int        `db:"repository_id" json:"-"`
+	GithubID     int64      `db:"github_id" json:"-"`
+	TagName      string     `db:"tag_name" json:"tag_name"`
+	Name         string     `db:"name" json:"name"`
+	SHA          string     `db:"sha" json:"sha,omitempty"`
+	CommitID     *int       `db:"commit_id" json:"-"`
+	CommitUID    *uuid.UUID `db:"commit_uid" json:"commit_id,omitempty"`
+	Draft        bool       `db:"draft" json:"draft"`
+	Prerelease   bool       `db:"prerelease" json:"prerelease"`
+	AuthorLogin  string     `db:"author_login" json:"author"`
+	URL          string     `db:"url" json:"url"`
+	PublishedAt  *time.Time `db:"published_at" json:"published_at"`
+	CreatedAt    time.Time  `db:"created_at" json:"-"`
+}
+
+// ReleaseCadence describes how often a repository ships. Commits per release counts the commits
+// authored between a release and the one before it.
+type ReleaseCadence struct {
+	Releases                   int        `db:"releases" json:"releases"`
+	LastReleaseAt              *time.Time `db:"last_release_at" json:"last_release_at"`
+	AverageDaysBetweenReleases float64    `db:"average_days_between_releases" json:"average_days_between_releases"`
+	MedianDaysBetweenReleases  float64    `db:"median_days_between_releases" json:"median_days_between_releases"`
+	AverageCommitsPerRelease   float64    `db:"average_commits_per_release" json:"average_commits_per_release"`
+	MedianCommitsPerRelease    float64    `db:"median_commits_per_release" json:"median_commits_per_release"`
 }
 
 type Author struct {
@@ -42,13 +306,176 @@ type Author struct {
 	UID   uuid.UUID `db:"uid" json:"id,omitempty"`
 	Name  string    `db:"name" json:"name"`
 	Email string    `db:"email" json:"email"`
+	// IsBot flags bot and automation accounts, e.g. GitHub Apps or accounts matching the configured patterns.
+	IsBot bool `db:"is_bot" json:"is_bot"`
+}
+
+// AuthorIdentity is a canonical author with the other identities, e.g. former emails, resolved to
+// it. Author aggregates count the commits of aliases for their canonical author.
+type AuthorIdentity struct {
+	Author
+	Aliases []Author `json:"aliases"`
+}
+
+// How an author identity was resolved. Identities merged or split by hand are left alone by .mailmap rules.
+const (
+	AuthorSourceMailmap = "mailmap"
+	AuthorSourceManual  = "manual"
+)
+
+// MailmapImport reports the .mailmap rules stored for a repository and how many author identities
+// they resolved to another.
+type MailmapImport struct {
+	Entries int `json:"entries"`
+	Aliases int `json:"aliases"`
 }
 
 type CommitAuthor struct {
 	Author
-	CommitCount int `db:"commit_count" json:"commit_count"`
+	CommitCount  int `db:"commit_count" json:"commit_count"`
+	LinesAdded   int `db:"lines_added" json:"lines_added"`
+	LinesRemoved int `db:"lines_removed" json:"lines_removed"`
+	// CoAuthoredCount is how many of the commits were co-authored, only counted with AuthorFilter.IncludeCoAuthors.
+	CoAuthoredCount int `db:"co_authored_count" json:"co_authored_count,omitempty"`
+}
+
+// TopReviewer is someone credited by the "Reviewed-by:" trailers of commits.
+type TopReviewer struct {
+	Name        string `db:"name" json:"name"`
+	Email       string `db:"email" json:"email"`
+	ReviewCount int    `db:"review_count" json:"review_count"`
+}
+
+// Roles of the people credited with a commit.
+const (
+	CommitAuthorRoleAuthor    = "author"
+	CommitAuthorRoleCoAuthor  = "co-author"
+	CommitAuthorRoleCommitter = "committer"
+)
+
+type AuthorFilter struct {
+	// IncludeCoAuthors credits commits to their co-authors as well as to their author.
+	IncludeCoAuthors bool
+	// Bots is BotsExclude or BotsOnly to leave out or only keep bot authors, both kinds being kept when empty.
+	Bots string
+}
+
+// Filters on whether authors are bots.
+const (
+	BotsExclude = "exclude"
+	BotsOnly    = "only"
+)
+
+// BotShare splits the commits of a repository, and their authors, between bots and people.
+type BotShare struct {
+	TotalCommits int `db:"total_commits" json:"total_commits"`
+	BotCommits   int `db:"bot_commits" json:"bot_commits"`
+	HumanCommits int `db:"human_commits" json:"human_commits"`
+	// BotCommitShare is the fraction of commits authored by bots, from 0 to 1.
+	BotCommitShare float64 `db:"-" json:"bot_commit_share"`
+	TotalAuthors   int     `db:"total_authors" json:"total_authors"`
+	BotAuthors     int     `db:"bot_authors" json:"bot_authors"`
+	HumanAuthors   int     `db:"human_authors" json:"human_authors"`
 }
 
 type PaginatedCommits struct{}
 
 type Pagination struct{}
+
+// ETag holds the cache validators returned by the GitHub API for a request URL.
+type ETag struct {
+	ID           int       `db:"id" json:"-"`
+	URL          string    `db:"url" json:"url"`
+	ETag         string    `db:"etag" json:"etag"`
+	LastModified string    `db:"last_modified" json:"last_modified"`
+	UpdatedAt    time.Time `db:"updated_at" json:"updated_at"`
+}
+
+// RateLimit describes the GitHub request budget currently available to lema.
+type RateLimit struct {
+	Limit        int       `json:"limit"`
+	Remaining    int       `json:"remaining"`
+	Reset        time.Time `json:"reset"`
+	BlockedUntil time.Time `json:"blocked_until"`
+}
+
+const (
+	TokenStatusActive    = "active"
+	TokenStatusExhausted = "exhausted"
+	TokenStatusRetired   = "retired"
+)
+
+// TokenHealth describes the state of a GitHub token without exposing its value.
+type TokenHealth struct {
+	ID          string     `json:"id"`
+	Fingerprint string     `json:"fingerprint"`
+	Status      string     `json:"status"`
+	Limit       int        `json:"limit"`
+	Remaining   int        `json:"remaining"`
+	Reset       time.Time  `json:"reset"`
+	LastUsedAt  *time.Time `json:"last_used_at"`
+	RetiredAt   *time.Time `json:"retired_at,omitempty"`
+}
+
+const (
+	WebhookOutcomePong       = "pong"
+	WebhookOutcomeDuplicate  = "duplicate"
+	WebhookOutcomeIgnored    = "ignored"
+	WebhookOutcomeStored     = "stored"
+	WebhookOutcomeEnqueued   = "enqueued"
+	WebhookOutcomeRenamed    = "renamed"
+	WebhookOutcomeStateSaved = "state_saved"
+)
+
+// WebhookResult describes what was done with a webhook delivery.
+type WebhookResult struct {
+	Event   string `json:"event"`
+	Outcome string `json:"outcome"`
+	// Commits is how many pushed commits were stored.
+	Commits int `json:"commits,omitempty"`
+}
+
+const (
+	SnapshotSourceSnapshot   = "snapshot"
+	SnapshotSourceStargazers = "stargazers"
+)
+
+// RepositorySnapshot records the popularity counters of a repository at a point in time. Counters
+// unknown to the source of the snapshot are nil, e.g. all but stars for backfilled star history.
+type RepositorySnapshot struct {
+	ID              int       `db:"id" json:"-"`
+	RepositoryID    int       `db:"repository_id" json:"-"`
+	Source          string    `db:"source" json:"source"`
+	StarsCount      *int      `db:"stars_count" json:"stars_count"`
+	ForksCount      *int      `db:"forks_count" json:"forks_count"`
+	WatchersCount   *int      `db:"watchers_count" json:"watchers_count"`
+	OpenIssuesCount *int      `db:"open_issues_count" json:"open_issues_count"`
+	RecordedAt      time.Time `db:"recorded_at" json:"recorded_at"`
+}
+
+// Stargazer is a user who starred a repository.
+type Stargazer struct {
+	Login     string
+	StarredAt time.Time
+}
+
+const (
+	PopularityIntervalDay  = "day"
+	PopularityIntervalWeek = "week"
+)
+
+type PopularityFilter struct {
+	// Interval is the size of the buckets, PopularityIntervalDay or PopularityIntervalWeek.
+	Interval string
+	Since    *time.Time
+	Until    *time.Time
+}
+
+// PopularityPoint holds the latest known counters of a repository within a bucket of its popularity history.
+type PopularityPoint struct {
+	Bucket          time.Time `db:"bucket" json:"date"`
+	StarsCount      *int      `db:"stars_count" json:"stars_count"`
+	ForksCount      *int      `db:"forks_count" json:"forks_count"`
+	WatchersCount   *int      `db:"watchers_count" json:"watchers_count"`
+	OpenIssuesCount *int      `db:"open_issues_count" json:"open_issues_count"`
+}
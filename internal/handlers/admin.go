@@ -0,0 +1,47 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/babyfaceeasy/lema/internal/messages"
+	"github.com/babyfaceeasy/lema/internal/utils"
+	"go.uber.org/zap"
+)
+
+// GetGithubTokens reports the health of the configured GitHub tokens and the overall budget.
+// Token values are never returned, only an identifier and a fingerprint.
+func (h Handler) GetGithubTokens(w http.ResponseWriter, r *http.Request) {
+	logr := h.logger.With(zap.String("method", "GetGithubTokens"))
+
+	tokens, err := h.githubService.GetTokenHealth(r.Context())
+	if err != nil {
+		logr.Error("error in getting github token health", zap.Error(err))
+		code, res := h.response(http.StatusInternalServerError, ResponseFormat{
+			Status:  false,
+			Message: messages.SomethingWentWrong,
+		})
+		utils.SendResponse(w, code, res)
+		return
+	}
+
+	budget, err := h.githubService.GetRateLimit(r.Context())
+	if err != nil {
+		logr.Error("error in getting github rate limit", zap.Error(err))
+		code, res := h.response(http.StatusInternalServerError, ResponseFormat{
+			Status:  false,
+			Message: messages.SomethingWentWrong,
+		})
+		utils.SendResponse(w, code, res)
+		return
+	}
+
+	code, res := h.response(http.StatusOK, ResponseFormat{
+		Status:  true,
+		Message: "GitHub token health retrieved successfully",
+		Data: map[string]any{
+			"rate_limit": budget,
+			"tokens":     tokens,
+		},
+	})
+	utils.SendResponse(w, code, res)
+}
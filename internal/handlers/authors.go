@@ -0,0 +1,208 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+
+	"github.com/babyfaceeasy/lema/internal/domain"
+	"github.com/babyfaceeasy/lema/internal/messages"
+	"github.com/babyfaceeasy/lema/internal/utils"
+	validation "github.com/go-ozzo/ozzo-validation/v4"
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+	"go.uber.org/zap"
+)
+
+// maxMailmapSize bounds the size of an uploaded .mailmap file.
+const maxMailmapSize = 4 << 20
+
+// sendAuthorError answers a request to the author or mailmap endpoints that failed with err.
+func (h Handler) sendAuthorError(w http.ResponseWriter, logr *zap.Logger, err error) {
+	switch {
+	case errors.Is(err, domain.ErrNotFound):
+		code, res := h.response(http.StatusNotFound, ResponseFormat{
+			Status:  false,
+			Message: messages.NotFound,
+			Error:   []string{err.Error()},
+		})
+		utils.SendResponse(w, code, res)
+	case errors.Is(err, domain.ErrInvalidAuthorMerge), errors.Is(err, domain.ErrUnsupportedByProvider):
+		code, res := h.response(http.StatusBadRequest, ResponseFormat{
+			Status:  false,
+			Message: messages.InvalidRequest,
+			Error:   []string{err.Error()},
+		})
+		utils.SendResponse(w, code, res)
+	default:
+		logr.Error("error in resolving author identities", zap.Error(err))
+		code, res := h.response(http.StatusInternalServerError, ResponseFormat{
+			Status:  false,
+			Message: messages.SomethingWentWrong,
+		})
+		utils.SendResponse(w, code, res)
+	}
+}
+
+// authorID reads the author_id path parameter, writing a bad request response and returning false
+// when it is not a UUID.
+func (h Handler) authorID(w http.ResponseWriter, r *http.Request) (uuid.UUID, bool) {
+	uid, err := uuid.Parse(mux.Vars(r)["author_id"])
+	if err != nil {
+		code, res := h.response(http.StatusBadRequest, ResponseFormat{
+			Status:  false,
+			Message: messages.InvalidRequest,
+			Error:   []string{"Invalid author id"},
+		})
+		utils.SendResponse(w, code, res)
+		return uuid.Nil, false
+	}
+	return uid, true
+}
+
+// GetAuthorIdentity returns the canonical author an author resolves to, with its aliases.
+func (h Handler) GetAuthorIdentity(w http.ResponseWriter, r *http.Request) {
+	logr := h.logger.With(zap.String("method", "GetAuthorIdentity"))
+
+	uid, ok := h.authorID(w, r)
+	if !ok {
+		return
+	}
+
+	identity, err := h.authorService.GetAuthorIdentity(r.Context(), uid)
+	if err != nil {
+		h.sendAuthorError(w, logr, err)
+		return
+	}
+
+	code, res := h.response(http.StatusOK, ResponseFormat{
+		Status:  true,
+		Message: "Author identity retrieved successfully",
+		Data:    identity,
+	})
+	utils.SendResponse(w, code, res)
+}
+
+// MergeAuthors resolves an author, and its aliases, to the canonical author of another one.
+func (h Handler) MergeAuthors(w http.ResponseWriter, r *http.Request) {
+	logr := h.logger.With(zap.String("method", "MergeAuthors"))
+
+	var req mergeAuthorsRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		code, res := h.response(http.StatusBadRequest, ResponseFormat{
+			Status:  false,
+			Message: "invalid payload request",
+		})
+		utils.SendResponse(w, code, res)
+		return
+	}
+	defer r.Body.Close()
+
+	if err := req.Validate(); err != nil {
+		code, res := h.response(http.StatusBadRequest, ResponseFormat{
+			Status:  false,
+			Message: messages.InvalidRequest,
+		})
+		if verrs, ok := err.(validation.Errors); ok {
+			res.Data = h.withValidationErrors(verrs)
+		}
+		utils.SendResponse(w, code, res)
+		return
+	}
+
+	identity, err := h.authorService.MergeAuthors(r.Context(), uuid.MustParse(req.SourceID), uuid.MustParse(req.TargetID))
+	if err != nil {
+		h.sendAuthorError(w, logr, err)
+		return
+	}
+
+	code, res := h.response(http.StatusOK, ResponseFormat{
+		Status:  true,
+		Message: "Authors merged successfully",
+		Data:    identity,
+	})
+	utils.SendResponse(w, code, res)
+}
+
+// SplitAuthor detaches an author from the author it resolved to, or its aliases from it.
+func (h Handler) SplitAuthor(w http.ResponseWriter, r *http.Request) {
+	logr := h.logger.With(zap.String("method", "SplitAuthor"))
+
+	uid, ok := h.authorID(w, r)
+	if !ok {
+		return
+	}
+
+	identity, err := h.authorService.SplitAuthor(r.Context(), uid)
+	if err != nil {
+		h.sendAuthorError(w, logr, err)
+		return
+	}
+
+	code, res := h.response(http.StatusOK, ResponseFormat{
+		Status:  true,
+		Message: "Author split successfully",
+		Data:    identity,
+	})
+	utils.SendResponse(w, code, res)
+}
+
+// ImportRepositoryMailmap replaces the .mailmap rules of a repository with the request body.
+func (h Handler) ImportRepositoryMailmap(w http.ResponseWriter, r *http.Request) {
+	logr := h.logger.With(zap.String("method", "ImportRepositoryMailmap"))
+
+	repositoryName := mux.Vars(r)["repository_name"]
+	ownerName := r.URL.Query().Get("owner_name")
+	if ownerName == "" {
+		ownerName = repositoryName
+	}
+
+	contents, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxMailmapSize))
+	if err != nil {
+		code, res := h.response(http.StatusBadRequest, ResponseFormat{
+			Status:  false,
+			Message: messages.InvalidRequest,
+			Error:   []string{err.Error()},
+		})
+		utils.SendResponse(w, code, res)
+		return
+	}
+
+	result, err := h.authorService.ImportMailmap(r.Context(), ownerName, repositoryName, string(contents))
+	if err != nil {
+		h.sendAuthorError(w, logr, err)
+		return
+	}
+
+	code, res := h.response(http.StatusOK, ResponseFormat{
+		Status:  true,
+		Message: "Mailmap imported successfully",
+		Data:    result,
+	})
+	utils.SendResponse(w, code, res)
+}
+
+// SyncRepositoryMailmap imports the .mailmap file of the default branch of a GitHub repository.
+func (h Handler) SyncRepositoryMailmap(w http.ResponseWriter, r *http.Request) {
+	logr := h.logger.With(zap.String("method", "SyncRepositoryMailmap"))
+
+	repositoryName := mux.Vars(r)["repository_name"]
+	ownerName := r.URL.Query().Get("owner_name")
+	if ownerName == "" {
+		ownerName = repositoryName
+	}
+
+	result, err := h.authorService.SyncMailmap(r.Context(), ownerName, repositoryName)
+	if err != nil {
+		h.sendAuthorError(w, logr, err)
+		return
+	}
+
+	code, res := h.response(http.StatusOK, ResponseFormat{
+		Status:  true,
+		Message: "Mailmap imported successfully",
+		Data:    result,
+	})
+	utils.SendResponse(w, code, res)
+}
@@ -7,18 +7,26 @@ import (
 	"github.com/babyfaceeasy/lema/internal/domain"
 	"github.com/babyfaceeasy/lema/internal/messages"
 	"github.com/babyfaceeasy/lema/internal/queue"
+	"github.com/babyfaceeasy/lema/internal/services/githubservice"
 	"github.com/babyfaceeasy/lema/internal/store"
 	validation "github.com/go-ozzo/ozzo-validation/v4"
 	"go.uber.org/zap"
 )
 
 type Handler struct {
-	config            *config.Config
-	logger            *zap.Logger
-	store             *store.Store
-	commitService     domain.CommitService
-	repositoryService domain.RepositoryService
-	taskQueue         queue.TaskQueue
+	config             *config.Config
+	logger             *zap.Logger
+	store              *store.Store
+	commitService      domain.CommitService
+	repositoryService  domain.RepositoryService
+	pullRequestService domain.PullRequestService
+	issueService       domain.IssueService
+	releaseService     domain.ReleaseService
+	webhookService     domain.WebhookService
+	popularityService  domain.PopularityService
+	authorService      domain.AuthorService
+	githubService      githubservice.GitHubService
+	taskQueue          queue.TaskQueue
 }
 
 func New(
@@ -27,10 +35,31 @@ func New(
 	store *store.Store,
 	commitService domain.CommitService,
 	repositoryService domain.RepositoryService,
+	pullRequestService domain.PullRequestService,
+	issueService domain.IssueService,
+	releaseService domain.ReleaseService,
+	webhookService domain.WebhookService,
+	popularityService domain.PopularityService,
+	authorService domain.AuthorService,
+	githubService githubservice.GitHubService,
 	taskQueue queue.TaskQueue,
 ) *Handler {
 	logger = logger.With(zap.String("package", "handlers"))
-	return &Handler{config: config, logger: logger, store: store, commitService: commitService, repositoryService: repositoryService, taskQueue: taskQueue}
+	return &Handler{
+		config:             config,
+		logger:             logger,
+		store:              store,
+		commitService:      commitService,
+		repositoryService:  repositoryService,
+		pullRequestService: pullRequestService,
+		issueService:       issueService,
+		releaseService:     releaseService,
+		webhookService:     webhookService,
+		popularityService:  popularityService,
+		authorService:      authorService,
+		githubService:      githubService,
+		taskQueue:          taskQueue,
+	}
 }
 
 type ResponseFormat struct {
@@ -0,0 +1,125 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/babyfaceeasy/lema/internal/messages"
+	"github.com/babyfaceeasy/lema/internal/tasks"
+	"github.com/babyfaceeasy/lema/internal/utils"
+	validation "github.com/go-ozzo/ozzo-validation/v4"
+	"github.com/gorilla/mux"
+	"go.uber.org/zap"
+)
+
+// GetRepositoryBranches lists the monitored branches of a repository with their last sync time.
+func (h Handler) GetRepositoryBranches(w http.ResponseWriter, r *http.Request) {
+	logr := h.logger.With(zap.String("method", "GetRepositoryBranches"))
+
+	repositoryName := mux.Vars(r)["repository_name"]
+	ownerName := r.URL.Query().Get("owner_name")
+	if ownerName == "" {
+		ownerName = repositoryName
+	}
+
+	repoDetails, err := h.repositoryService.GetRepository(r.Context(), ownerName, repositoryName)
+	if err != nil || repoDetails == nil {
+		code, res := h.response(http.StatusNotFound, ResponseFormat{
+			Status:  false,
+			Message: messages.NotFound,
+		})
+		utils.SendResponse(w, code, res)
+		return
+	}
+
+	branches, err := h.repositoryService.GetRepositoryBranches(r.Context(), repoDetails.OwnerName, repoDetails.Name)
+	if err != nil {
+		logr.Error("error in getting repository branches", zap.Error(err))
+		code, res := h.response(http.StatusInternalServerError, ResponseFormat{
+			Status:  false,
+			Message: messages.SomethingWentWrong,
+		})
+		utils.SendResponse(w, code, res)
+		return
+	}
+
+	code, res := h.response(http.StatusOK, ResponseFormat{
+		Status:  true,
+		Message: "Branches retrieved successfully",
+		Data:    branches,
+	})
+	utils.SendResponse(w, code, res)
+}
+
+// AddRepositoryBranches starts monitoring more branches of an already monitored repository.
+func (h Handler) AddRepositoryBranches(w http.ResponseWriter, r *http.Request) {
+	logr := h.logger.With(zap.String("method", "AddRepositoryBranches"))
+
+	repositoryName := strings.ToLower(mux.Vars(r)["repository_name"])
+
+	var req addBranchesRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		code, res := h.response(http.StatusBadRequest, ResponseFormat{
+			Status:  false,
+			Message: "invalid payload request",
+		})
+		utils.SendResponse(w, code, res)
+		return
+	}
+	defer r.Body.Close()
+
+	if err := req.Validate(); err != nil {
+		code, res := h.response(http.StatusBadRequest, ResponseFormat{
+			Status:  false,
+			Message: messages.InvalidRequest,
+		})
+		if verrs, ok := err.(validation.Errors); ok {
+			res.Data = h.withValidationErrors(verrs)
+		}
+		utils.SendResponse(w, code, res)
+		return
+	}
+
+	repoDetails, err := h.repositoryService.GetRepository(r.Context(), strings.ToLower(req.OwnerName), repositoryName)
+	if err != nil || repoDetails == nil {
+		code, res := h.response(http.StatusNotFound, ResponseFormat{
+			Status:  false,
+			Message: messages.NotFound,
+		})
+		utils.SendResponse(w, code, res)
+		return
+	}
+
+	if err := h.repositoryService.AddRepositoryBranches(r.Context(), repoDetails.OwnerName, repoDetails.Name, req.Branches); err != nil {
+		logr.Error("error in saving repository branches", zap.Error(err))
+		code, res := h.response(http.StatusInternalServerError, ResponseFormat{
+			Status:  false,
+			Message: messages.SomethingWentWrong,
+		})
+		utils.SendResponse(w, code, res)
+		return
+	}
+
+	for _, branch := range req.Branches {
+		if branch == repoDetails.DefaultBranch {
+			continue
+		}
+		if err := tasks.CallBranchCommitsTask(repoDetails.Host, repoDetails.OwnerName, repoDetails.Name, branch); err != nil {
+			logr.Error("error in creating task to sync branch", zap.String("branch", branch), zap.Error(err))
+			code, res := h.response(http.StatusInternalServerError, ResponseFormat{
+				Status:  false,
+				Message: messages.SomethingWentWrong,
+			})
+			utils.SendResponse(w, code, res)
+			return
+		}
+	}
+
+	code, res := h.response(http.StatusOK, ResponseFormat{
+		Status:  true,
+		Message: fmt.Sprintf("Monitoring started for %d branches of repository named %s/%s", len(req.Branches), repoDetails.OwnerName, repoDetails.Name),
+	})
+	utils.SendResponse(w, code, res)
+}
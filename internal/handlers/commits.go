@@ -4,8 +4,10 @@ import (
 	"net/http"
 	"strconv"
 
+	"github.com/babyfaceeasy/lema/internal/domain"
 	"github.com/babyfaceeasy/lema/internal/messages"
 	"github.com/babyfaceeasy/lema/internal/utils"
+	"github.com/gorilla/mux"
 	"go.uber.org/zap"
 )
 
@@ -31,7 +33,21 @@ func (h Handler) GetTopCommitAuthors(w http.ResponseWriter, r *http.Request) {
 	}
 	logr.Info("value of query parameters passed", zap.Int("limit", limit))
 
-	authors, err := h.commitService.GetTopCommitAuthors(r.Context(), "chronuim", "chronuim", limit)
+	bots, err := parseBotsFilter(r)
+	if err != nil {
+		code, res := h.response(http.StatusBadRequest, ResponseFormat{
+			Status:  false,
+			Message: messages.InvalidRequest,
+			Error:   []string{err.Error()},
+		})
+		utils.SendResponse(w, code, res)
+		return
+	}
+
+	includeCoAuthors, _ := strconv.ParseBool(r.URL.Query().Get("include_co_authors"))
+	filter := domain.AuthorFilter{IncludeCoAuthors: includeCoAuthors, Bots: bots}
+
+	authors, err := h.commitService.GetTopCommitAuthors(r.Context(), "chronuim", "chronuim", filter, limit)
 	if err != nil {
 		logr.Error("an error occurred", zap.Error(err))
 
@@ -51,3 +67,134 @@ func (h Handler) GetTopCommitAuthors(w http.ResponseWriter, r *http.Request) {
 	})
 	utils.SendResponse(w, code, res)
 }
+
+// GetTopCommitReviewers returns the people most often credited by "Reviewed-by:" trailers of the
+// commits of a host, or of one repository when owner_name and repository_name are given.
+func (h Handler) GetTopCommitReviewers(w http.ResponseWriter, r *http.Request) {
+	logr := h.logger.With(zap.String("method", "GetTopCommitReviewers"))
+
+	limitStr := r.URL.Query().Get("limit")
+	if limitStr == "" {
+		limitStr = "10"
+	}
+
+	limit, err := strconv.Atoi(limitStr)
+	if err != nil || limit < 1 {
+		code, res := h.response(http.StatusBadRequest, ResponseFormat{
+			Status:  false,
+			Message: messages.InvalidRequest,
+			Error:   []string{"Invalid limit value"},
+		})
+		utils.SendResponse(w, code, res)
+		return
+	}
+
+	ownerName := r.URL.Query().Get("owner_name")
+	repoName := r.URL.Query().Get("repository_name")
+	if (ownerName == "") != (repoName == "") {
+		code, res := h.response(http.StatusBadRequest, ResponseFormat{
+			Status:  false,
+			Message: messages.InvalidRequest,
+			Error:   []string{"owner_name and repository_name must be given together"},
+		})
+		utils.SendResponse(w, code, res)
+		return
+	}
+
+	reviewers, err := h.commitService.GetTopReviewers(r.Context(), ownerName, repoName, limit)
+	if err != nil {
+		logr.Error("error in getting top reviewers", zap.Error(err))
+		code, res := h.response(http.StatusInternalServerError, ResponseFormat{
+			Status:  false,
+			Message: messages.SomethingWentWrong,
+		})
+		utils.SendResponse(w, code, res)
+		return
+	}
+
+	code, res := h.response(http.StatusOK, ResponseFormat{
+		Status:  true,
+		Message: "Top reviewers retrieved successfully",
+		Data:    reviewers,
+	})
+	utils.SendResponse(w, code, res)
+}
+
+// GetCommitReview returns the code review a commit went through, for repositories hosted on a
+// review-based provider such as Gerrit.
+func (h Handler) GetCommitReview(w http.ResponseWriter, r *http.Request) {
+	logr := h.logger.With(zap.String("method", "GetCommitReview"))
+
+	repositoryName := mux.Vars(r)["repository_name"]
+	sha := mux.Vars(r)["sha"]
+	ownerName := r.URL.Query().Get("owner_name")
+	if ownerName == "" {
+		ownerName = repositoryName
+	}
+
+	review, err := h.commitService.GetCommitReview(r.Context(), ownerName, repositoryName, sha)
+	if err != nil {
+		logr.Error("error in getting review of commit", zap.Error(err))
+		code, res := h.response(http.StatusBadRequest, ResponseFormat{
+			Status:  false,
+			Message: messages.SomethingWentWrong,
+		})
+		utils.SendResponse(w, code, res)
+		return
+	}
+	if review == nil {
+		code, res := h.response(http.StatusNotFound, ResponseFormat{
+			Status:  false,
+			Message: messages.NotFound,
+		})
+		utils.SendResponse(w, code, res)
+		return
+	}
+
+	code, res := h.response(http.StatusOK, ResponseFormat{
+		Status:  true,
+		Message: "Review retrieved successfully",
+		Data:    review,
+	})
+	utils.SendResponse(w, code, res)
+}
+
+// GetRepositoryBotShare splits the commits of a repository, and their authors, between bots and
+// people. It accepts the filters of the commits endpoint besides exclude_bots and only_bots.
+func (h Handler) GetRepositoryBotShare(w http.ResponseWriter, r *http.Request) {
+	logr := h.logger.With(zap.String("method", "GetRepositoryBotShare"))
+
+	repositoryName := mux.Vars(r)["repository_name"]
+	ownerName := r.URL.Query().Get("owner_name")
+	if ownerName == "" {
+		ownerName = repositoryName
+	}
+
+	filter, err := parseCommitFilter(r)
+	if err != nil {
+		code, res := h.response(http.StatusBadRequest, ResponseFormat{
+			Status:  false,
+			Message: err.Error(),
+		})
+		utils.SendResponse(w, code, res)
+		return
+	}
+
+	share, err := h.commitService.GetBotShare(r.Context(), ownerName, repositoryName, filter)
+	if err != nil {
+		logr.Error("error in getting bot share", zap.Error(err))
+		code, res := h.response(http.StatusBadRequest, ResponseFormat{
+			Status:  false,
+			Message: messages.SomethingWentWrong,
+		})
+		utils.SendResponse(w, code, res)
+		return
+	}
+
+	code, res := h.response(http.StatusOK, ResponseFormat{
+		Status:  true,
+		Message: "Bot share retrieved successfully",
+		Data:    share,
+	})
+	utils.SendResponse(w, code, res)
+}
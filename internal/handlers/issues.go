@@ -0,0 +1,141 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/babyfaceeasy/lema/internal/domain"
+	"github.com/babyfaceeasy/lema/internal/messages"
+	"github.com/babyfaceeasy/lema/internal/utils"
+	"github.com/babyfaceeasy/lema/pkg/pagination"
+	"github.com/gorilla/mux"
+	"go.uber.org/zap"
+)
+
+func (h Handler) GetRepositoryIssues(w http.ResponseWriter, r *http.Request) {
+	logr := h.logger.With(zap.String("method", "GetRepositoryIssues"))
+
+	repositoryName := mux.Vars(r)["repository_name"]
+	ownerName := r.URL.Query().Get("owner_name")
+	if ownerName == "" {
+		ownerName = repositoryName
+	}
+
+	page, pageSize, _ := pagination.ParsePaginationParams(r.URL.Query())
+
+	filter := domain.IssueFilter{Label: r.URL.Query().Get("label")}
+	switch state := strings.ToLower(r.URL.Query().Get("state")); state {
+	case "", "all":
+	case domain.IssueStateOpen, domain.IssueStateClosed:
+		filter.State = state
+	default:
+		code, res := h.response(http.StatusBadRequest, ResponseFormat{
+			Status:  false,
+			Message: "Invalid 'state', must be one of open or closed",
+		})
+		utils.SendResponse(w, code, res)
+		return
+	}
+
+	issues, pg, err := h.issueService.GetIssues(r.Context(), ownerName, repositoryName, filter, page, pageSize)
+	if err != nil {
+		logr.Error("error in getting stored issues", zap.Error(err))
+		code, res := h.response(http.StatusBadRequest, ResponseFormat{
+			Status:  false,
+			Message: messages.SomethingWentWrong,
+		})
+		utils.SendResponse(w, code, res)
+		return
+	}
+
+	code, res := h.response(http.StatusOK, ResponseFormat{
+		Status:  true,
+		Message: "Issues retrieved successfully",
+		Data:    pagination.PagedResponse{Pagination: pg, Data: issues},
+	})
+	utils.SendResponse(w, code, res)
+}
+
+// GetIssueCommits returns the commits of a repository whose message references the given issue.
+// It accepts the same filters as the commit listing.
+func (h Handler) GetIssueCommits(w http.ResponseWriter, r *http.Request) {
+	logr := h.logger.With(zap.String("method", "GetIssueCommits"))
+
+	repositoryName := mux.Vars(r)["repository_name"]
+	ownerName := r.URL.Query().Get("owner_name")
+	if ownerName == "" {
+		ownerName = repositoryName
+	}
+
+	issueNumber, err := strconv.Atoi(mux.Vars(r)["issue_number"])
+	if err != nil || issueNumber <= 0 {
+		code, res := h.response(http.StatusBadRequest, ResponseFormat{
+			Status:  false,
+			Message: "Invalid issue number",
+		})
+		utils.SendResponse(w, code, res)
+		return
+	}
+
+	page, pageSize, _ := pagination.ParsePaginationParams(r.URL.Query())
+
+	filter, err := parseCommitFilter(r)
+	if err != nil {
+		code, res := h.response(http.StatusBadRequest, ResponseFormat{
+			Status:  false,
+			Message: err.Error(),
+		})
+		utils.SendResponse(w, code, res)
+		return
+	}
+	filter.Issue = issueNumber
+
+	commits, pg, err := h.commitService.GetCommitsByRepositoryName(r.Context(), ownerName, repositoryName, filter, page, pageSize)
+	if err != nil {
+		logr.Error("error in getting commits of issue", zap.Error(err))
+		code, res := h.response(http.StatusBadRequest, ResponseFormat{
+			Status:  false,
+			Message: messages.SomethingWentWrong,
+		})
+		utils.SendResponse(w, code, res)
+		return
+	}
+
+	code, res := h.response(http.StatusOK, ResponseFormat{
+		Status:  true,
+		Message: "Commits retrieved successfully",
+		Data:    pagination.PagedResponse{Pagination: pg, Data: commits},
+	})
+	utils.SendResponse(w, code, res)
+}
+
+// GetCommitIssues returns the issues referenced by the message of a commit.
+func (h Handler) GetCommitIssues(w http.ResponseWriter, r *http.Request) {
+	logr := h.logger.With(zap.String("method", "GetCommitIssues"))
+
+	repositoryName := mux.Vars(r)["repository_name"]
+	sha := mux.Vars(r)["sha"]
+	ownerName := r.URL.Query().Get("owner_name")
+	if ownerName == "" {
+		ownerName = repositoryName
+	}
+
+	refs, err := h.issueService.GetCommitIssues(r.Context(), ownerName, repositoryName, sha)
+	if err != nil {
+		logr.Error("error in getting issues of commit", zap.Error(err))
+		code, res := h.response(http.StatusBadRequest, ResponseFormat{
+			Status:  false,
+			Message: messages.SomethingWentWrong,
+		})
+		utils.SendResponse(w, code, res)
+		return
+	}
+
+	code, res := h.response(http.StatusOK, ResponseFormat{
+		Status:  true,
+		Message: "Issues retrieved successfully",
+		Data:    refs,
+	})
+	utils.SendResponse(w, code, res)
+}
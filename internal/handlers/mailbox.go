@@ -0,0 +1,83 @@
+package handlers
+
+import (
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/babyfaceeasy/lema/internal/domain"
+	"github.com/babyfaceeasy/lema/internal/messages"
+	"github.com/babyfaceeasy/lema/internal/utils"
+	"github.com/gorilla/mux"
+	"go.uber.org/zap"
+)
+
+// maxMailboxSize bounds the size of an uploaded mailbox.
+const maxMailboxSize = 64 << 20
+
+// ImportMailbox stores the patches of an uploaded mbox file or git format-patch output under the
+// pseudo-repository {owner_name}/{repository_name} of the mailbox host. The mailbox is sent as the
+// "file" field of a multipart form, or as the request body.
+func (h Handler) ImportMailbox(w http.ResponseWriter, r *http.Request) {
+	logr := h.logger.With(zap.String("method", "ImportMailbox"))
+
+	repositoryName := strings.ToLower(mux.Vars(r)["repository_name"])
+	ownerName := strings.ToLower(r.URL.Query().Get("owner_name"))
+	if ownerName == "" {
+		ownerName = repositoryName
+	}
+
+	r.Body = http.MaxBytesReader(w, r.Body, maxMailboxSize)
+	var mbox io.Reader = r.Body
+	if strings.HasPrefix(r.Header.Get("Content-Type"), "multipart/form-data") {
+		file, _, err := r.FormFile("file")
+		if err != nil {
+			code, res := h.response(http.StatusBadRequest, ResponseFormat{
+				Status:  false,
+				Message: messages.InvalidRequest,
+				Error:   []string{"Missing 'file' field holding the mailbox"},
+			})
+			utils.SendResponse(w, code, res)
+			return
+		}
+		defer file.Close()
+		mbox = file
+	}
+
+	result, err := h.commitService.ImportMailbox(r.Context(), ownerName, repositoryName, mbox)
+	if err != nil {
+		logr.Error("error in importing mailbox", zap.Error(err))
+		code, res := h.response(http.StatusInternalServerError, ResponseFormat{
+			Status:  false,
+			Message: messages.SomethingWentWrong,
+		})
+		utils.SendResponse(w, code, res)
+		return
+	}
+
+	message := "Mailbox imported successfully"
+	if len(result.Errors) > 0 {
+		message = "Mailbox imported, some messages could not be read"
+	}
+	code, res := h.response(http.StatusOK, ResponseFormat{
+		Status:  true,
+		Message: message,
+		Data:    result,
+	})
+	utils.SendResponse(w, code, res)
+}
+
+// importedRepositoryRequested reports, and answers, requests to monitor or reset a repository of the
+// mailbox host, whose commits can only be imported.
+func (h Handler) importedRepositoryRequested(w http.ResponseWriter, r *http.Request) bool {
+	if domain.HostFromContext(r.Context()) != domain.MailboxHost {
+		return false
+	}
+
+	code, res := h.response(http.StatusBadRequest, ResponseFormat{
+		Status:  false,
+		Message: "Repositories of the mailbox host are imported, not synced",
+	})
+	utils.SendResponse(w, code, res)
+	return true
+}
@@ -0,0 +1,127 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/babyfaceeasy/lema/internal/domain"
+	"github.com/babyfaceeasy/lema/internal/messages"
+	"github.com/babyfaceeasy/lema/internal/tasks"
+	"github.com/babyfaceeasy/lema/internal/utils"
+	"github.com/gorilla/mux"
+	"go.uber.org/zap"
+)
+
+// parsePopularityFilter reads the interval, since and until query parameters of a popularity history.
+func parsePopularityFilter(r *http.Request) (domain.PopularityFilter, error) {
+	query := r.URL.Query()
+	filter := domain.PopularityFilter{Interval: domain.PopularityIntervalDay}
+
+	switch interval := strings.ToLower(query.Get("interval")); interval {
+	case "":
+	case domain.PopularityIntervalDay, domain.PopularityIntervalWeek:
+		filter.Interval = interval
+	default:
+		return filter, fmt.Errorf("Invalid 'interval', must be %s or %s", domain.PopularityIntervalDay, domain.PopularityIntervalWeek)
+	}
+
+	for name, dst := range map[string]**time.Time{"since": &filter.Since, "until": &filter.Until} {
+		value := query.Get(name)
+		if value == "" {
+			continue
+		}
+		t, err := time.Parse(time.RFC3339, value)
+		if err != nil {
+			return filter, fmt.Errorf("Invalid '%s' date format, must be RFC3339", name)
+		}
+		*dst = &t
+	}
+
+	return filter, nil
+}
+
+func (h Handler) GetRepositoryPopularity(w http.ResponseWriter, r *http.Request) {
+	logr := h.logger.With(zap.String("method", "GetRepositoryPopularity"))
+
+	repositoryName := mux.Vars(r)["repository_name"]
+	ownerName := r.URL.Query().Get("owner_name")
+	if ownerName == "" {
+		ownerName = repositoryName
+	}
+
+	filter, err := parsePopularityFilter(r)
+	if err != nil {
+		code, res := h.response(http.StatusBadRequest, ResponseFormat{
+			Status:  false,
+			Message: err.Error(),
+		})
+		utils.SendResponse(w, code, res)
+		return
+	}
+
+	points, err := h.popularityService.GetPopularityHistory(r.Context(), ownerName, repositoryName, filter)
+	if err != nil {
+		logr.Error("error in getting popularity history", zap.Error(err))
+		code, res := h.response(http.StatusBadRequest, ResponseFormat{
+			Status:  false,
+			Message: messages.SomethingWentWrong,
+		})
+		utils.SendResponse(w, code, res)
+		return
+	}
+
+	code, res := h.response(http.StatusOK, ResponseFormat{
+		Status:  true,
+		Message: "Popularity history retrieved successfully",
+		Data:    points,
+	})
+	utils.SendResponse(w, code, res)
+}
+
+// BackfillRepositoryStarHistory starts rebuilding the star history of a GitHub repository from its stargazers.
+func (h Handler) BackfillRepositoryStarHistory(w http.ResponseWriter, r *http.Request) {
+	logr := h.logger.With(zap.String("method", "BackfillRepositoryStarHistory"))
+
+	repositoryName := strings.ToLower(mux.Vars(r)["repository_name"])
+	ownerName := strings.ToLower(r.URL.Query().Get("owner_name"))
+	if ownerName == "" {
+		ownerName = repositoryName
+	}
+
+	repoDetails, err := h.repositoryService.GetRepository(r.Context(), ownerName, repositoryName)
+	if err != nil || repoDetails == nil {
+		code, res := h.response(http.StatusNotFound, ResponseFormat{
+			Status:  false,
+			Message: messages.NotFound,
+		})
+		utils.SendResponse(w, code, res)
+		return
+	}
+
+	if !repoDetails.HostedOnGithub() {
+		code, res := h.response(http.StatusBadRequest, ResponseFormat{
+			Status:  false,
+			Message: "Star history is only backfilled for GitHub repositories",
+		})
+		utils.SendResponse(w, code, res)
+		return
+	}
+
+	if err := tasks.CallStarHistoryTask(repoDetails.Host, repoDetails.OwnerName, repoDetails.Name); err != nil {
+		logr.Error("error in initiating the background task for star history", zap.Error(err))
+		code, res := h.response(http.StatusInternalServerError, ResponseFormat{
+			Status:  false,
+			Message: messages.SomethingWentWrong,
+		})
+		utils.SendResponse(w, code, res)
+		return
+	}
+
+	code, res := h.response(http.StatusAccepted, ResponseFormat{
+		Status:  true,
+		Message: fmt.Sprintf("Star history backfill started for repository named %s/%s", repoDetails.OwnerName, repoDetails.Name),
+	})
+	utils.SendResponse(w, code, res)
+}
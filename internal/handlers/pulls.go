@@ -0,0 +1,137 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/babyfaceeasy/lema/internal/domain"
+	"github.com/babyfaceeasy/lema/internal/messages"
+	"github.com/babyfaceeasy/lema/internal/utils"
+	"github.com/babyfaceeasy/lema/pkg/pagination"
+	"github.com/gorilla/mux"
+	"go.uber.org/zap"
+)
+
+// parsePullRequestFilter reads the state, author, base, since and until query parameters of a
+// pull request listing.
+func parsePullRequestFilter(r *http.Request) (domain.PullRequestFilter, error) {
+	query := r.URL.Query()
+	filter := domain.PullRequestFilter{
+		Author:     query.Get("author"),
+		BaseBranch: query.Get("base"),
+	}
+
+	switch state := strings.ToLower(query.Get("state")); state {
+	case "", "all":
+	case domain.PullRequestStateOpen, domain.PullRequestStateClosed, domain.PullRequestStateMerged:
+		filter.State = state
+	default:
+		return filter, fmt.Errorf("Invalid 'state', must be one of %s, %s or %s", domain.PullRequestStateOpen, domain.PullRequestStateClosed, domain.PullRequestStateMerged)
+	}
+
+	for name, dst := range map[string]**time.Time{"since": &filter.Since, "until": &filter.Until} {
+		value := query.Get(name)
+		if value == "" {
+			continue
+		}
+		t, err := time.Parse(time.RFC3339, value)
+		if err != nil {
+			return filter, fmt.Errorf("Invalid '%s' date format, must be RFC3339", name)
+		}
+		*dst = &t
+	}
+
+	return filter, nil
+}
+
+func (h Handler) GetRepositoryPullRequests(w http.ResponseWriter, r *http.Request) {
+	logr := h.logger.With(zap.String("method", "GetRepositoryPullRequests"))
+
+	repositoryName := mux.Vars(r)["repository_name"]
+	ownerName := r.URL.Query().Get("owner_name")
+	if ownerName == "" {
+		ownerName = repositoryName
+	}
+
+	// pagination parameters
+	page, err := strconv.Atoi(r.URL.Query().Get("page"))
+	if err != nil || page <= 0 {
+		page = 1
+	}
+
+	pageSize, err := strconv.Atoi(r.URL.Query().Get("page_size"))
+	if err != nil || pageSize <= 0 {
+		pageSize = 10
+	}
+
+	filter, err := parsePullRequestFilter(r)
+	if err != nil {
+		code, res := h.response(http.StatusBadRequest, ResponseFormat{
+			Status:  false,
+			Message: err.Error(),
+		})
+		utils.SendResponse(w, code, res)
+		return
+	}
+
+	pullRequests, pg, err := h.pullRequestService.GetPullRequests(r.Context(), ownerName, repositoryName, filter, page, pageSize)
+	if err != nil {
+		logr.Error("error in getting stored pull requests", zap.Error(err))
+		code, res := h.response(http.StatusBadRequest, ResponseFormat{
+			Status:  false,
+			Message: messages.SomethingWentWrong,
+		})
+		utils.SendResponse(w, code, res)
+		return
+	}
+
+	code, res := h.response(http.StatusOK, ResponseFormat{
+		Status:  true,
+		Message: "Pull requests retrieved successfully",
+		Data:    pagination.PagedResponse{Pagination: pg, Data: pullRequests},
+	})
+	utils.SendResponse(w, code, res)
+}
+
+// GetRepositoryPullRequestStats returns pull request counts and time-to-merge statistics, using
+// the same filters as the pull request listing.
+func (h Handler) GetRepositoryPullRequestStats(w http.ResponseWriter, r *http.Request) {
+	logr := h.logger.With(zap.String("method", "GetRepositoryPullRequestStats"))
+
+	repositoryName := mux.Vars(r)["repository_name"]
+	ownerName := r.URL.Query().Get("owner_name")
+	if ownerName == "" {
+		ownerName = repositoryName
+	}
+
+	filter, err := parsePullRequestFilter(r)
+	if err != nil {
+		code, res := h.response(http.StatusBadRequest, ResponseFormat{
+			Status:  false,
+			Message: err.Error(),
+		})
+		utils.SendResponse(w, code, res)
+		return
+	}
+
+	stats, err := h.pullRequestService.GetPullRequestStats(r.Context(), ownerName, repositoryName, filter)
+	if err != nil {
+		logr.Error("error in getting pull request stats", zap.Error(err))
+		code, res := h.response(http.StatusBadRequest, ResponseFormat{
+			Status:  false,
+			Message: messages.SomethingWentWrong,
+		})
+		utils.SendResponse(w, code, res)
+		return
+	}
+
+	code, res := h.response(http.StatusOK, ResponseFormat{
+		Status:  true,
+		Message: "Pull request stats retrieved successfully",
+		Data:    stats,
+	})
+	utils.SendResponse(w, code, res)
+}
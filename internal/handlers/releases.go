@@ -0,0 +1,73 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/babyfaceeasy/lema/internal/messages"
+	"github.com/babyfaceeasy/lema/internal/utils"
+	"github.com/babyfaceeasy/lema/pkg/pagination"
+	"github.com/gorilla/mux"
+	"go.uber.org/zap"
+)
+
+func (h Handler) GetRepositoryReleases(w http.ResponseWriter, r *http.Request) {
+	logr := h.logger.With(zap.String("method", "GetRepositoryReleases"))
+
+	repositoryName := mux.Vars(r)["repository_name"]
+	ownerName := r.URL.Query().Get("owner_name")
+	if ownerName == "" {
+		ownerName = repositoryName
+	}
+
+	page, pageSize, _ := pagination.ParsePaginationParams(r.URL.Query())
+	includePrereleases, _ := strconv.ParseBool(r.URL.Query().Get("include_prereleases"))
+
+	releases, pg, err := h.releaseService.GetReleases(r.Context(), ownerName, repositoryName, includePrereleases, page, pageSize)
+	if err != nil {
+		logr.Error("error in getting stored releases", zap.Error(err))
+		code, res := h.response(http.StatusBadRequest, ResponseFormat{
+			Status:  false,
+			Message: messages.SomethingWentWrong,
+		})
+		utils.SendResponse(w, code, res)
+		return
+	}
+
+	code, res := h.response(http.StatusOK, ResponseFormat{
+		Status:  true,
+		Message: "Releases retrieved successfully",
+		Data:    pagination.PagedResponse{Pagination: pg, Data: releases},
+	})
+	utils.SendResponse(w, code, res)
+}
+
+func (h Handler) GetRepositoryReleaseCadence(w http.ResponseWriter, r *http.Request) {
+	logr := h.logger.With(zap.String("method", "GetRepositoryReleaseCadence"))
+
+	repositoryName := mux.Vars(r)["repository_name"]
+	ownerName := r.URL.Query().Get("owner_name")
+	if ownerName == "" {
+		ownerName = repositoryName
+	}
+
+	includePrereleases, _ := strconv.ParseBool(r.URL.Query().Get("include_prereleases"))
+
+	cadence, err := h.releaseService.GetReleaseCadence(r.Context(), ownerName, repositoryName, includePrereleases)
+	if err != nil {
+		logr.Error("error in getting release cadence", zap.Error(err))
+		code, res := h.response(http.StatusBadRequest, ResponseFormat{
+			Status:  false,
+			Message: messages.SomethingWentWrong,
+		})
+		utils.SendResponse(w, code, res)
+		return
+	}
+
+	code, res := h.response(http.StatusOK, ResponseFormat{
+		Status:  true,
+		Message: "Release cadence retrieved successfully",
+		Data:    cadence,
+	})
+	utils.SendResponse(w, code, res)
+}
@@ -8,6 +8,7 @@ import (
 	"strings"
 	"time"
 
+	"github.com/babyfaceeasy/lema/internal/domain"
 	"github.com/babyfaceeasy/lema/internal/messages"
 	"github.com/babyfaceeasy/lema/internal/tasks"
 	"github.com/babyfaceeasy/lema/internal/utils"
@@ -40,8 +41,18 @@ func (h Handler) GetRepositoryCommits(w http.ResponseWriter, r *http.Request) {
 		pageSize = 10
 	}
 
+	filter, err := parseCommitFilter(r)
+	if err != nil {
+		code, res := h.response(http.StatusBadRequest, ResponseFormat{
+			Status:  false,
+			Message: err.Error(),
+		})
+		utils.SendResponse(w, code, res)
+		return
+	}
+
 	// Retrieve and return the stored commits.
-	storedCommits, pg, err := h.commitService.GetCommitsByRepositoryName(r.Context(), ownerName, repositoryName, page, pageSize)
+	storedCommits, pg, err := h.commitService.GetCommitsByRepositoryName(r.Context(), ownerName, repositoryName, filter, page, pageSize)
 	if err != nil {
 		logr.Error("error in getting stored commits", zap.Error(err))
 		code, res := h.response(http.StatusBadRequest, ResponseFormat{
@@ -60,6 +71,86 @@ func (h Handler) GetRepositoryCommits(w http.ResponseWriter, r *http.Request) {
 	utils.SendResponse(w, code, res)
 }
 
+// parseBotsFilter reads the exclude_bots and only_bots query parameters, returning domain.BotsExclude,
+// domain.BotsOnly or an empty filter keeping both bots and people.
+func parseBotsFilter(r *http.Request) (string, error) {
+	query := r.URL.Query()
+	excludeBots, _ := strconv.ParseBool(query.Get("exclude_bots"))
+	onlyBots, _ := strconv.ParseBool(query.Get("only_bots"))
+
+	switch {
+	case excludeBots && onlyBots:
+		return "", fmt.Errorf("Invalid filter, 'exclude_bots' and 'only_bots' cannot be combined")
+	case excludeBots:
+		return domain.BotsExclude, nil
+	case onlyBots:
+		return domain.BotsOnly, nil
+	}
+	return "", nil
+}
+
+// parseCommitFilter reads the date_field, since, until, branch, trailer, exclude_bots and only_bots
+// query parameters of a commit listing.
+func parseCommitFilter(r *http.Request) (domain.CommitFilter, error) {
+	query := r.URL.Query()
+	filter := domain.CommitFilter{DateField: domain.CommitDateAuthor, Branch: query.Get("branch")}
+
+	switch dateField := strings.ToLower(query.Get("date_field")); dateField {
+	case "", domain.CommitDateAuthor:
+	case domain.CommitDateCommitter:
+		filter.DateField = domain.CommitDateCommitter
+	default:
+		return filter, fmt.Errorf("Invalid 'date_field', must be one of %s or %s", domain.CommitDateAuthor, domain.CommitDateCommitter)
+	}
+
+	for name, dst := range map[string]**time.Time{"since": &filter.Since, "until": &filter.Until} {
+		value := query.Get(name)
+		if value == "" {
+			continue
+		}
+		t, err := time.Parse(time.RFC3339, value)
+		if err != nil {
+			return filter, fmt.Errorf("Invalid '%s' date format, must be RFC3339", name)
+		}
+		*dst = &t
+	}
+
+	if trailer := query.Get("trailer"); trailer != "" {
+		key, value, _ := strings.Cut(trailer, ":")
+		filter.TrailerKey, filter.TrailerValue = strings.TrimSpace(key), strings.TrimSpace(value)
+		if filter.TrailerKey == "" {
+			return filter, fmt.Errorf("Invalid 'trailer', must be Key or Key:Value")
+		}
+	}
+
+	bots, err := parseBotsFilter(r)
+	if err != nil {
+		return filter, err
+	}
+	filter.Bots = bots
+
+	return filter, nil
+}
+
+// withHost scopes r to host when it is set. It writes a bad request response and returns false
+// when no provider is configured for host.
+func (h Handler) withHost(w http.ResponseWriter, r *http.Request, host string) (*http.Request, bool) {
+	if host == "" {
+		return r, true
+	}
+
+	host = domain.NormalizeHost(host)
+	if _, ok := h.config.HostProvider(host); !ok {
+		code, res := h.response(http.StatusBadRequest, ResponseFormat{
+			Status:  false,
+			Message: fmt.Sprintf("Unknown host %s", host),
+		})
+		utils.SendResponse(w, code, res)
+		return r, false
+	}
+	return r.WithContext(domain.WithHost(r.Context(), host)), true
+}
+
 func (h Handler) GetRepository(w http.ResponseWriter, r *http.Request) {
 	logr := h.logger.With(zap.String("method", "GetRepository"))
 
@@ -123,6 +214,26 @@ func (h Handler) MonitorRepository(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// a host in the body takes precedence over the host query parameter, and a provider given
+	// without any host stands for its public instance.
+	host := req.Host
+	if host == "" && req.Provider != "" && r.URL.Query().Get("host") == "" {
+		host = domain.PublicHost(req.Provider)
+	}
+	r, ok := h.withHost(w, r, host)
+	if !ok || h.importedRepositoryRequested(w, r) {
+		return
+	}
+
+	if provider, _ := h.config.HostProvider(domain.HostFromContext(r.Context())); req.Provider != "" && req.Provider != provider {
+		code, res := h.response(http.StatusBadRequest, ResponseFormat{
+			Status:  false,
+			Message: fmt.Sprintf("Host %s is served by %s, not %s", domain.HostFromContext(r.Context()), provider, req.Provider),
+		})
+		utils.SendResponse(w, code, res)
+		return
+	}
+
 	// check the startTime
 	if req.StartTimeStr != "" {
 		req.StartTime, err = time.Parse(time.RFC3339, req.StartTimeStr)
@@ -183,7 +294,19 @@ func (h Handler) MonitorRepository(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	if err := tasks.CallLoadCommitsTask(repoDetails.OwnerName, repoDetails.Name); err != nil {
+	if len(req.Branches) > 0 {
+		if err := h.repositoryService.AddRepositoryBranches(r.Context(), repoDetails.OwnerName, repoDetails.Name, req.Branches); err != nil {
+			logr.Error("error in saving repository branches:", zap.Error(err))
+			code, res := h.response(http.StatusInternalServerError, ResponseFormat{
+				Status:  false,
+				Message: messages.SomethingWentWrong,
+			})
+			utils.SendResponse(w, code, res)
+			return
+		}
+	}
+
+	if err := tasks.CallLoadCommitsTask(repoDetails.Host, repoDetails.OwnerName, repoDetails.Name); err != nil {
 		logr.Error("error in creating task to load commits:", zap.Error(err))
 		code, res := h.response(http.StatusInternalServerError, ResponseFormat{
 			Status:  false,
@@ -228,6 +351,12 @@ func (h Handler) ResetCollection(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// a host in the body takes precedence over the host query parameter
+	r, ok := h.withHost(w, r, req.Host)
+	if !ok || h.importedRepositoryRequested(w, r) {
+		return
+	}
+
 	// check the startTime
 	if req.StartTimeStr != "" {
 		req.StartTime, err = time.Parse(time.RFC3339, req.StartTimeStr)
@@ -271,7 +400,7 @@ func (h Handler) ResetCollection(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	if err := tasks.CallResetCommitsTask(repoDetails.OwnerName, repoDetails.Name); err != nil {
+	if err := tasks.CallResetCommitsTask(repoDetails.Host, repoDetails.OwnerName, repoDetails.Name); err != nil {
 		logr.Error("error in initiating the background task for reset commits", zap.Error(err))
 		code, res := h.response(http.StatusInternalServerError, ResponseFormat{
 			Status:  false,
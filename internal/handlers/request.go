@@ -3,24 +3,35 @@ package handlers
 import (
 	"time"
 
+	"github.com/babyfaceeasy/lema/internal/domain"
 	validation "github.com/go-ozzo/ozzo-validation/v4"
+	"github.com/go-ozzo/ozzo-validation/v4/is"
 )
 
 type monitorRepositoryRequest struct {
+	// Host defaults to the host query parameter, itself defaulting to github.com.
+	Host string `json:"host"`
+	// Provider is the provider serving Host, standing for its public instance when no host is given.
+	Provider       string    `json:"provider"`
 	RepositoryName string    `json:"repo_name"`
 	OwnerName      string    `json:"owner_name"`
 	StartTimeStr   string    `json:"start_time"`
 	StartTime      time.Time `json:"-"`
+	// Branches are monitored besides the default branch.
+	Branches []string `json:"branches"`
 }
 
 func (r monitorRepositoryRequest) Validate() error {
 	return validation.ValidateStruct(&r,
+		validation.Field(&r.Provider, validation.In(domain.ProviderGithub, domain.ProviderGitlab, domain.ProviderBitbucket, domain.ProviderGitea, domain.ProviderGerrit, domain.ProviderLocal)),
 		validation.Field(&r.RepositoryName, validation.Required),
 		validation.Field(&r.OwnerName, validation.Required),
+		validation.Field(&r.Branches, validation.Each(validation.Required, validation.Length(1, 255))),
 	)
 }
 
 type resetCollectionRequest struct {
+	Host           string    `json:"host"`
 	RepositoryName string    `json:"repo_name"`
 	OwnerName      string    `json:"owner_name"`
 	StartTimeStr   string    `json:"start_time"`
@@ -33,3 +44,28 @@ func (r resetCollectionRequest) Validate() error {
 		validation.Field(&r.OwnerName, validation.Required),
 	)
 }
+
+type addBranchesRequest struct {
+	OwnerName string   `json:"owner_name"`
+	Branches  []string `json:"branches"`
+}
+
+func (r addBranchesRequest) Validate() error {
+	return validation.ValidateStruct(&r,
+		validation.Field(&r.OwnerName, validation.Required),
+		validation.Field(&r.Branches, validation.Required, validation.Each(validation.Required, validation.Length(1, 255))),
+	)
+}
+
+type mergeAuthorsRequest struct {
+	// SourceID is the author resolved to the canonical author of TargetID, along with its aliases.
+	SourceID string `json:"source_id"`
+	TargetID string `json:"target_id"`
+}
+
+func (r mergeAuthorsRequest) Validate() error {
+	return validation.ValidateStruct(&r,
+		validation.Field(&r.SourceID, validation.Required, is.UUID),
+		validation.Field(&r.TargetID, validation.Required, is.UUID),
+	)
+}
@@ -0,0 +1,92 @@
+package handlers
+
+import (
+	"errors"
+	"io"
+	"net/http"
+
+	"github.com/babyfaceeasy/lema/internal/domain"
+	"github.com/babyfaceeasy/lema/internal/integrations/githubapi"
+	"github.com/babyfaceeasy/lema/internal/messages"
+	"github.com/babyfaceeasy/lema/internal/utils"
+	"go.uber.org/zap"
+)
+
+// maxWebhookSize bounds the size of a webhook payload, GitHub capping them at 25MB.
+const maxWebhookSize = 25 << 20
+
+// GithubWebhook receives the webhooks of GitHub, signed with the configured secret. Webhooks of a
+// GitHub Enterprise Server carry its host in the host query parameter of their URL.
+func (h Handler) GithubWebhook(w http.ResponseWriter, r *http.Request) {
+	logr := h.logger.With(zap.String("method", "GithubWebhook"))
+
+	secret := h.config.GetGithubWebhookSecret()
+	if secret == "" {
+		code, res := h.response(http.StatusServiceUnavailable, ResponseFormat{
+			Status:  false,
+			Message: "GitHub webhooks are not configured",
+		})
+		utils.SendResponse(w, code, res)
+		return
+	}
+
+	payload, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxWebhookSize))
+	if err != nil {
+		code, res := h.response(http.StatusBadRequest, ResponseFormat{
+			Status:  false,
+			Message: messages.InvalidRequest,
+			Error:   []string{err.Error()},
+		})
+		utils.SendResponse(w, code, res)
+		return
+	}
+
+	if err := githubapi.VerifyWebhookSignature(secret, payload, r.Header.Get("X-Hub-Signature-256")); err != nil {
+		logr.Warn("rejected webhook", zap.Error(err))
+		code, res := h.response(http.StatusUnauthorized, ResponseFormat{
+			Status:  false,
+			Message: err.Error(),
+		})
+		utils.SendResponse(w, code, res)
+		return
+	}
+
+	event := r.Header.Get("X-GitHub-Event")
+	deliveryID := r.Header.Get("X-GitHub-Delivery")
+	if event == "" || deliveryID == "" {
+		code, res := h.response(http.StatusBadRequest, ResponseFormat{
+			Status:  false,
+			Message: messages.InvalidRequest,
+			Error:   []string{"Missing X-GitHub-Event or X-GitHub-Delivery header"},
+		})
+		utils.SendResponse(w, code, res)
+		return
+	}
+
+	result, err := h.webhookService.HandleGithubWebhook(r.Context(), event, deliveryID, payload)
+	if err != nil {
+		if errors.Is(err, domain.ErrInvalidWebhookPayload) {
+			code, res := h.response(http.StatusBadRequest, ResponseFormat{
+				Status:  false,
+				Message: messages.InvalidRequest,
+				Error:   []string{err.Error()},
+			})
+			utils.SendResponse(w, code, res)
+			return
+		}
+
+		logr.Error("error in handling webhook", zap.String("event", event), zap.String("delivery_id", deliveryID), zap.Error(err))
+		code, res := h.response(http.StatusInternalServerError, ResponseFormat{
+			Status:  false,
+			Message: messages.SomethingWentWrong,
+		})
+		utils.SendResponse(w, code, res)
+		return
+	}
+
+	code, res := h.response(http.StatusOK, ResponseFormat{
+		Status: true,
+		Data:   result,
+	})
+	utils.SendResponse(w, code, res)
+}
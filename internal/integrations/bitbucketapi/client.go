@@ -0,0 +1,114 @@
+package bitbucketapi
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/babyfaceeasy/lema/internal/integrations/httpretry"
+	"github.com/bytedance/sonic"
+	"go.uber.org/zap"
+)
+
+type HttpClient interface {
+	Do(*http.Request) (*http.Response, error)
+}
+
+// Client talks to the Bitbucket Cloud REST 2.0 API.
+type Client struct {
+	baseURL     string
+	username    string
+	appPassword string
+	http        *httpretry.Client
+}
+
+type BitbucketError struct {
+	Error struct {
+		Message string `json:"message"`
+	} `json:"error"`
+}
+
+// page is the envelope of Bitbucket's paginated responses. Next is the absolute URL of the
+// following page and is empty on the last one.
+type page struct {
+	Size int    `json:"size"`
+	Next string `json:"next"`
+}
+
+// NewClient creates a client for the API rooted at baseURL, e.g. https://api.bitbucket.org/2.0,
+// authenticating with the app password of username when both are set.
+func NewClient(baseURL, username, appPassword string, httpClient HttpClient, logger *zap.Logger) *Client {
+	c := &Client{
+		baseURL:     strings.TrimSuffix(baseURL, "/"),
+		username:    username,
+		appPassword: appPassword,
+	}
+	c.http = &httpretry.Client{
+		HttpClient: httpClient,
+		Logger:     logger.With(zap.String("package", "bitbucketapi")),
+		Before:     c.authorize,
+	}
+	return c
+}
+
+// authorize sets the app password on req. Bitbucket counts requests over a rolling hour and
+// rarely says how long a rate limited request should wait.
+func (c *Client) authorize(req *http.Request) error {
+	if c.username != "" && c.appPassword != "" {
+		req.SetBasicAuth(c.username, c.appPassword)
+	}
+	return nil
+}
+
+// get requests endpoint and returns the body of a 200 response.
+func (c *Client) get(ctx context.Context, endpoint string) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		var bbErr BitbucketError
+		_ = sonic.Unmarshal(body, &bbErr)
+		return nil, fmt.Errorf("Bitbucket API error %d: %s", resp.StatusCode, bbErr.Error.Message)
+	}
+	return body, nil
+}
+
+// eachPage requests endpoint and follows the next URLs of its responses, handing every page body
+// to visit until it returns false or no page is left.
+func (c *Client) eachPage(ctx context.Context, endpoint string, visit func(body []byte) (bool, error)) error {
+	for n := 1; endpoint != ""; n++ {
+		body, err := c.get(ctx, endpoint)
+		if err != nil {
+			return fmt.Errorf("failed to get page %d: %w", n, err)
+		}
+
+		more, err := visit(body)
+		if err != nil {
+			return fmt.Errorf("failed to process page %d: %w", n, err)
+		}
+		if !more {
+			return nil
+		}
+
+		var p page
+		if err := sonic.Unmarshal(body, &p); err != nil {
+			return fmt.Errorf("failed to decode page %d: %w", n, err)
+		}
+		endpoint = p.Next
+	}
+	return nil
+}
@@ -0,0 +1,106 @@
+package bitbucketapi_test
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/babyfaceeasy/lema/internal/domain"
+	"github.com/babyfaceeasy/lema/internal/integrations/bitbucketapi"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestGetRepository(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		username, password, ok := r.BasicAuth()
+		assert.True(t, ok)
+		assert.Equal(t, "alice", username)
+		assert.Equal(t, "app-password", password)
+
+		switch r.URL.Path {
+		case "/2.0/repositories/atlassian/python-bitbucket":
+			w.Write([]byte(`{"slug":"python-bitbucket","description":"Bitbucket client","language":"python",
+				"mainbranch":{"name":"master"},"workspace":{"slug":"atlassian"},
+				"links":{"html":{"href":"https://bitbucket.org/atlassian/python-bitbucket"}}}`))
+		case "/2.0/repositories/atlassian/python-bitbucket/forks":
+			assert.Equal(t, "1", r.URL.Query().Get("pagelen"))
+			w.Write([]byte(`{"size":7,"values":[{}]}`))
+		case "/2.0/repositories/atlassian/python-bitbucket/watchers":
+			w.Write([]byte(`{"size":12,"values":[{}]}`))
+		default:
+			t.Errorf("unexpected request to %s", r.URL.Path)
+		}
+	}))
+	defer server.Close()
+
+	client := bitbucketapi.NewClient(server.URL+"/2.0", "alice", "app-password", server.Client(), zap.NewNop())
+
+	repo, err := client.GetRepository(context.Background(), "python-bitbucket", "atlassian")
+	require.NoError(t, err)
+	require.Equal(t, "master", repo.Mainbranch.Name)
+	require.Equal(t, "https://bitbucket.org/atlassian/python-bitbucket", repo.Links.HTML.Href)
+	require.Equal(t, 7, repo.ForksCount)
+	require.Equal(t, 12, repo.WatchersCount)
+}
+
+func TestGetCommitsWalksPastOlderCommits(t *testing.T) {
+	var server *httptest.Server
+	requests := 0
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		assert.Equal(t, "/2.0/repositories/ws/repo/commits", r.URL.Path)
+
+		if r.URL.Query().Get("page") == "" {
+			assert.Equal(t, "stable", r.URL.Query().Get("include"))
+			fmt.Fprintf(w, `{"next":"%s/2.0/repositories/ws/repo/commits?page=2","values":[
+				{"hash":"ccc","message":"third","date":"2025-03-05T00:00:00+00:00","author":{"raw":"Alice <alice@example.com>"},"parents":[{"hash":"bbb"}]},
+				{"hash":"bbb","message":"second","date":"2025-03-03T00:00:00+00:00","author":{"raw":"Bob <bob@example.com>"},"parents":[{"hash":"aaa"}]}]}`, server.URL)
+			return
+		}
+		w.Write([]byte(`{"values":[
+			{"hash":"aaa","message":"first","date":"2025-02-01T00:00:00+00:00","author":{"raw":"Bob <bob@example.com>"},"parents":[]},
+			{"hash":"abc","message":"merged","date":"2025-03-02T00:00:00+00:00","author":{"raw":"Bob <bob@example.com>"},"parents":[]}]}`))
+	}))
+	defer server.Close()
+
+	client := bitbucketapi.NewClient(server.URL+"/2.0", "", "", server.Client(), zap.NewNop())
+
+	ctx := domain.WithBranch(context.Background(), "refs/heads/stable")
+	since := time.Date(2025, time.March, 1, 0, 0, 0, 0, time.UTC)
+	until := time.Date(2025, time.March, 4, 0, 0, 0, 0, time.UTC)
+	commitCh := make(chan bitbucketapi.CommitResponse, 10)
+	require.NoError(t, client.GetCommits(ctx, "repo", "ws", &since, &until, 100, commitCh))
+	close(commitCh)
+
+	var hashes []string
+	for commit := range commitCh {
+		hashes = append(hashes, commit.Hash)
+	}
+	require.Equal(t, []string{"bbb", "abc"}, hashes)
+	require.Equal(t, 2, requests)
+}
+
+func TestAuthorNameAndEmail(t *testing.T) {
+	tests := []struct {
+		raw   string
+		name  string
+		email string
+	}{
+		{raw: "Alice Smith <alice@example.com>", name: "Alice Smith", email: "alice@example.com"},
+		{raw: "J. R. Bob <bob@example.com>", name: "J. R. Bob", email: "bob@example.com"},
+		{raw: "build-bot", name: "build-bot", email: ""},
+	}
+
+	for _, tt := range tests {
+		var cr bitbucketapi.CommitResponse
+		cr.Author.Raw = tt.raw
+		name, email := cr.AuthorNameAndEmail()
+		assert.Equal(t, tt.name, name, tt.raw)
+		assert.Equal(t, tt.email, email, tt.raw)
+	}
+}
@@ -0,0 +1,148 @@
+package bitbucketapi
+
+import (
+	"context"
+	"fmt"
+	"net/mail"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/babyfaceeasy/lema/internal/domain"
+	"github.com/bytedance/sonic"
+)
+
+type link struct {
+	Href string `json:"href"`
+}
+
+type RepositoryResponse struct {
+	Slug        string `json:"slug"`
+	Description string `json:"description"`
+	Language    string `json:"language"`
+	Mainbranch  struct {
+		Name string `json:"name"`
+	} `json:"mainbranch"`
+	Workspace struct {
+		Slug string `json:"slug"`
+	} `json:"workspace"`
+	Links struct {
+		HTML link `json:"html"`
+	} `json:"links"`
+
+	// ForksCount and WatchersCount are not part of the repository resource and are counted
+	// separately.
+	ForksCount    int `json:"-"`
+	WatchersCount int `json:"-"`
+}
+
+type CommitResponse struct {
+	Hash    string    `json:"hash"`
+	Message string    `json:"message"`
+	Date    time.Time `json:"date"`
+	Author  struct {
+		// Raw is the author as recorded in the commit, "Name <email>".
+		Raw  string `json:"raw"`
+		User *struct {
+			DisplayName string `json:"display_name"`
+			Nickname    string `json:"nickname"`
+		} `json:"user"`
+	} `json:"author"`
+	Parents []struct {
+		Hash string `json:"hash"`
+	} `json:"parents"`
+	Links struct {
+		HTML link `json:"html"`
+	} `json:"links"`
+}
+
+// AuthorNameAndEmail splits the raw author of the commit into its name and email address.
+func (cr CommitResponse) AuthorNameAndEmail() (string, string) {
+	raw := strings.TrimSpace(cr.Author.Raw)
+	if addr, err := mail.ParseAddress(raw); err == nil {
+		return addr.Name, addr.Address
+	}
+	// git accepts identities net/mail does not, e.g. with unquoted dots in the name.
+	if i := strings.LastIndex(raw, "<"); i >= 0 && strings.HasSuffix(raw, ">") {
+		return strings.TrimSpace(raw[:i]), raw[i+1 : len(raw)-1]
+	}
+	return raw, ""
+}
+
+// repositoryPath returns the path of the repository with slug in workspace.
+func (c *Client) repositoryPath(slug, workspace string) string {
+	return fmt.Sprintf("%s/repositories/%s/%s", c.baseURL, url.PathEscape(workspace), url.PathEscape(slug))
+}
+
+// GetRepository returns the repository with slug in workspace, with its forks and watchers counted.
+func (c *Client) GetRepository(ctx context.Context, slug, workspace string) (*RepositoryResponse, error) {
+	body, err := c.get(ctx, c.repositoryPath(slug, workspace))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get repository %s/%s: %w", workspace, slug, err)
+	}
+
+	var repo RepositoryResponse
+	if err := sonic.Unmarshal(body, &repo); err != nil {
+		return nil, fmt.Errorf("failed to decode repository %s/%s: %w", workspace, slug, err)
+	}
+
+	if repo.ForksCount, err = c.count(ctx, c.repositoryPath(slug, workspace)+"/forks"); err != nil {
+		return nil, fmt.Errorf("failed to count forks of %s/%s: %w", workspace, slug, err)
+	}
+	if repo.WatchersCount, err = c.count(ctx, c.repositoryPath(slug, workspace)+"/watchers"); err != nil {
+		return nil, fmt.Errorf("failed to count watchers of %s/%s: %w", workspace, slug, err)
+	}
+	return &repo, nil
+}
+
+// count returns the size of the collection at endpoint, requesting a single element of it.
+func (c *Client) count(ctx context.Context, endpoint string) (int, error) {
+	body, err := c.get(ctx, endpoint+"?pagelen=1")
+	if err != nil {
+		return 0, err
+	}
+
+	var p page
+	if err := sonic.Unmarshal(body, &p); err != nil {
+		return 0, err
+	}
+	return p.Size, nil
+}
+
+// GetCommits sends the commits of workspace/slug dated between since and until, newest first,
+// through commitCh. The branch set with domain.WithBranch is walked instead of the main branch.
+// Bitbucket cannot filter commits by date, so commits outside since and until are skipped here.
+// History is listed in graph order, where a merged branch can bring older commits before newer
+// ones, so the walk goes through the whole history rather than stopping at the first commit
+// before since.
+func (c *Client) GetCommits(ctx context.Context, slug, workspace string, since, until *time.Time, pageSize int, commitCh chan<- CommitResponse) error {
+	q := url.Values{}
+	q.Set("pagelen", fmt.Sprintf("%d", pageSize))
+	if branch := domain.BranchFromContext(ctx); branch != "" {
+		q.Set("include", strings.TrimPrefix(branch, "refs/heads/"))
+	}
+	endpoint := fmt.Sprintf("%s/commits?%s", c.repositoryPath(slug, workspace), q.Encode())
+
+	return c.eachPage(ctx, endpoint, func(body []byte) (bool, error) {
+		var p struct {
+			Values []CommitResponse `json:"values"`
+		}
+		if err := sonic.Unmarshal(body, &p); err != nil {
+			return false, err
+		}
+		for _, commit := range p.Values {
+			if since != nil && !since.IsZero() && commit.Date.Before(*since) {
+				continue
+			}
+			if until != nil && !until.IsZero() && commit.Date.After(*until) {
+				continue
+			}
+			select {
+			case <-ctx.Done():
+				return false, ctx.Err()
+			case commitCh <- commit:
+			}
+		}
+		return len(p.Values) > 0, nil
+	})
+}
@@ -0,0 +1,182 @@
+package gerritapi
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/bytedance/sonic"
+	"go.uber.org/zap"
+)
+
+type ProjectResponse struct {
+	ID          string `json:"id"`
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	State       string `json:"state"`
+}
+
+type AccountInfo struct {
+	AccountID int    `json:"_account_id"`
+	Name      string `json:"name"`
+	Email     string `json:"email"`
+	Username  string `json:"username"`
+}
+
+type ApprovalInfo struct {
+	AccountInfo
+	Value int `json:"value"`
+}
+
+type LabelInfo struct {
+	All []ApprovalInfo `json:"all"`
+}
+
+type GitPersonInfo struct {
+	Name  string    `json:"name"`
+	Email string    `json:"email"`
+	Date  Timestamp `json:"date"`
+}
+
+type CommitInfo struct {
+	Parents []struct {
+		Commit string `json:"commit"`
+	} `json:"parents"`
+	Author    GitPersonInfo `json:"author"`
+	Committer GitPersonInfo `json:"committer"`
+	Subject   string        `json:"subject"`
+	Message   string        `json:"message"`
+}
+
+type RevisionInfo struct {
+	Number int                 `json:"_number"`
+	Commit CommitInfo          `json:"commit"`
+	Files  map[string]struct{} `json:"files"`
+}
+
+type ChangeResponse struct {
+	ID              string                   `json:"id"`
+	Project         string                   `json:"project"`
+	Branch          string                   `json:"branch"`
+	ChangeID        string                   `json:"change_id"`
+	Subject         string                   `json:"subject"`
+	Status          string                   `json:"status"`
+	Number          int                      `json:"_number"`
+	Created         Timestamp                `json:"created"`
+	Updated         Timestamp                `json:"updated"`
+	Submitted       *Timestamp               `json:"submitted"`
+	Insertions      int                      `json:"insertions"`
+	Deletions       int                      `json:"deletions"`
+	Owner           AccountInfo              `json:"owner"`
+	CurrentRevision string                   `json:"current_revision"`
+	Revisions       map[string]RevisionInfo  `json:"revisions"`
+	Labels          map[string]LabelInfo     `json:"labels"`
+	Reviewers       map[string][]AccountInfo `json:"reviewers"`
+	MoreChanges     bool                     `json:"_more_changes"`
+}
+
+// ChangeURL returns the URL of the change on the Gerrit web UI.
+func (c *Client) ChangeURL(change ChangeResponse) string {
+	return fmt.Sprintf("%s/c/%s/+/%d", c.baseURL, change.Project, change.Number)
+}
+
+// ProjectURL returns the URL of the project on the Gerrit web UI.
+func (c *Client) ProjectURL(project string) string {
+	return fmt.Sprintf("%s/q/project:%s", c.baseURL, project)
+}
+
+// GetProject returns the project with the given name, e.g. chromium/src.
+func (c *Client) GetProject(ctx context.Context, project string) (*ProjectResponse, error) {
+	body, err := c.get(ctx, c.endpoint("/projects/"+url.PathEscape(project)))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get project %s: %w", project, err)
+	}
+
+	var p ProjectResponse
+	if err := sonic.Unmarshal(body, &p); err != nil {
+		return nil, fmt.Errorf("failed to decode project %s: %w", project, err)
+	}
+	return &p, nil
+}
+
+// GetHead returns the branch HEAD of project points to, without its refs/heads/ prefix.
+func (c *Client) GetHead(ctx context.Context, project string) (string, error) {
+	body, err := c.get(ctx, c.endpoint("/projects/"+url.PathEscape(project)+"/HEAD"))
+	if err != nil {
+		return "", fmt.Errorf("failed to get HEAD of project %s: %w", project, err)
+	}
+
+	var head string
+	if err := sonic.Unmarshal(body, &head); err != nil {
+		return "", fmt.Errorf("failed to decode HEAD of project %s: %w", project, err)
+	}
+	return strings.TrimPrefix(head, "refs/heads/"), nil
+}
+
+// GetMergedChanges sends the changes of project merged into branch between since and until
+// through changeCh, with their current revision, files, labels and reviewers. Changes come most
+// recently updated first. Each page is bounded with before: by the update time of the last change
+// of the previous one, rather than requested by offset, so changes updated while the walk goes on
+// cannot shift unseen ones past it. The bound is inclusive and rounded up to the second Gerrit
+// accepts, so changes seen on the previous page are skipped.
+func (c *Client) GetMergedChanges(ctx context.Context, project, branch string, since, until *time.Time, pageSize int, changeCh chan<- ChangeResponse) error {
+	terms := []string{fmt.Sprintf("project:%q", project), "status:merged"}
+	if branch != "" {
+		terms = append(terms, fmt.Sprintf("branch:%q", branch))
+	}
+	if since != nil && !since.IsZero() {
+		terms = append(terms, fmt.Sprintf("mergedafter:%q", formatTimestamp(*since)))
+	}
+	if until != nil && !until.IsZero() {
+		terms = append(terms, fmt.Sprintf("mergedbefore:%q", formatTimestamp(*until)))
+	}
+
+	q := url.Values{}
+	q.Set("q", strings.Join(terms, " "))
+	q.Set("n", fmt.Sprintf("%d", pageSize))
+	for _, o := range []string{"CURRENT_REVISION", "CURRENT_COMMIT", "CURRENT_FILES", "DETAILED_ACCOUNTS", "DETAILED_LABELS"} {
+		q.Add("o", o)
+	}
+
+	seen := make(map[int]bool)
+	for page := 1; ; page++ {
+		body, err := c.get(ctx, c.endpoint("/changes/?"+q.Encode()))
+		if err != nil {
+			return fmt.Errorf("failed to get page %d of merged changes of %s: %w", page, project, err)
+		}
+
+		var changes []ChangeResponse
+		if err := sonic.Unmarshal(body, &changes); err != nil {
+			return fmt.Errorf("failed to decode page %d of merged changes of %s: %w", page, project, err)
+		}
+		sent := 0
+		for _, change := range changes {
+			if seen[change.Number] {
+				continue
+			}
+			seen[change.Number] = true
+			sent++
+
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case changeCh <- change:
+			}
+		}
+
+		// Gerrit flags the last change of a page when more are left.
+		if len(changes) == 0 || !changes[len(changes)-1].MoreChanges {
+			return nil
+		}
+		// A page made only of changes already seen means a whole page was updated within the
+		// same second, which a time bound cannot get past.
+		if sent == 0 {
+			c.http.Logger.Warn("merged changes share one update time, stopping", zap.String("project", project))
+			return nil
+		}
+		before := changes[len(changes)-1].Updated.Truncate(time.Second).Add(time.Second)
+		q.Set("q", strings.Join(append(terms, fmt.Sprintf("before:%q", formatTimestamp(before))), " "))
+	}
+}
@@ -0,0 +1,124 @@
+package gerritapi
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/babyfaceeasy/lema/internal/integrations/httpretry"
+	"go.uber.org/zap"
+)
+
+type HttpClient interface {
+	Do(*http.Request) (*http.Response, error)
+}
+
+// Client talks to the REST API of a Gerrit Code Review server.
+type Client struct {
+	baseURL  string
+	username string
+	password string
+	http     *httpretry.Client
+}
+
+// xssiPrefix precedes every JSON response of Gerrit to keep browsers from executing it.
+var xssiPrefix = []byte(")]}'")
+
+// NewClient creates a client for the Gerrit server at baseURL, e.g.
+// https://chromium-review.googlesource.com. Requests are anonymous unless username is set, in
+// which case they go through the authenticated /a/ endpoints with its HTTP password.
+func NewClient(baseURL, username, password string, httpClient HttpClient, logger *zap.Logger) *Client {
+	c := &Client{
+		baseURL:  strings.TrimSuffix(baseURL, "/"),
+		username: username,
+		password: password,
+	}
+	c.http = &httpretry.Client{
+		HttpClient: httpClient,
+		Logger:     logger.With(zap.String("package", "gerritapi")),
+		Before:     c.authorize,
+	}
+	return c
+}
+
+// endpoint returns the URL of path, which starts with a slash.
+func (c *Client) endpoint(path string) string {
+	if c.username != "" {
+		return c.baseURL + "/a" + path
+	}
+	return c.baseURL + path
+}
+
+// authorize sets the HTTP password on req.
+func (c *Client) authorize(req *http.Request) error {
+	if c.username != "" {
+		req.SetBasicAuth(c.username, c.password)
+	}
+	return nil
+}
+
+// get requests endpoint and returns the body of a 200 response without its XSSI prefix. Gerrit
+// reports errors as plain text.
+func (c *Client) get(ctx context.Context, endpoint string) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("Gerrit API error %d: %s", resp.StatusCode, strings.TrimSpace(string(body)))
+	}
+	return stripXSSIPrefix(body), nil
+}
+
+// stripXSSIPrefix removes the line Gerrit prepends to JSON responses, if present.
+func stripXSSIPrefix(body []byte) []byte {
+	if !bytes.HasPrefix(body, xssiPrefix) {
+		return body
+	}
+	body = body[len(xssiPrefix):]
+	if i := bytes.IndexByte(body, '\n'); i >= 0 {
+		return body[i+1:]
+	}
+	return nil
+}
+
+// Timestamp is a time as formatted by Gerrit, always in UTC.
+type Timestamp struct {
+	time.Time
+}
+
+const timestampLayout = "2006-01-02 15:04:05.000000000"
+
+func (t *Timestamp) UnmarshalJSON(data []byte) error {
+	s := strings.Trim(string(data), `"`)
+	if s == "" || s == "null" {
+		return nil
+	}
+	parsed, err := time.ParseInLocation(timestampLayout, s, time.UTC)
+	if err != nil {
+		return fmt.Errorf("invalid gerrit timestamp %q: %w", s, err)
+	}
+	t.Time = parsed
+	return nil
+}
+
+// formatTimestamp formats t as Gerrit expects in queries.
+func formatTimestamp(t time.Time) string {
+	return t.UTC().Format("2006-01-02 15:04:05")
+}
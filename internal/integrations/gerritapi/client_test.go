@@ -0,0 +1,95 @@
+package gerritapi_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/babyfaceeasy/lema/internal/integrations/gerritapi"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestGetProjectStripsXSSIPrefix(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/a/projects/chromium%2Fsrc", r.URL.EscapedPath())
+		username, password, ok := r.BasicAuth()
+		assert.True(t, ok)
+		assert.Equal(t, "alice", username)
+		assert.Equal(t, "http-password", password)
+		w.Write([]byte(")]}'\n" + `{"id":"chromium%2Fsrc","name":"chromium/src","description":"Chromium","state":"ACTIVE"}`))
+	}))
+	defer server.Close()
+
+	client := gerritapi.NewClient(server.URL, "alice", "http-password", server.Client(), zap.NewNop())
+
+	project, err := client.GetProject(context.Background(), "chromium/src")
+	require.NoError(t, err)
+	require.Equal(t, "chromium/src", project.Name)
+	require.Equal(t, "Chromium", project.Description)
+}
+
+func TestGetHead(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/projects/v8%2Fv8/HEAD", r.URL.EscapedPath())
+		w.Write([]byte(")]}'\n\"refs/heads/main\""))
+	}))
+	defer server.Close()
+
+	client := gerritapi.NewClient(server.URL, "", "", server.Client(), zap.NewNop())
+
+	head, err := client.GetHead(context.Background(), "v8/v8")
+	require.NoError(t, err)
+	require.Equal(t, "main", head)
+}
+
+func TestGetMergedChangesPagesByUpdateTime(t *testing.T) {
+	requests := 0
+	query := `project:"chromium/src" status:merged branch:"main" mergedafter:"2025-03-01 00:00:00"`
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		assert.Equal(t, "/changes/", r.URL.Path)
+		assert.Empty(t, r.URL.Query().Get("S"))
+		assert.Contains(t, r.URL.Query()["o"], "DETAILED_LABELS")
+
+		w.Write([]byte(")]}'\n"))
+		switch r.URL.Query().Get("q") {
+		case query:
+			w.Write([]byte(`[
+				{"project":"chromium/src","_number":102,"change_id":"I102","created":"2025-03-02 10:00:00.000000000",
+				 "updated":"2025-03-03 12:30:00.000000000","submitted":"2025-03-03 12:30:00.000000000",
+				 "owner":{"_account_id":1,"name":"Alice"},"current_revision":"bbb"},
+				{"project":"chromium/src","_number":101,"change_id":"I101","created":"2025-03-01 10:00:00.000000000",
+				 "updated":"2025-03-02 09:00:00.500000000","owner":{"_account_id":2,"name":"Bob"},"current_revision":"aaa",
+				 "_more_changes":true}]`))
+		case query + ` before:"2025-03-02 09:00:01"`:
+			// the bound is inclusive, so the last change of the previous page comes back
+			w.Write([]byte(`[
+				{"project":"chromium/src","_number":101,"change_id":"I101","created":"2025-03-01 10:00:00.000000000",
+				 "updated":"2025-03-02 09:00:00.500000000","owner":{"_account_id":2,"name":"Bob"},"current_revision":"aaa"}]`))
+		default:
+			t.Errorf("unexpected query %s", r.URL.Query().Get("q"))
+		}
+	}))
+	defer server.Close()
+
+	client := gerritapi.NewClient(server.URL, "", "", server.Client(), zap.NewNop())
+
+	since := time.Date(2025, time.March, 1, 0, 0, 0, 0, time.UTC)
+	changeCh := make(chan gerritapi.ChangeResponse, 10)
+	require.NoError(t, client.GetMergedChanges(context.Background(), "chromium/src", "main", &since, nil, 2, changeCh))
+	close(changeCh)
+
+	var changes []gerritapi.ChangeResponse
+	for change := range changeCh {
+		changes = append(changes, change)
+	}
+	require.Len(t, changes, 2)
+	require.Equal(t, 2, requests)
+	require.Equal(t, time.Date(2025, time.March, 3, 12, 30, 0, 0, time.UTC), changes[0].Submitted.Time)
+	require.Nil(t, changes[1].Submitted)
+	require.Equal(t, server.URL+"/c/chromium/src/+/102", client.ChangeURL(changes[0]))
+}
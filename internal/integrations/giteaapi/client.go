@@ -0,0 +1,109 @@
+package giteaapi
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/babyfaceeasy/lema/internal/integrations/httpretry"
+	"github.com/bytedance/sonic"
+	"go.uber.org/zap"
+)
+
+type HttpClient interface {
+	Do(*http.Request) (*http.Response, error)
+}
+
+// Client talks to the REST API of a Gitea or Forgejo instance, which share the same v1 API.
+type Client struct {
+	baseURL string
+	token   string
+	http    *httpretry.Client
+}
+
+type GiteaError struct {
+	Message string `json:"message"`
+}
+
+// NewClient creates a client for the API rooted at baseURL, e.g. https://gitea.corp/api/v1,
+// authenticating with an access token when token is not empty.
+func NewClient(baseURL, token string, httpClient HttpClient, logger *zap.Logger) *Client {
+	c := &Client{
+		baseURL: strings.TrimSuffix(baseURL, "/"),
+		token:   token,
+	}
+	c.http = &httpretry.Client{
+		HttpClient: httpClient,
+		Logger:     logger.With(zap.String("package", "giteaapi")),
+		Before:     c.authorize,
+	}
+	return c
+}
+
+// authorize sets the access token on req. Gitea only rate limits behind a reverse proxy, which
+// sends no budget headers.
+func (c *Client) authorize(req *http.Request) error {
+	if c.token != "" {
+		req.Header.Set("Authorization", "token "+c.token)
+	}
+	return nil
+}
+
+// get requests endpoint and returns the body of a 200 response.
+func (c *Client) get(ctx context.Context, endpoint string) ([]byte, http.Header, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to read response: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		var gtErr GiteaError
+		_ = sonic.Unmarshal(body, &gtErr)
+		return nil, nil, fmt.Errorf("Gitea API error %d: %s", resp.StatusCode, gtErr.Message)
+	}
+	return body, resp.Header, nil
+}
+
+// eachPage requests endpoint page by page, handing every page body and the number of items on it
+// to visit until it returns false. Gitea reports the size of the whole collection in
+// X-Total-Count rather than linking to the next page, and caps the page size to its own maximum,
+// so the walk ends once as many items as announced were seen or a page comes back empty.
+func (c *Client) eachPage(ctx context.Context, endpoint string, visit func(body []byte) (int, bool, error)) error {
+	sep := "?"
+	if strings.Contains(endpoint, "?") {
+		sep = "&"
+	}
+
+	seen := 0
+	for page := 1; ; page++ {
+		body, header, err := c.get(ctx, fmt.Sprintf("%s%spage=%d", endpoint, sep, page))
+		if err != nil {
+			return fmt.Errorf("failed to get page %d: %w", page, err)
+		}
+
+		n, more, err := visit(body)
+		if err != nil {
+			return fmt.Errorf("failed to process page %d: %w", page, err)
+		}
+		seen += n
+		if !more || n == 0 {
+			return nil
+		}
+		if total, err := strconv.Atoi(header.Get("X-Total-Count")); err == nil && seen >= total {
+			return nil
+		}
+	}
+}
@@ -0,0 +1,101 @@
+package giteaapi_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/babyfaceeasy/lema/internal/domain"
+	"github.com/babyfaceeasy/lema/internal/integrations/giteaapi"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestGetRepository(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/api/v1/repos/forgejo/forgejo", r.URL.Path)
+		assert.Equal(t, "token secret", r.Header.Get("Authorization"))
+		w.Write([]byte(`{"name":"forgejo","description":"Beyond coding","html_url":"https://codeberg.org/forgejo/forgejo",
+			"language":"Go","default_branch":"forgejo","stars_count":3000,"forks_count":500,"watchers_count":80,
+			"open_issues_count":1200,"owner":{"login":"forgejo"}}`))
+	}))
+	defer server.Close()
+
+	client := giteaapi.NewClient(server.URL+"/api/v1", "secret", server.Client(), zap.NewNop())
+
+	repo, err := client.GetRepository(context.Background(), "forgejo", "forgejo")
+	require.NoError(t, err)
+	require.Equal(t, "forgejo", repo.Owner.Login)
+	require.Equal(t, "forgejo", repo.DefaultBranch)
+	require.Equal(t, 3000, repo.StarsCount)
+}
+
+func TestGetCommitsStopsAtTotalCount(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		assert.Equal(t, "/api/v1/repos/org/repo/commits", r.URL.Path)
+		assert.Equal(t, "stable", r.URL.Query().Get("sha"))
+		assert.Equal(t, "true", r.URL.Query().Get("stat"))
+		w.Header().Set("X-Total-Count", "3")
+
+		switch r.URL.Query().Get("page") {
+		case "1":
+			w.Write([]byte(`[
+				{"sha":"ccc","commit":{"message":"third","author":{"name":"alice","date":"2025-03-03T00:00:00Z"},"committer":{"name":"alice","date":"2025-03-03T00:00:00Z"}},
+				 "parents":[{"sha":"bbb"}],"stats":{"additions":3,"deletions":1,"total":4},"files":[{"filename":"a.go"},{"filename":"b.go"}]},
+				{"sha":"bbb","commit":{"message":"second","author":{"name":"bob","date":"2025-03-02T00:00:00Z"},"committer":{"name":"bob","date":"2025-03-02T00:00:00Z"}},
+				 "parents":[{"sha":"aaa"}]}]`))
+		case "2":
+			w.Write([]byte(`[
+				{"sha":"aaa","commit":{"message":"first","author":{"name":"bob","date":"2025-03-01T00:00:00Z"},"committer":{"name":"bob","date":"2025-03-01T00:00:00Z"}},
+				 "parents":[]}]`))
+		default:
+			t.Errorf("unexpected page %s", r.URL.Query().Get("page"))
+		}
+	}))
+	defer server.Close()
+
+	client := giteaapi.NewClient(server.URL+"/api/v1", "", server.Client(), zap.NewNop())
+
+	ctx := domain.WithBranch(context.Background(), "refs/heads/stable")
+	commitCh := make(chan giteaapi.CommitResponse, 10)
+	require.NoError(t, client.GetCommits(ctx, "repo", "org", nil, nil, 2, commitCh))
+	close(commitCh)
+
+	var shas []string
+	for commit := range commitCh {
+		shas = append(shas, commit.SHA)
+	}
+	require.Equal(t, []string{"ccc", "bbb", "aaa"}, shas)
+	require.Equal(t, 2, requests)
+}
+
+func TestGetCommitsFiltersDatesIgnoredByOlderInstances(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "2025-03-02T00:00:00Z", r.URL.Query().Get("since"))
+		w.Header().Set("X-Total-Count", "3")
+		w.Write([]byte(`[
+			{"sha":"ccc","commit":{"committer":{"date":"2025-03-05T00:00:00Z"}}},
+			{"sha":"bbb","commit":{"committer":{"date":"2025-03-03T00:00:00Z"}}},
+			{"sha":"aaa","commit":{"committer":{"date":"2025-03-01T00:00:00Z"}}}]`))
+	}))
+	defer server.Close()
+
+	client := giteaapi.NewClient(server.URL, "", server.Client(), zap.NewNop())
+
+	since := time.Date(2025, time.March, 2, 0, 0, 0, 0, time.UTC)
+	until := time.Date(2025, time.March, 4, 0, 0, 0, 0, time.UTC)
+	commitCh := make(chan giteaapi.CommitResponse, 10)
+	require.NoError(t, client.GetCommits(context.Background(), "repo", "org", &since, &until, 50, commitCh))
+	close(commitCh)
+
+	var shas []string
+	for commit := range commitCh {
+		shas = append(shas, commit.SHA)
+	}
+	require.Equal(t, []string{"bbb"}, shas)
+}
@@ -0,0 +1,118 @@
+package giteaapi
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/babyfaceeasy/lema/internal/domain"
+	"github.com/bytedance/sonic"
+)
+
+type RepositoryResponse struct {
+	Name            string `json:"name"`
+	Description     string `json:"description"`
+	HTMLURL         string `json:"html_url"`
+	Language        string `json:"language"`
+	DefaultBranch   string `json:"default_branch"`
+	StarsCount      int    `json:"stars_count"`
+	ForksCount      int    `json:"forks_count"`
+	WatchersCount   int    `json:"watchers_count"`
+	OpenIssuesCount int    `json:"open_issues_count"`
+	Owner           struct {
+		Login string `json:"login"`
+	} `json:"owner"`
+}
+
+type CommitUser struct {
+	Name  string    `json:"name"`
+	Email string    `json:"email"`
+	Date  time.Time `json:"date"`
+}
+
+type CommitResponse struct {
+	SHA     string `json:"sha"`
+	HTMLURL string `json:"html_url"`
+	Commit  struct {
+		Message   string     `json:"message"`
+		Author    CommitUser `json:"author"`
+		Committer CommitUser `json:"committer"`
+	} `json:"commit"`
+	Parents []struct {
+		SHA string `json:"sha"`
+	} `json:"parents"`
+	Stats *struct {
+		Additions int `json:"additions"`
+		Deletions int `json:"deletions"`
+	} `json:"stats"`
+	Files []struct {
+		Filename string `json:"filename"`
+	} `json:"files"`
+}
+
+// repoPath returns the path of the repository owner/name.
+func (c *Client) repoPath(name, owner string) string {
+	return fmt.Sprintf("%s/repos/%s/%s", c.baseURL, url.PathEscape(owner), url.PathEscape(name))
+}
+
+// GetRepository returns the repository owner/name.
+func (c *Client) GetRepository(ctx context.Context, repositoryName, owner string) (*RepositoryResponse, error) {
+	body, _, err := c.get(ctx, c.repoPath(repositoryName, owner))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get repository %s/%s: %w", owner, repositoryName, err)
+	}
+
+	var repo RepositoryResponse
+	if err := sonic.Unmarshal(body, &repo); err != nil {
+		return nil, fmt.Errorf("failed to decode repository %s/%s: %w", owner, repositoryName, err)
+	}
+	return &repo, nil
+}
+
+// GetCommits sends the commits of owner/name committed between since and until, newest first,
+// through commitCh with their line statistics and changed files. The branch set with
+// domain.WithBranch is walked instead of the default branch. Older instances ignore
+// the since and until parameters, so commits outside them are also skipped here. History is
+// listed in graph order, where a merged branch can bring older commits before newer ones, so the
+// walk does not stop at the first commit before since.
+func (c *Client) GetCommits(ctx context.Context, repositoryName, owner string, since, until *time.Time, pageSize int, commitCh chan<- CommitResponse) error {
+	q := url.Values{}
+	q.Set("limit", fmt.Sprintf("%d", pageSize))
+	q.Set("stat", "true")
+	q.Set("files", "true")
+	q.Set("verification", "false")
+	if since != nil && !since.IsZero() {
+		q.Set("since", since.UTC().Format(time.RFC3339))
+	}
+	if until != nil && !until.IsZero() {
+		q.Set("until", until.UTC().Format(time.RFC3339))
+	}
+	if branch := domain.BranchFromContext(ctx); branch != "" {
+		q.Set("sha", strings.TrimPrefix(branch, "refs/heads/"))
+	}
+	endpoint := fmt.Sprintf("%s/commits?%s", c.repoPath(repositoryName, owner), q.Encode())
+
+	return c.eachPage(ctx, endpoint, func(body []byte) (int, bool, error) {
+		var commits []CommitResponse
+		if err := sonic.Unmarshal(body, &commits); err != nil {
+			return 0, false, err
+		}
+		for _, commit := range commits {
+			date := commit.Commit.Committer.Date
+			if since != nil && !since.IsZero() && date.Before(*since) {
+				continue
+			}
+			if until != nil && !until.IsZero() && date.After(*until) {
+				continue
+			}
+			select {
+			case <-ctx.Done():
+				return 0, false, ctx.Err()
+			case commitCh <- commit:
+			}
+		}
+		return len(commits), true, nil
+	})
+}
@@ -0,0 +1,238 @@
+package githubapi
+
+import (
+	"context"
+	"crypto"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/bytedance/sonic"
+)
+
+const (
+	// appJWTLifetime is below the ten minutes GitHub accepts for an app JWT.
+	appJWTLifetime = 9 * time.Minute
+	// installationTokenRefreshMargin is how long before expiry an installation token is renewed.
+	installationTokenRefreshMargin = 5 * time.Minute
+)
+
+type installationToken struct {
+	Token     string    `json:"token"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+type installationResponse struct {
+	ID int64 `json:"id"`
+}
+
+// AppAuth authenticates requests as a GitHub App installation. It signs app JWTs with the
+// app's private key, exchanges them for installation access tokens and caches those until
+// shortly before they expire.
+type AppAuth struct {
+	appID      int64
+	privateKey *rsa.PrivateKey
+	apiURL     string
+	httpClient HttpClient
+
+	// defaultInstallation is used when the installation of a repository cannot be looked up.
+	defaultInstallation int64
+
+	mu            sync.Mutex
+	installations map[string]int64
+	tokens        map[int64]installationToken
+}
+
+// NewAppAuth creates an AppAuth from a PEM encoded private key. apiURL is the root of the
+// GitHub REST API, e.g. https://api.github.com.
+func NewAppAuth(appID int64, privateKeyPEM []byte, apiURL string, defaultInstallation int64, httpClient HttpClient) (*AppAuth, error) {
+	key, err := parsePrivateKey(privateKeyPEM)
+	if err != nil {
+		return nil, err
+	}
+
+	return &AppAuth{
+		appID:               appID,
+		privateKey:          key,
+		apiURL:              strings.TrimSuffix(apiURL, "/"),
+		httpClient:          httpClient,
+		defaultInstallation: defaultInstallation,
+		installations:       make(map[string]int64),
+		tokens:              make(map[int64]installationToken),
+	}, nil
+}
+
+// LoadAppAuth creates an AppAuth from a private key file.
+func LoadAppAuth(appID int64, privateKeyPath string, apiURL string, defaultInstallation int64, httpClient HttpClient) (*AppAuth, error) {
+	privateKeyPEM, err := os.ReadFile(privateKeyPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read github app private key: %w", err)
+	}
+	return NewAppAuth(appID, privateKeyPEM, apiURL, defaultInstallation, httpClient)
+}
+
+func parsePrivateKey(privateKeyPEM []byte) (*rsa.PrivateKey, error) {
+	block, _ := pem.Decode(privateKeyPEM)
+	if block == nil {
+		return nil, errors.New("github app private key is not PEM encoded")
+	}
+
+	if key, err := x509.ParsePKCS1PrivateKey(block.Bytes); err == nil {
+		return key, nil
+	}
+
+	parsed, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse github app private key: %w", err)
+	}
+	key, ok := parsed.(*rsa.PrivateKey)
+	if !ok {
+		return nil, errors.New("github app private key is not an RSA key")
+	}
+	return key, nil
+}
+
+// appJWT returns a JWT identifying the app, signed with RS256.
+func (a *AppAuth) appJWT() (string, error) {
+	now := time.Now()
+	header, err := sonic.Marshal(map[string]string{"alg": "RS256", "typ": "JWT"})
+	if err != nil {
+		return "", err
+	}
+	claims, err := sonic.Marshal(map[string]any{
+		// issued in the past to allow for clock drift.
+		"iat": now.Add(-time.Minute).Unix(),
+		"exp": now.Add(appJWTLifetime).Unix(),
+		"iss": fmt.Sprintf("%d", a.appID),
+	})
+	if err != nil {
+		return "", err
+	}
+
+	unsigned := base64.RawURLEncoding.EncodeToString(header) + "." + base64.RawURLEncoding.EncodeToString(claims)
+	digest := sha256.Sum256([]byte(unsigned))
+	signature, err := rsa.SignPKCS1v15(nil, a.privateKey, crypto.SHA256, digest[:])
+	if err != nil {
+		return "", fmt.Errorf("failed to sign github app jwt: %w", err)
+	}
+
+	return unsigned + "." + base64.RawURLEncoding.EncodeToString(signature), nil
+}
+
+// appRequest sends a request authenticated with the app JWT and decodes the response into v.
+func (a *AppAuth) appRequest(ctx context.Context, method, path string, expectedStatus int, v any) error {
+	jwt, err := a.appJWT()
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, a.apiURL+path, nil)
+	if err != nil {
+		return fmt.Errorf("failed to create github app request: %w", err)
+	}
+	req.Header.Set("Authorization", "Bearer "+jwt)
+	req.Header.Set("Accept", "application/vnd.github+json")
+
+	resp, err := a.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to submit github app request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("error reading response body: %w", err)
+	}
+
+	if resp.StatusCode != expectedStatus {
+		var ghErr GitHubError
+		_ = sonic.Unmarshal(body, &ghErr)
+		return fmt.Errorf("github app request %s %s failed with status %d: %s", method, path, resp.StatusCode, ghErr.Message)
+	}
+
+	if err := sonic.Unmarshal(body, v); err != nil {
+		return fmt.Errorf("failed to unmarshal github app response: %w", err)
+	}
+	return nil
+}
+
+// installationID returns the installation of the app that covers owner/repo.
+func (a *AppAuth) installationID(ctx context.Context, owner, repo string) (int64, error) {
+	if owner == "" {
+		if a.defaultInstallation == 0 {
+			return 0, errors.New("no github app installation configured for requests outside a repository")
+		}
+		return a.defaultInstallation, nil
+	}
+
+	// installations are per account, so every repository of an owner shares one.
+	cacheKey := strings.ToLower(owner)
+
+	a.mu.Lock()
+	id, ok := a.installations[cacheKey]
+	a.mu.Unlock()
+	if ok {
+		return id, nil
+	}
+
+	var installation installationResponse
+	err := a.appRequest(ctx, http.MethodGet, fmt.Sprintf("/repos/%s/%s/installation", owner, repo), http.StatusOK, &installation)
+	if err != nil {
+		if a.defaultInstallation != 0 {
+			return a.defaultInstallation, nil
+		}
+		return 0, err
+	}
+
+	a.mu.Lock()
+	a.installations[cacheKey] = installation.ID
+	a.mu.Unlock()
+	return installation.ID, nil
+}
+
+// authorization returns the Authorization header for a request on owner/repo together with
+// the rate limit key of the installation it belongs to.
+func (a *AppAuth) authorization(ctx context.Context, owner, repo string) (header string, key string, err error) {
+	id, err := a.installationID(ctx, owner, repo)
+	if err != nil {
+		return "", "", err
+	}
+
+	a.mu.Lock()
+	token, ok := a.tokens[id]
+	a.mu.Unlock()
+
+	if !ok || time.Until(token.ExpiresAt) < installationTokenRefreshMargin {
+		if err := a.appRequest(ctx, http.MethodPost, fmt.Sprintf("/app/installations/%d/access_tokens", id), http.StatusCreated, &token); err != nil {
+			return "", "", err
+		}
+
+		a.mu.Lock()
+		a.tokens[id] = token
+		a.mu.Unlock()
+	}
+
+	return "Bearer " + token.Token, fmt.Sprintf("installation-%d", id), nil
+}
+
+// invalidate drops the cached token of the installation identified by key.
+func (a *AppAuth) invalidate(key string) {
+	var id int64
+	if _, err := fmt.Sscanf(key, "installation-%d", &id); err != nil {
+		return
+	}
+
+	a.mu.Lock()
+	delete(a.tokens, id)
+	a.mu.Unlock()
+}
@@ -0,0 +1,93 @@
+package githubapi_test
+
+import (
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/babyfaceeasy/lema/config"
+	"github.com/babyfaceeasy/lema/internal/integrations/githubapi"
+	"github.com/bytedance/sonic"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestAppAuthInstallationToken(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	keyPEM := pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(key)})
+
+	// verifyJWT checks the app JWT was signed with the app's key.
+	verifyJWT := func(t *testing.T, header string) {
+		jwt := strings.TrimPrefix(header, "Bearer ")
+		parts := strings.Split(jwt, ".")
+		if !assert.Len(t, parts, 3) {
+			return
+		}
+
+		signature, err := base64.RawURLEncoding.DecodeString(parts[2])
+		assert.NoError(t, err)
+		digest := sha256.Sum256([]byte(parts[0] + "." + parts[1]))
+		assert.NoError(t, rsa.VerifyPKCS1v15(&key.PublicKey, crypto.SHA256, digest[:], signature))
+
+		claims, err := base64.RawURLEncoding.DecodeString(parts[1])
+		assert.NoError(t, err)
+		assert.Contains(t, string(claims), `"iss":"1234"`)
+	}
+
+	var tokensIssued atomic.Int32
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /repos/chromium/chromium/installation", func(w http.ResponseWriter, r *http.Request) {
+		verifyJWT(t, r.Header.Get("Authorization"))
+		w.Write([]byte(`{"id": 42}`))
+	})
+	mux.HandleFunc("POST /app/installations/42/access_tokens", func(w http.ResponseWriter, r *http.Request) {
+		verifyJWT(t, r.Header.Get("Authorization"))
+		tokensIssued.Add(1)
+		body, _ := sonic.Marshal(map[string]any{
+			"token":      "installation-token",
+			"expires_at": time.Now().Add(time.Hour).UTC().Format(time.RFC3339),
+		})
+		w.WriteHeader(http.StatusCreated)
+		w.Write(body)
+	})
+	mux.HandleFunc("GET /repos/chromium/chromium", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "Bearer installation-token", r.Header.Get("Authorization"))
+		w.Write([]byte(`{"name": "chromium", "owner": {"login": "chromium"}}`))
+	})
+
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	app, err := githubapi.NewAppAuth(1234, keyPEM, server.URL, 0, server.Client())
+	require.NoError(t, err)
+
+	client := githubapi.NewClient(
+		server.URL+"/repos",
+		server.Client(),
+		zap.NewNop(),
+		&config.Config{},
+		githubapi.WithRateLimiter(githubapi.NewMemoryRateLimiter()),
+		githubapi.WithAppAuth(app),
+	)
+
+	for i := 0; i < 2; i++ {
+		repo, err := client.GetRepositoryDetails("chromium", "chromium")
+		require.NoError(t, err)
+		require.Equal(t, "chromium", repo.Name)
+	}
+
+	// the installation token is cached between requests.
+	require.Equal(t, int32(1), tokensIssued.Load())
+}
@@ -6,11 +6,17 @@ import (
 	"fmt"
 	"io"
 	"net/http"
+	"net/url"
 	"strconv"
 	"strings"
+	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/babyfaceeasy/lema/config"
+	"github.com/babyfaceeasy/lema/internal/domain"
+	"github.com/babyfaceeasy/lema/internal/repositories"
+	"github.com/babyfaceeasy/lema/pkg/botdetect"
 	"github.com/bytedance/sonic"
 	"go.uber.org/zap"
 )
@@ -24,17 +30,468 @@ type Client struct {
 	httpClient HttpClient
 	logger     *zap.Logger
 	config     config.Config
+
+	// rate limiting
+	rateLimiter RateLimiter
+	tokens      *TokenPool
+	app         *AppAuth
+
+	// graphql
+	graphqlURL string
+
+	// conditional requests
+	etagStore   repositories.ETagRepository
+	cacheHits   atomic.Int64
+	cacheMisses atomic.Int64
+}
+
+// ClientOption configures optional behaviour of a Client.
+type ClientOption func(*Client)
+
+// WithETagStore makes the client persist ETag / Last-Modified validators per request URL
+// and send them back as If-None-Match / If-Modified-Since on conditional requests.
+func WithETagStore(store repositories.ETagRepository) ClientOption {
+	return func(c *Client) {
+		c.etagStore = store
+	}
+}
+
+// WithRateLimiter replaces the process-wide in-memory rate limiter, e.g. by one stored in Redis.
+func WithRateLimiter(limiter RateLimiter) ClientOption {
+	return func(c *Client) {
+		c.rateLimiter = limiter
+	}
+}
+
+// WithTokenPool replaces the pool built from the configured GitHub tokens.
+func WithTokenPool(pool *TokenPool) ClientOption {
+	return func(c *Client) {
+		c.tokens = pool
+	}
+}
+
+// WithAppAuth authenticates every request as the GitHub App installation covering its repository
+// instead of using personal access tokens.
+func WithAppAuth(app *AppAuth) ClientOption {
+	return func(c *Client) {
+		c.app = app
+	}
+}
+
+// WithGraphQLURL sets the GraphQL endpoint, by default derived from the base URL.
+func WithGraphQLURL(graphqlURL string) ClientOption {
+	return func(c *Client) {
+		c.graphqlURL = graphqlURL
+	}
+}
+
+// maxRedirects bounds how many redirects of a renamed or transferred repository are followed.
+const maxRedirects = 5
+
+func NewClient(baseURL string, httpClient HttpClient, logger *zap.Logger, cfg *config.Config, opts ...ClientOption) *Client {
+	if hc, ok := httpClient.(*http.Client); ok && hc.CheckRedirect == nil {
+		// Redirects are followed by do, so that they are authorized and rate limited like any request.
+		noRedirect := *hc
+		noRedirect.CheckRedirect = func(*http.Request, []*http.Request) error {
+			return http.ErrUseLastResponse
+		}
+		httpClient = &noRedirect
+	}
+
+	c := &Client{
+		baseURL:     baseURL,
+		httpClient:  httpClient,
+		logger:      logger,
+		config:      *cfg,
+		rateLimiter: defaultRateLimiter,
+		tokens:      NewTokenPool(cfg.GetGithubTokens()),
+		graphqlURL:  strings.TrimSuffix(strings.TrimSuffix(baseURL, "/"), "/repos") + "/graphql",
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	c.rateLimiter = scopeRateLimiter(c.rateLimiter, baseURL)
+	return c
+}
+
+type conditionalKey struct{}
+
+// conditionalRequests holds the validators received by the conditional requests of a context
+// until the caller has stored the data they describe.
+type conditionalRequests struct {
+	mu      sync.Mutex
+	store   repositories.ETagRepository
+	pending map[string]domain.ETag
+}
+
+// WithConditionalRequests marks ctx so that requests made with it are sent with the cached
+// validators of their URL. A 304 response is then reported as domain.ErrNotModified.
+// The validators of new responses are only cached by SaveValidators.
+func WithConditionalRequests(ctx context.Context) context.Context {
+	return context.WithValue(ctx, conditionalKey{}, &conditionalRequests{pending: make(map[string]domain.ETag)})
+}
+
+// withoutConditionalRequests makes requests made with ctx unconditional.
+func withoutConditionalRequests(ctx context.Context) context.Context {
+	return context.WithValue(ctx, conditionalKey{}, (*conditionalRequests)(nil))
+}
+
+func conditionalOf(ctx context.Context) *conditionalRequests {
+	v, _ := ctx.Value(conditionalKey{}).(*conditionalRequests)
+	return v
 }
 
-func NewClient(baseURL string, httpClient HttpClient, logger *zap.Logger, cfg *config.Config) *Client {
-	return &Client{
-		baseURL:    baseURL,
-		httpClient: httpClient,
-		logger:     logger,
-		config:     *cfg,
+// SaveValidators caches the validators of the responses received with ctx, a context returned by
+// WithConditionalRequests. It must only be called once their data has been stored, otherwise the
+// next request is answered with a 304 and the data is never fetched again.
+func SaveValidators(ctx context.Context) error {
+	cond := conditionalOf(ctx)
+	if cond == nil {
+		return nil
+	}
+
+	cond.mu.Lock()
+	defer cond.mu.Unlock()
+
+	for url, etag := range cond.pending {
+		if err := cond.store.Save(ctx, etag); err != nil {
+			return err
+		}
+		delete(cond.pending, url)
+	}
+	return nil
+}
+
+func (cond *conditionalRequests) add(store repositories.ETagRepository, etag domain.ETag) {
+	cond.mu.Lock()
+	defer cond.mu.Unlock()
+
+	cond.store = store
+	cond.pending[etag.URL] = etag
+}
+
+// WithBranch makes commit listings requested with ctx walk the history of branch instead of
+// the default branch.
+func WithBranch(ctx context.Context, branch string) context.Context {
+	return domain.WithBranch(ctx, branch)
+}
+
+func branchOf(ctx context.Context) string {
+	return domain.BranchFromContext(ctx)
+}
+
+// CacheStats returns how many conditional requests were answered with 304 (hits)
+// and how many had to download a full response (misses).
+func (c *Client) CacheStats() (hits, misses int64) {
+	return c.cacheHits.Load(), c.cacheMisses.Load()
+}
+
+// RateLimitBudget returns the GitHub request budget shared by the clients of this process,
+// summed over every active token of the pool.
+func (c *Client) RateLimitBudget(ctx context.Context) (domain.RateLimit, error) {
+	if c.tokens.Len() == 0 {
+		return c.rateLimiter.Budget(ctx, anonymousKey)
+	}
+
+	health, err := c.tokens.health(ctx, c.rateLimiter)
+	if err != nil {
+		return domain.RateLimit{}, err
+	}
+
+	var total domain.RateLimit
+	for _, tok := range health {
+		if tok.Status == domain.TokenStatusRetired {
+			continue
+		}
+		total.Limit += tok.Limit
+		total.Remaining += tok.Remaining
+		if total.Reset.IsZero() || tok.Reset.Before(total.Reset) {
+			total.Reset = tok.Reset
+		}
+	}
+	return total, nil
+}
+
+// TokenHealth reports the quota and status of every token in the pool.
+func (c *Client) TokenHealth(ctx context.Context) ([]domain.TokenHealth, error) {
+	return c.tokens.health(ctx, c.rateLimiter)
+}
+
+// authorize sets the Authorization header of req, either from the GitHub App installation
+// covering the requested repository, from the pool token set with withToken or from the pool
+// token with the most remaining quota. It returns the pool token used, if any, and the rate limit
+// key of the credential.
+func (c *Client) authorize(req *http.Request) (*poolToken, string, error) {
+	ctx := req.Context()
+
+	if c.app != nil {
+		owner, repo := c.repositoryOf(ctx, req.URL)
+		header, key, err := c.app.authorization(ctx, owner, repo)
+		if err != nil {
+			return nil, "", fmt.Errorf("github app authentication: %w", err)
+		}
+		req.Header.Set("Authorization", header)
+		return nil, key, nil
+	}
+
+	if tok, ok := ctx.Value(tokenKey{}).(*poolToken); ok && !c.tokens.retired(tok) {
+		req.Header.Set("Authorization", tok.header)
+		return tok, tok.id, nil
+	}
+
+	tok, err := c.tokens.pick(ctx, c.rateLimiter)
+	if err != nil {
+		return nil, "", err
+	}
+	if tok == nil {
+		return nil, anonymousKey, nil
+	}
+	req.Header.Set("Authorization", tok.header)
+	return tok, tok.id, nil
+}
+
+type mediaTypeKey struct{}
+
+// withMediaType makes requests made with ctx ask for mediaType, e.g. to get extra fields.
+func withMediaType(ctx context.Context, mediaType string) context.Context {
+	return context.WithValue(ctx, mediaTypeKey{}, mediaType)
+}
+
+type repositoryKey struct{}
+
+// withRepository records in ctx the repository a request that has no repository in its URL,
+// such as a GraphQL query, is about.
+func withRepository(ctx context.Context, owner, repo string) context.Context {
+	return context.WithValue(ctx, repositoryKey{}, [2]string{owner, repo})
+}
+
+// repositoryOf returns the owner and name of the repository a request is about, taken from the
+// request context or from the URL relative to the base URL.
+func (c *Client) repositoryOf(ctx context.Context, u *url.URL) (owner, repo string) {
+	if r, ok := ctx.Value(repositoryKey{}).([2]string); ok {
+		return r[0], r[1]
+	}
+
+	base, err := url.Parse(c.baseURL)
+	if err != nil {
+		return "", ""
+	}
+
+	rest := strings.TrimPrefix(u.Path, strings.TrimSuffix(base.Path, "/"))
+	parts := strings.Split(strings.Trim(rest, "/"), "/")
+	if len(parts) < 2 {
+		return "", ""
+	}
+	return parts[0], parts[1]
+}
+
+// send executes req with the pool token that has the most remaining quota, once the rate
+// limiter allows it. Rate limit headers of the response are recorded against the token,
+// requests rejected by a rate limit are retried and tokens rejected with a 401 are retired.
+func (c *Client) send(req *http.Request) (*http.Response, error) {
+	ctx := req.Context()
+	maxRetries := 3 + c.tokens.Len()
+
+	for attempt := 0; ; attempt++ {
+		if attempt > 0 && req.GetBody != nil {
+			body, err := req.GetBody()
+			if err != nil {
+				return nil, fmt.Errorf("failed to rewind request body: %w", err)
+			}
+			req.Body = body
+		}
+
+		tok, key, err := c.authorize(req)
+		if err != nil {
+			return nil, err
+		}
+		if strings.HasSuffix(req.URL.Path, "/graphql") {
+			// GraphQL requests are accounted against their own quota.
+			key += ":graphql"
+		}
+
+		if err := c.rateLimiter.Acquire(ctx, key); err != nil {
+			return nil, fmt.Errorf("waiting for github rate limit: %w", err)
+		}
+
+		resp, err := c.httpClient.Do(req)
+		if err != nil {
+			return nil, err
+		}
+
+		rl, limited := parseRateLimit(resp)
+		if err := c.rateLimiter.Update(ctx, key, rl); err != nil {
+			c.logger.Warn("failed to update github rate limit", zap.Error(err))
+		}
+
+		if resp.StatusCode == http.StatusUnauthorized {
+			switch {
+			case c.app != nil:
+				c.app.invalidate(key)
+				c.logger.Warn("GitHub rejected installation token, refreshing it", zap.String("rate_limit_key", key))
+				limited = true
+			case tok != nil:
+				c.tokens.retire(tok)
+				c.logger.Warn("GitHub rejected token, retiring it", zap.String("token_id", tok.id), zap.String("fingerprint", tok.fingerprint))
+				limited = true
+			}
+		}
+
+		if !limited || attempt >= maxRetries {
+			return resp, nil
+		}
+
+		resp.Body.Close()
+		c.logger.Warn("GitHub request rejected, retrying",
+			zap.String("url", req.URL.String()),
+			zap.String("rate_limit_key", key),
+			zap.Int("status_code", resp.StatusCode),
+			zap.Time("blocked_until", rl.BlockedUntil),
+			zap.Int("attempt", attempt+1),
+		)
 	}
 }
 
+// do executes req, setting the cached validators of the request URL on conditional requests.
+func (c *Client) do(req *http.Request) (*http.Response, error) {
+	ctx := req.Context()
+	cond := conditionalOf(ctx)
+	conditional := c.etagStore != nil && cond != nil
+	key := req.URL.String()
+
+	if mediaType, ok := ctx.Value(mediaTypeKey{}).(string); ok {
+		req.Header.Set("Accept", mediaType)
+	}
+
+	if conditional {
+		cached, err := c.etagStore.ByURL(ctx, key)
+		if err != nil {
+			c.logger.Warn("failed to read cached etag", zap.String("url", key), zap.Error(err))
+		}
+		if cached != nil {
+			if cached.ETag != "" {
+				req.Header.Set("If-None-Match", cached.ETag)
+			}
+			if cached.LastModified != "" {
+				req.Header.Set("If-Modified-Since", cached.LastModified)
+			}
+		}
+	}
+
+	resp, err := c.sendFollowingRedirects(req)
+	if err != nil || !conditional {
+		return resp, err
+	}
+
+	switch resp.StatusCode {
+	case http.StatusNotModified:
+		c.cacheHits.Add(1)
+	case http.StatusOK:
+		c.cacheMisses.Add(1)
+		etag, lastModified := resp.Header.Get("ETag"), resp.Header.Get("Last-Modified")
+		if etag != "" || lastModified != "" {
+			cond.add(c.etagStore, domain.ETag{URL: key, ETag: etag, LastModified: lastModified})
+		}
+	}
+
+	hits, misses := c.CacheStats()
+	c.logger.Info("conditional github request",
+		zap.String("url", key),
+		zap.Int("status_code", resp.StatusCode),
+		zap.Int64("etag_hits", hits),
+		zap.Int64("etag_misses", misses),
+	)
+
+	return resp, nil
+}
+
+// sendFollowingRedirects sends req and follows the redirects GitHub answers requests for a
+// renamed or transferred repository with, e.g. from /repos/old-owner/old-name to
+// /repositories/1296269. Only GET requests are followed, their body being empty, and only to the
+// same scheme and host, since every hop is sent with the credentials of the client.
+func (c *Client) sendFollowingRedirects(req *http.Request) (*http.Response, error) {
+	resp, err := c.send(req)
+	for hops := 0; err == nil && req.Method == http.MethodGet && hops < maxRedirects; hops++ {
+		switch resp.StatusCode {
+		case http.StatusMovedPermanently, http.StatusFound, http.StatusTemporaryRedirect, http.StatusPermanentRedirect:
+		default:
+			return resp, nil
+		}
+
+		location := resp.Header.Get("Location")
+		if location == "" {
+			return resp, nil
+		}
+		next, parseErr := req.URL.Parse(location)
+		if parseErr != nil {
+			return resp, nil
+		}
+		if next.Scheme != req.URL.Scheme || !strings.EqualFold(next.Host, req.URL.Host) {
+			c.logger.Warn("GitHub redirected to another host, not following it",
+				zap.String("from", req.URL.String()),
+				zap.String("to", next.String()),
+			)
+			return resp, nil
+		}
+		resp.Body.Close()
+
+		c.logger.Info("GitHub resource moved, following redirect",
+			zap.String("from", req.URL.String()),
+			zap.String("to", next.String()),
+			zap.Int("status_code", resp.StatusCode),
+		)
+
+		ctx := req.Context()
+		if _, ok := ctx.Value(repositoryKey{}).([2]string); !ok {
+			// The new URL only names the repository by its ID.
+			owner, repo := c.repositoryOf(ctx, req.URL)
+			ctx = withRepository(ctx, owner, repo)
+		}
+		req = req.Clone(ctx)
+		req.URL = next
+		req.Host = next.Host
+		resp, err = c.send(req)
+	}
+	return resp, err
+}
+
+// confirmNotFound sends req again with every other active token of the pool after GitHub answered
+// it with notFound, as GitHub also answers 404 for private repositories a token cannot access.
+// It returns the first response that is not a 404, or notFound once every token got one.
+// Installation tokens of a GitHub App are picked per repository and are not rotated.
+func (c *Client) confirmNotFound(req *http.Request, notFound *http.Response) (*http.Response, error) {
+	if c.app != nil {
+		return notFound, nil
+	}
+
+	used := req.Header.Get("Authorization")
+	ctx := withoutConditionalRequests(req.Context())
+	for _, tok := range c.tokens.active() {
+		if tok.header == used {
+			continue
+		}
+
+		retry := req.Clone(withToken(ctx, tok))
+		retry.Header.Del("If-None-Match")
+		retry.Header.Del("If-Modified-Since")
+		resp, err := c.sendFollowingRedirects(retry)
+		if err != nil {
+			notFound.Body.Close()
+			return nil, err
+		}
+		if resp.StatusCode != http.StatusNotFound {
+			notFound.Body.Close()
+			c.logger.Warn("GitHub resource hidden from a token, found with another one",
+				zap.String("url", req.URL.String()),
+				zap.String("token_id", tok.id),
+			)
+			return resp, nil
+		}
+		resp.Body.Close()
+	}
+	return notFound, nil
+}
+
 // GitHubError represents an error response from the GitHub API.
 type GitHubError struct {
 	Message          string `json:"message"`
@@ -58,10 +515,42 @@ type CommitDetail struct {
 	CommentCount int    `json:"comment_count"`
 }
 
+// CommitStats holds the line statistics of a single commit.
+type CommitStats struct {
+	Total     int `json:"total"`
+	Additions int `json:"additions"`
+	Deletions int `json:"deletions"`
+}
+
+// CommitFile is a file changed by a commit.
+type CommitFile struct {
+	Filename  string `json:"filename"`
+	Status    string `json:"status"`
+	Additions int    `json:"additions"`
+	Deletions int    `json:"deletions"`
+}
+
 type CommitResponse struct {
 	SHA    string       `json:"sha"`
 	URL    string       `json:"url"`
 	Commit CommitDetail `json:"commit"`
+	// Author and Committer are the GitHub accounts linked to the commit, nil for unknown emails.
+	Author    *CommitUser `json:"author"`
+	Committer *CommitUser `json:"committer"`
+	// Stats and Files are only returned by the single commit endpoint.
+	Stats *CommitStats `json:"stats,omitempty"`
+	Files []CommitFile `json:"files,omitempty"`
+}
+
+// CommitUser is the GitHub account of the author or committer of a commit.
+type CommitUser struct {
+	Login string `json:"login"`
+	Type  string `json:"type"`
+}
+
+// IsBot reports whether u is a bot account, such as a GitHub App.
+func (u *CommitUser) IsBot() bool {
+	return u != nil && (u.Type == "Bot" || botdetect.IsGithubApp(u.Login))
 }
 
 type RepositoryOwner struct {
@@ -69,6 +558,7 @@ type RepositoryOwner struct {
 }
 
 type RepositoryResponse struct {
+	ID                  int64           `json:"id"`
 	Name                string          `json:"name"`
 	Owner               RepositoryOwner `json:"owner"`
 	URL                 string          `json:"url"`
@@ -78,6 +568,8 @@ type RepositoryResponse struct {
 	OpenIssuesCount     int             `json:"open_issues_count"`
 	WatchersCount       int             `json:"watchers"`
 	StarsCount          int             `json:"stargazers_count"`
+	DefaultBranch       string          `json:"default_branch"`
+	Archived            bool            `json:"archived"`
 }
 
 func parseLastPage(linkHeader string) int {
@@ -137,6 +629,49 @@ func parseNextLink(linkHeader string) string {
 	return ""
 }
 
+// eachPage requests endpoint on behalf of ownerName/repositoryName and follows the rel="next"
+// links of its responses, handing every page body to visit until it returns false or no page is left.
+// With a conditional ctx it returns domain.ErrNotModified when the first page has not changed.
+func (c *Client) eachPage(ctx context.Context, ownerName, repositoryName, endpoint string, visit func(body []byte) (bool, error)) error {
+	ctx = withRepository(ctx, ownerName, repositoryName)
+	for page := 1; endpoint != ""; page++ {
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+		if err != nil {
+			return fmt.Errorf("failed to create request for page %d: %w", page, err)
+		}
+
+		resp, err := c.do(req)
+		if err != nil {
+			return fmt.Errorf("failed to get page %d: %w", page, err)
+		}
+		body, err := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		if err != nil {
+			return fmt.Errorf("failed to read page %d response: %w", page, err)
+		}
+		if resp.StatusCode == http.StatusNotModified {
+			return fmt.Errorf("page %d: %w", page, domain.ErrNotModified)
+		}
+		if resp.StatusCode != http.StatusOK {
+			var ghErr GitHubError
+			_ = sonic.Unmarshal(body, &ghErr)
+			return fmt.Errorf("GitHub API error %d on page %d: %s", resp.StatusCode, page, ghErr.Message)
+		}
+		// Only the first page is conditional: once it has changed every other page has to be read in full.
+		ctx = withoutConditionalRequests(ctx)
+
+		more, err := visit(body)
+		if err != nil {
+			return fmt.Errorf("failed to process page %d: %w", page, err)
+		}
+		if !more {
+			return nil
+		}
+		endpoint = parseNextLink(resp.Header.Get("Link"))
+	}
+	return nil
+}
+
 // GetCommits calls the commits endpoint and returns all the commits attached to the repositoryName.
 // It supports optional "since" and "until" query parameters to filter commits.
 func (c *Client) GetCommitsOLD(repositoryName, ownerName string, since, until *time.Time) ([]CommitResponse, error) {
@@ -156,7 +691,7 @@ func (c *Client) GetCommitsOLD(repositoryName, ownerName string, since, until *t
 	req.URL.RawQuery = q.Encode()
 
 	// Execute the request.
-	resp, err := c.httpClient.Do(req)
+	resp, err := c.do(req)
 	if err != nil {
 		return nil, fmt.Errorf("failed to submit get commits http request: %w", err)
 	}
@@ -198,11 +733,6 @@ func (c *Client) GetCommits(repositoryName, ownerName string, since, until *time
 			return nil, fmt.Errorf("failed to create request: %w", err)
 		}
 
-		// Set the Authorization header if token is available.
-		if c.config.GetGithubToken() != "" {
-			req.Header.Set("Authorization", c.config.GetGithubToken())
-		}
-
 		q := req.URL.Query()
 		if since != nil || !since.IsZero() {
 			q.Set("since", since.UTC().Format(time.RFC3339))
@@ -216,7 +746,7 @@ func (c *Client) GetCommits(repositoryName, ownerName string, since, until *time
 		req.URL.RawQuery = q.Encode()
 
 		// Execute the request.
-		resp, err := c.httpClient.Do(req)
+		resp, err := c.do(req)
 		if err != nil {
 			return nil, fmt.Errorf("failed to submit get commits http request: %w", err)
 		}
@@ -266,25 +796,23 @@ func (c *Client) GetCommitsNew(ctx context.Context, repositoryName, ownerName st
 		return fmt.Errorf("failed to create request for page 1: %w", err)
 	}
 
-	// Authorization Token
-	if c.config.GetGithubToken() != "" {
-		req.Header.Set("Authorization", c.config.GetGithubToken())
-	}
-
 	q := req.URL.Query()
 	if since != nil {
 		q.Set("since", since.UTC().Format(time.RFC3339))
 	}
-	if !until.IsZero() {
+	if until != nil && !until.IsZero() {
 		q.Set("until", until.UTC().Format(time.RFC3339))
 	}
+	if branch := branchOf(ctx); branch != "" {
+		q.Set("sha", branch)
+	}
 	q.Set("per_page", fmt.Sprintf("%d", pageSize))
 	q.Set("page", fmt.Sprintf("%d", page))
 	req.URL.RawQuery = q.Encode()
 
 	c.logger.Sugar().Infof("URL: %s", req.URL.String())
 
-	resp, err := c.httpClient.Do(req)
+	resp, err := c.do(req)
 	if err != nil {
 		return fmt.Errorf("failed to get page 1: %w", err)
 	}
@@ -293,6 +821,12 @@ func (c *Client) GetCommitsNew(ctx context.Context, repositoryName, ownerName st
 	if err != nil {
 		return fmt.Errorf("failed to read page 1 response: %w", err)
 	}
+	if resp.StatusCode == http.StatusNotModified {
+		return fmt.Errorf("commits of %s/%s: %w", ownerName, repositoryName, domain.ErrNotModified)
+	}
+	if resp.StatusCode == http.StatusNotFound {
+		return fmt.Errorf("commits of %s/%s: %w", ownerName, repositoryName, domain.ErrNotFound)
+	}
 	if resp.StatusCode != http.StatusOK {
 		return fmt.Errorf("GitHub API error on page 1: %s", string(bodyBytes))
 	}
@@ -319,6 +853,8 @@ func (c *Client) GetCommitsNew(ctx context.Context, repositoryName, ownerName st
 	errCh := make(chan error, numWorkers)
 
 	// Worker function.
+	// Only page 1 is conditional: once it has changed every other page has to be read in full.
+	workerCtx := withoutConditionalRequests(ctx)
 	worker := func() {
 		for pageNum := range jobs {
 			select {
@@ -328,17 +864,12 @@ func (c *Client) GetCommitsNew(ctx context.Context, repositoryName, ownerName st
 			default:
 			}
 
-			req, err := http.NewRequestWithContext(ctx, http.MethodGet, fmt.Sprintf("%s/%s/%s/commits", c.baseURL, ownerName, repositoryName), nil)
+			req, err := http.NewRequestWithContext(workerCtx, http.MethodGet, fmt.Sprintf("%s/%s/%s/commits", c.baseURL, ownerName, repositoryName), nil)
 			if err != nil {
 				errCh <- fmt.Errorf("failed to create request for page %d: %w", pageNum, err)
 				return
 			}
 
-			// Authorization Token
-			if c.config.GetGithubToken() != "" {
-				req.Header.Set("Authorization", c.config.GetGithubToken())
-			}
-
 			q := req.URL.Query()
 			if since != nil {
 				q.Set("since", since.UTC().Format(time.RFC3339))
@@ -346,41 +877,17 @@ func (c *Client) GetCommitsNew(ctx context.Context, repositoryName, ownerName st
 			if until != nil {
 				q.Set("until", until.UTC().Format(time.RFC3339))
 			}
+			if branch := branchOf(ctx); branch != "" {
+				q.Set("sha", branch)
+			}
 			q.Set("per_page", fmt.Sprintf("%d", pageSize))
 			q.Set("page", fmt.Sprintf("%d", pageNum))
 			req.URL.RawQuery = q.Encode()
 
-			// Retry loop to handle rate limiting.
-			attempt := 0
-			maxRetries := 3
-			var r *http.Response
-			for {
-				r, err = c.httpClient.Do(req)
-				if err != nil {
-					errCh <- fmt.Errorf("failed to get page %d: %w", pageNum, err)
-					return
-				}
-				if remainingStr := r.Header.Get("X-RateLimit-Remaining"); remainingStr != "" {
-					remaining, err := strconv.Atoi(remainingStr)
-					if err == nil && remaining <= 0 {
-						resetStr := r.Header.Get("X-RateLimit-Reset")
-						if resetStr != "" {
-							resetUnix, err := strconv.ParseInt(resetStr, 10, 64)
-							if err == nil {
-								sleepDuration := time.Until(time.Unix(resetUnix, 0))
-								c.logger.Warn("Rate limit reached in worker", zap.Int("page", pageNum), zap.Duration("sleepDuration", sleepDuration))
-								time.Sleep(sleepDuration)
-								attempt++
-								if attempt >= maxRetries {
-									errCh <- fmt.Errorf("rate limit exceeded on page %d after max retries", pageNum)
-									return
-								}
-								continue
-							}
-						}
-					}
-				}
-				break
+			r, err := c.do(req)
+			if err != nil {
+				errCh <- fmt.Errorf("failed to get page %d: %w", pageNum, err)
+				return
 			}
 			bodyBytes, err := io.ReadAll(r.Body)
 			r.Body.Close()
@@ -426,26 +933,79 @@ func (c *Client) GetCommitsNew(ctx context.Context, repositoryName, ownerName st
 	return nil
 }
 
+// GetCommitDetails fetches a single commit, including its line statistics and changed files.
+func (c *Client) GetCommitDetails(ctx context.Context, repositoryName, ownerName, sha string) (*CommitResponse, error) {
+	endpoint := fmt.Sprintf("%s/%s/%s/commits/%s", c.baseURL, ownerName, repositoryName, sha)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create get commit details request: %w", err)
+	}
+
+	resp, err := c.do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to submit get commit details http request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("error reading response body: %w", err)
+	}
+
+	// GitHub answers 422 for a SHA it does not know, e.g. one rewritten by a force push.
+	if resp.StatusCode == http.StatusNotFound || resp.StatusCode == http.StatusUnprocessableEntity {
+		return nil, fmt.Errorf("commit %s: %w", sha, domain.ErrNotFound)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		var ghErr GitHubError
+		if err := sonic.Unmarshal(body, &ghErr); err != nil {
+			c.logger.Error("Unexpected status code", zap.Int("status code", resp.StatusCode), zap.Error(err))
+		}
+		return nil, fmt.Errorf("GitHub API error on commit %s: %s", sha, ghErr.Message)
+	}
+
+	var commit CommitResponse
+	if err := sonic.Unmarshal(body, &commit); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal commit http response: %w", err)
+	}
+
+	return &commit, nil
+}
+
 func (c *Client) GetRepositoryDetails(repositoryName, ownerName string) (*RepositoryResponse, error) {
+	return c.GetRepositoryDetailsWithContext(context.Background(), repositoryName, ownerName)
+}
+
+// GetRepositoryDetailsWithContext fetches the details of ownerName/repositoryName.
+// It returns domain.ErrNotModified when ctx is conditional and the repository has not changed,
+// and domain.ErrNotFound when the repository no longer exists. A 404 is only trusted once every
+// token of the pool got it, see confirmNotFound.
+func (c *Client) GetRepositoryDetailsWithContext(ctx context.Context, repositoryName, ownerName string) (*RepositoryResponse, error) {
 	logr := c.logger.With(zap.String("method", "GetRepositoryDetails"))
 	endpoint := fmt.Sprintf(c.baseURL+"/%s/%s", ownerName, repositoryName)
-	req, err := http.NewRequest("GET", endpoint, nil)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create get repository details request: %w", err)
 	}
 
-	// Set the Authorization header if token is available.
-	if c.config.GetGithubToken() != "" {
-		req.Header.Set("Authorization", c.config.GetGithubToken())
+	resp, err := c.do(req)
+	if err == nil && resp.StatusCode == http.StatusNotFound {
+		resp, err = c.confirmNotFound(req, resp)
 	}
-
-	resp, err := c.httpClient.Do(req)
 	if err != nil {
 		return nil, fmt.Errorf("failed to submit get repository details http request: %w", err)
 	}
 
 	defer resp.Body.Close()
 
+	if resp.StatusCode == http.StatusNotModified {
+		return nil, fmt.Errorf("repository %s/%s: %w", ownerName, repositoryName, domain.ErrNotModified)
+	}
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, fmt.Errorf("repository %s/%s: %w", ownerName, repositoryName, domain.ErrNotFound)
+	}
+
 	body, err := io.ReadAll(resp.Body)
 	if err != nil {
 		c.logger.Error("error reading response body", zap.Error(err))
@@ -2,6 +2,8 @@ package githubapi_test
 
 import (
 	"bytes"
+	"context"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
@@ -9,6 +11,7 @@ import (
 	"time"
 
 	"github.com/babyfaceeasy/lema/config"
+	"github.com/babyfaceeasy/lema/internal/domain"
 	"github.com/babyfaceeasy/lema/internal/integrations/githubapi"
 	mock_githubapi "github.com/babyfaceeasy/lema/internal/integrations/githubapi/mock_httpclient"
 	"github.com/bytedance/sonic"
@@ -153,3 +156,407 @@ func TestGetCommits(t *testing.T) {
 		require.WithinDuration(t, expected.Commit.Committer.Date, actual.Commit.Committer.Date, time.Second)
 	}
 }
+
+func TestGetCommitsNewOnBranch(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockHttpClient := mock_githubapi.NewMockHttpClient(ctrl)
+	client := githubapi.NewClient("https://api.github.com/repos", mockHttpClient, zap.NewNop(), &config.Config{})
+
+	jsonBytes, err := sonic.Marshal([]githubapi.CommitResponse{{SHA: "abc123"}})
+	require.NoError(t, err)
+
+	mockHttpClient.
+		EXPECT().
+		Do(gomock.AssignableToTypeOf(&http.Request{})).
+		DoAndReturn(func(req *http.Request) (*http.Response, error) {
+			q := req.URL.Query()
+			require.Equal(t, "release-1", q.Get("sha"))
+			require.Empty(t, q.Get("until"))
+			return &http.Response{
+				StatusCode: http.StatusOK,
+				Body:       io.NopCloser(bytes.NewBuffer(jsonBytes)),
+			}, nil
+		})
+
+	commitCh := make(chan githubapi.CommitResponse, 10)
+	ctx := githubapi.WithBranch(context.Background(), "release-1")
+	err = client.GetCommitsNew(ctx, "chromium", "chromium", nil, nil, 100, commitCh)
+	require.NoError(t, err)
+	close(commitCh)
+
+	require.Len(t, commitCh, 1)
+	require.Equal(t, "abc123", (<-commitCh).SHA)
+}
+
+func TestGetCommitDetailsOfUnknownCommit(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockHttpClient := mock_githubapi.NewMockHttpClient(ctrl)
+	client := githubapi.NewClient("https://api.github.com/repos", mockHttpClient, zap.NewNop(), &config.Config{})
+
+	mockHttpClient.
+		EXPECT().
+		Do(gomock.AssignableToTypeOf(&http.Request{})).
+		Return(&http.Response{
+			StatusCode: http.StatusUnprocessableEntity,
+			Body:       io.NopCloser(bytes.NewBufferString(`{"message":"No commit found for SHA: abc123"}`)),
+		}, nil)
+
+	_, err := client.GetCommitDetails(context.Background(), "chromium", "chromium", "abc123")
+	require.ErrorIs(t, err, domain.ErrNotFound)
+}
+
+// memoryETagStore is an in-memory repositories.ETagRepository used by the tests.
+type memoryETagStore struct {
+	etags map[string]domain.ETag
+}
+
+func (m *memoryETagStore) ByURL(_ context.Context, url string) (*domain.ETag, error) {
+	etag, ok := m.etags[url]
+	if !ok {
+		return nil, nil
+	}
+	return &etag, nil
+}
+
+func (m *memoryETagStore) Save(_ context.Context, etag domain.ETag) error {
+	m.etags[etag.URL] = etag
+	return nil
+}
+
+func TestGetRepositoryDetailsConditional(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockHttpClient := mock_githubapi.NewMockHttpClient(ctrl)
+	logger := zap.NewNop()
+
+	baseURL := "https://api.github.com/repos"
+	store := &memoryETagStore{etags: map[string]domain.ETag{}}
+	client := githubapi.NewClient(baseURL, mockHttpClient, logger, &config.Config{}, githubapi.WithETagStore(store))
+
+	jsonBytes, err := sonic.Marshal(githubapi.RepositoryResponse{Name: "chromium"})
+	require.NoError(t, err)
+
+	gomock.InOrder(
+		mockHttpClient.
+			EXPECT().
+			Do(gomock.AssignableToTypeOf(&http.Request{})).
+			DoAndReturn(func(req *http.Request) (*http.Response, error) {
+				require.Empty(t, req.Header.Get("If-None-Match"))
+				header := http.Header{}
+				header.Set("ETag", `"abc"`)
+				return &http.Response{
+					StatusCode: http.StatusOK,
+					Header:     header,
+					Body:       io.NopCloser(bytes.NewBuffer(jsonBytes)),
+				}, nil
+			}),
+		mockHttpClient.
+			EXPECT().
+			Do(gomock.AssignableToTypeOf(&http.Request{})).
+			DoAndReturn(func(req *http.Request) (*http.Response, error) {
+				require.Equal(t, `"abc"`, req.Header.Get("If-None-Match"))
+				return &http.Response{
+					StatusCode: http.StatusNotModified,
+					Body:       io.NopCloser(bytes.NewBuffer(nil)),
+				}, nil
+			}),
+	)
+
+	ctx := githubapi.WithConditionalRequests(context.Background())
+
+	repoResponse, err := client.GetRepositoryDetailsWithContext(ctx, "chromium", "chromium")
+	require.NoError(t, err)
+	require.Equal(t, "chromium", repoResponse.Name)
+	require.Empty(t, store.etags, "validators are only saved once the caller stored the data")
+	require.NoError(t, githubapi.SaveValidators(ctx))
+
+	_, err = client.GetRepositoryDetailsWithContext(ctx, "chromium", "chromium")
+	require.True(t, errors.Is(err, domain.ErrNotModified))
+
+	hits, misses := client.CacheStats()
+	require.Equal(t, int64(1), hits)
+	require.Equal(t, int64(1), misses)
+}
+
+func TestGetRepositoryDetailsRetriesSecondaryRateLimit(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockHttpClient := mock_githubapi.NewMockHttpClient(ctrl)
+	logger := zap.NewNop()
+
+	limiter := githubapi.NewMemoryRateLimiter()
+	client := githubapi.NewClient("https://api.github.com/repos", mockHttpClient, logger, &config.Config{}, githubapi.WithRateLimiter(limiter))
+
+	jsonBytes, err := sonic.Marshal(githubapi.RepositoryResponse{Name: "chromium"})
+	require.NoError(t, err)
+
+	gomock.InOrder(
+		mockHttpClient.
+			EXPECT().
+			Do(gomock.AssignableToTypeOf(&http.Request{})).
+			DoAndReturn(func(req *http.Request) (*http.Response, error) {
+				header := http.Header{}
+				header.Set("Retry-After", "0")
+				return &http.Response{
+					StatusCode: http.StatusForbidden,
+					Header:     header,
+					Body:       io.NopCloser(bytes.NewBufferString(`{"message":"You have exceeded a secondary rate limit."}`)),
+				}, nil
+			}),
+		mockHttpClient.
+			EXPECT().
+			Do(gomock.AssignableToTypeOf(&http.Request{})).
+			DoAndReturn(func(req *http.Request) (*http.Response, error) {
+				header := http.Header{}
+				header.Set("X-RateLimit-Limit", "5000")
+				header.Set("X-RateLimit-Remaining", "4999")
+				header.Set("X-RateLimit-Reset", fmt.Sprintf("%d", time.Now().Add(time.Hour).Unix()))
+				return &http.Response{
+					StatusCode: http.StatusOK,
+					Header:     header,
+					Body:       io.NopCloser(bytes.NewBuffer(jsonBytes)),
+				}, nil
+			}),
+	)
+
+	repoResponse, err := client.GetRepositoryDetails("chromium", "chromium")
+	require.NoError(t, err)
+	require.Equal(t, "chromium", repoResponse.Name)
+
+	budget, err := client.RateLimitBudget(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, 5000, budget.Limit)
+	require.Equal(t, 4999, budget.Remaining)
+}
+
+func TestRateLimitBudgetsAreKeptPerHost(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockHttpClient := mock_githubapi.NewMockHttpClient(ctrl)
+	limiter := githubapi.NewMemoryRateLimiter()
+	newClient := func(baseURL string) *githubapi.Client {
+		return githubapi.NewClient(baseURL, mockHttpClient, zap.NewNop(), &config.Config{},
+			githubapi.WithRateLimiter(limiter),
+			githubapi.WithTokenPool(githubapi.NewTokenPool([]string{"secret"})),
+		)
+	}
+	githubClient := newClient("https://api.github.com/repos")
+	enterpriseClient := newClient("https://ghe.example.com/api/v3/repos")
+
+	jsonBytes, err := sonic.Marshal(githubapi.RepositoryResponse{Name: "chromium"})
+	require.NoError(t, err)
+
+	mockHttpClient.
+		EXPECT().
+		Do(gomock.AssignableToTypeOf(&http.Request{})).
+		DoAndReturn(func(req *http.Request) (*http.Response, error) {
+			header := http.Header{}
+			header.Set("X-RateLimit-Limit", "5000")
+			header.Set("X-RateLimit-Remaining", "0")
+			header.Set("X-RateLimit-Reset", fmt.Sprintf("%d", time.Now().Add(time.Hour).Unix()))
+			return &http.Response{
+				StatusCode: http.StatusOK,
+				Header:     header,
+				Body:       io.NopCloser(bytes.NewBuffer(jsonBytes)),
+			}, nil
+		})
+
+	_, err = githubClient.GetRepositoryDetails("chromium", "chromium")
+	require.NoError(t, err)
+
+	health, err := githubClient.TokenHealth(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, domain.TokenStatusExhausted, health[0].Status)
+
+	health, err = enterpriseClient.TokenHealth(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, domain.TokenStatusActive, health[0].Status)
+	require.Zero(t, health[0].Limit)
+}
+
+func TestGetRepositoryDetailsFollowsMovedRepository(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockHttpClient := mock_githubapi.NewMockHttpClient(ctrl)
+	client := githubapi.NewClient("https://api.github.com/repos", mockHttpClient, zap.NewNop(), &config.Config{})
+
+	jsonBytes, err := sonic.Marshal(githubapi.RepositoryResponse{ID: 1296269, Name: "new-name", Owner: githubapi.RepositoryOwner{Login: "new-owner"}})
+	require.NoError(t, err)
+
+	gomock.InOrder(
+		mockHttpClient.
+			EXPECT().
+			Do(gomock.AssignableToTypeOf(&http.Request{})).
+			DoAndReturn(func(req *http.Request) (*http.Response, error) {
+				require.Equal(t, "https://api.github.com/repos/old-owner/old-name", req.URL.String())
+				header := http.Header{}
+				header.Set("Location", "https://api.github.com/repositories/1296269")
+				return &http.Response{
+					StatusCode: http.StatusMovedPermanently,
+					Header:     header,
+					Body:       io.NopCloser(bytes.NewBufferString(`{"message":"Moved Permanently","url":"https://api.github.com/repositories/1296269"}`)),
+				}, nil
+			}),
+		mockHttpClient.
+			EXPECT().
+			Do(gomock.AssignableToTypeOf(&http.Request{})).
+			DoAndReturn(func(req *http.Request) (*http.Response, error) {
+				require.Equal(t, "https://api.github.com/repositories/1296269", req.URL.String())
+				return &http.Response{
+					StatusCode: http.StatusOK,
+					Body:       io.NopCloser(bytes.NewBuffer(jsonBytes)),
+				}, nil
+			}),
+		mockHttpClient.
+			EXPECT().
+			Do(gomock.AssignableToTypeOf(&http.Request{})).
+			DoAndReturn(func(req *http.Request) (*http.Response, error) {
+				return &http.Response{
+					StatusCode: http.StatusNotFound,
+					Body:       io.NopCloser(bytes.NewBufferString(`{"message":"Not Found"}`)),
+				}, nil
+			}),
+	)
+
+	repoResponse, err := client.GetRepositoryDetails("old-name", "old-owner")
+	require.NoError(t, err)
+	require.Equal(t, int64(1296269), repoResponse.ID)
+	require.Equal(t, "new-owner", repoResponse.Owner.Login)
+	require.Equal(t, "new-name", repoResponse.Name)
+
+	_, err = client.GetRepositoryDetails("deleted", "old-owner")
+	require.True(t, errors.Is(err, domain.ErrNotFound))
+}
+
+func TestGetRepositoryDetailsDoesNotFollowRedirectsToOtherHosts(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockHttpClient := mock_githubapi.NewMockHttpClient(ctrl)
+	client := githubapi.NewClient(
+		"https://api.github.com/repos",
+		mockHttpClient,
+		zap.NewNop(),
+		&config.Config{},
+		githubapi.WithRateLimiter(githubapi.NewMemoryRateLimiter()),
+		githubapi.WithTokenPool(githubapi.NewTokenPool([]string{"secret"})),
+	)
+
+	mockHttpClient.
+		EXPECT().
+		Do(gomock.AssignableToTypeOf(&http.Request{})).
+		DoAndReturn(func(req *http.Request) (*http.Response, error) {
+			header := http.Header{}
+			header.Set("Location", "https://attacker.example.com/repositories/1296269")
+			return &http.Response{
+				StatusCode: http.StatusMovedPermanently,
+				Header:     header,
+				Body:       io.NopCloser(bytes.NewBufferString(`{"message":"Moved Permanently"}`)),
+			}, nil
+		}).
+		Times(1)
+
+	_, err := client.GetRepositoryDetails("old-name", "old-owner")
+	require.Error(t, err)
+}
+
+func TestGetRepositoryDetailsConfirmsNotFoundWithEveryToken(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockHttpClient := mock_githubapi.NewMockHttpClient(ctrl)
+	client := githubapi.NewClient(
+		"https://api.github.com/repos",
+		mockHttpClient,
+		zap.NewNop(),
+		&config.Config{},
+		githubapi.WithRateLimiter(githubapi.NewMemoryRateLimiter()),
+		githubapi.WithTokenPool(githubapi.NewTokenPool([]string{"outsider", "member"})),
+	)
+
+	jsonBytes, err := sonic.Marshal(githubapi.RepositoryResponse{Name: "private"})
+	require.NoError(t, err)
+
+	mockHttpClient.
+		EXPECT().
+		Do(gomock.AssignableToTypeOf(&http.Request{})).
+		DoAndReturn(func(req *http.Request) (*http.Response, error) {
+			if req.Header.Get("Authorization") == "Bearer member" {
+				return &http.Response{
+					StatusCode: http.StatusOK,
+					Body:       io.NopCloser(bytes.NewBuffer(jsonBytes)),
+				}, nil
+			}
+			return &http.Response{
+				StatusCode: http.StatusNotFound,
+				Body:       io.NopCloser(bytes.NewBufferString(`{"message":"Not Found"}`)),
+			}, nil
+		}).
+		Times(2)
+
+	repoResponse, err := client.GetRepositoryDetails("private", "corp")
+	require.NoError(t, err)
+	require.Equal(t, "private", repoResponse.Name)
+}
+
+func TestTokenPoolRetiresUnauthorizedToken(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockHttpClient := mock_githubapi.NewMockHttpClient(ctrl)
+	logger := zap.NewNop()
+
+	pool := githubapi.NewTokenPool([]string{"revoked", "token valid"})
+	client := githubapi.NewClient(
+		"https://api.github.com/repos",
+		mockHttpClient,
+		logger,
+		&config.Config{},
+		githubapi.WithRateLimiter(githubapi.NewMemoryRateLimiter()),
+		githubapi.WithTokenPool(pool),
+	)
+
+	jsonBytes, err := sonic.Marshal(githubapi.RepositoryResponse{Name: "chromium"})
+	require.NoError(t, err)
+
+	gomock.InOrder(
+		mockHttpClient.
+			EXPECT().
+			Do(gomock.AssignableToTypeOf(&http.Request{})).
+			DoAndReturn(func(req *http.Request) (*http.Response, error) {
+				require.Equal(t, "Bearer revoked", req.Header.Get("Authorization"))
+				return &http.Response{
+					StatusCode: http.StatusUnauthorized,
+					Body:       io.NopCloser(bytes.NewBufferString(`{"message":"Bad credentials"}`)),
+				}, nil
+			}),
+		mockHttpClient.
+			EXPECT().
+			Do(gomock.AssignableToTypeOf(&http.Request{})).
+			DoAndReturn(func(req *http.Request) (*http.Response, error) {
+				require.Equal(t, "token valid", req.Header.Get("Authorization"))
+				return &http.Response{
+					StatusCode: http.StatusOK,
+					Body:       io.NopCloser(bytes.NewBuffer(jsonBytes)),
+				}, nil
+			}),
+	)
+
+	_, err = client.GetRepositoryDetails("chromium", "chromium")
+	require.NoError(t, err)
+
+	health, err := client.TokenHealth(context.Background())
+	require.NoError(t, err)
+	require.Len(t, health, 2)
+	require.Equal(t, domain.TokenStatusRetired, health[0].Status)
+	require.Equal(t, domain.TokenStatusActive, health[1].Status)
+	require.NotContains(t, health[0].Fingerprint, "revoked")
+}
@@ -0,0 +1,55 @@
+package githubapi
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/babyfaceeasy/lema/internal/domain"
+	"github.com/bytedance/sonic"
+	"go.uber.org/zap"
+)
+
+// rawMediaType makes content requests return the file itself rather than its base64 encoding.
+const rawMediaType = "application/vnd.github.raw+json"
+
+// GetFileContents fetches a file of the default branch of ownerName/repositoryName. It returns
+// domain.ErrNotFound when the repository has no such file.
+func (c *Client) GetFileContents(ctx context.Context, repositoryName, ownerName, path string) ([]byte, error) {
+	segments := strings.Split(strings.Trim(path, "/"), "/")
+	for i, segment := range segments {
+		segments[i] = url.PathEscape(segment)
+	}
+	endpoint := fmt.Sprintf("%s/%s/%s/contents/%s", c.baseURL, ownerName, repositoryName, strings.Join(segments, "/"))
+	req, err := http.NewRequestWithContext(withMediaType(ctx, rawMediaType), http.MethodGet, endpoint, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create get file contents request: %w", err)
+	}
+
+	resp, err := c.do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to submit get file contents http request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("error reading response body: %w", err)
+	}
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+		return body, nil
+	case http.StatusNotFound:
+		return nil, fmt.Errorf("file %s of %s/%s: %w", path, ownerName, repositoryName, domain.ErrNotFound)
+	default:
+		var ghErr GitHubError
+		if err := sonic.Unmarshal(body, &ghErr); err != nil {
+			c.logger.Error("Unexpected status code", zap.Int("status code", resp.StatusCode), zap.Error(err))
+		}
+		return nil, fmt.Errorf("GitHub API error on file %s: %s", path, ghErr.Message)
+	}
+}
@@ -0,0 +1,43 @@
+package githubapi_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/babyfaceeasy/lema/config"
+	"github.com/babyfaceeasy/lema/internal/domain"
+	"github.com/babyfaceeasy/lema/internal/integrations/githubapi"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestGetFileContentsAsksForRawFile(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "application/vnd.github.raw+json", r.Header.Get("Accept"))
+		if r.URL.Path != "/repos/chromium/chromium/contents/.mailmap" {
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"message":"Not Found"}`))
+			return
+		}
+		w.Write([]byte("Jane Doe <jane@chromium.org> <jdoe@corp.example>\n"))
+	}))
+	defer server.Close()
+
+	client := githubapi.NewClient(
+		server.URL+"/repos",
+		server.Client(),
+		zap.NewNop(),
+		&config.Config{},
+		githubapi.WithRateLimiter(githubapi.NewMemoryRateLimiter()),
+	)
+
+	contents, err := client.GetFileContents(context.Background(), "chromium", "chromium", ".mailmap")
+	require.NoError(t, err)
+	assert.Equal(t, "Jane Doe <jane@chromium.org> <jdoe@corp.example>\n", string(contents))
+
+	_, err = client.GetFileContents(context.Background(), "v8", "v8", ".mailmap")
+	assert.ErrorIs(t, err, domain.ErrNotFound)
+}
@@ -0,0 +1,230 @@
+package githubapi
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/bytedance/sonic"
+	"go.uber.org/zap"
+)
+
+// maxGraphQLPageSize is the largest page GitHub accepts on a GraphQL connection.
+const maxGraphQLPageSize = 100
+
+// historyQuery walks the history of the ref selected by the first verb, either defaultBranchRef or
+// ref(qualifiedName: $ref) with the $ref variable declared by the second verb.
+const historyQuery = `
+query($owner: String!, $name: String!, $first: Int!, $after: String, $since: GitTimestamp, $until: GitTimestamp%[2]s) {
+  repository(owner: $owner, name: $name) {
+    %[1]s {
+      target {
+        ... on Commit {
+          history(first: $first, after: $after, since: $since, until: $until) {
+            pageInfo {
+              hasNextPage
+              endCursor
+            }
+            nodes {
+              oid
+              message
+              additions
+              deletions
+              changedFilesIfAvailable
+              author {
+                name
+                email
+                date
+              }
+              committer {
+                name
+                email
+                date
+              }
+              parents(first: 10) {
+                nodes {
+                  oid
+                }
+              }
+            }
+          }
+        }
+      }
+    }
+  }
+}`
+
+type graphQLRequest struct {
+	Query     string         `json:"query"`
+	Variables map[string]any `json:"variables"`
+}
+
+type graphQLError struct {
+	Type    string `json:"type"`
+	Message string `json:"message"`
+}
+
+// GraphQLCommit is a commit returned by the GraphQL history connection.
+type GraphQLCommit struct {
+	OID          string `json:"oid"`
+	URL          string `json:"-"`
+	Message      string `json:"message"`
+	Additions    int    `json:"additions"`
+	Deletions    int    `json:"deletions"`
+	ChangedFiles int    `json:"changedFilesIfAvailable"`
+	Author       Person `json:"author"`
+	Committer    Person `json:"committer"`
+	Parents      struct {
+		Nodes []struct {
+			OID string `json:"oid"`
+		} `json:"nodes"`
+	} `json:"parents"`
+}
+
+type historyResponse struct {
+	Data struct {
+		Repository *struct {
+			DefaultBranchRef *historyRef `json:"defaultBranchRef"`
+			Ref              *historyRef `json:"ref"`
+		} `json:"repository"`
+	} `json:"data"`
+	Errors []graphQLError `json:"errors"`
+}
+
+type historyRef struct {
+	Target struct {
+		History struct {
+			PageInfo struct {
+				HasNextPage bool   `json:"hasNextPage"`
+				EndCursor   string `json:"endCursor"`
+			} `json:"pageInfo"`
+			Nodes []GraphQLCommit `json:"nodes"`
+		} `json:"history"`
+	} `json:"target"`
+}
+
+// graphQL posts query to the GraphQL endpoint on behalf of ownerName/repositoryName and
+// returns the raw response body.
+func (c *Client) graphQL(ctx context.Context, ownerName, repositoryName, query string, variables map[string]any) ([]byte, error) {
+	payload, err := sonic.Marshal(graphQLRequest{Query: query, Variables: variables})
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal graphql request: %w", err)
+	}
+
+	// Every query is posted to the same URL, so cached validators would mix up repositories and queries.
+	ctx = withoutConditionalRequests(withRepository(ctx, ownerName, repositoryName))
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.graphqlURL, bytes.NewReader(payload))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create graphql request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := c.do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to submit graphql request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("error reading response body: %w", err)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		var ghErr GitHubError
+		_ = sonic.Unmarshal(body, &ghErr)
+		return nil, fmt.Errorf("GitHub GraphQL API error %d: %s", resp.StatusCode, ghErr.Message)
+	}
+
+	return body, nil
+}
+
+// GetCommitsGraphQL walks the history of the default branch of ownerName/repositoryName, or of the
+// branch set with WithBranch, with the GraphQL API and sends each commit through commitCh. Compared to GetCommitsNew every commit
+// carries its committer, line statistics and parents, and up to 100 commits come per call.
+func (c *Client) GetCommitsGraphQL(ctx context.Context, repositoryName, ownerName string, since, until *time.Time, pageSize int, commitCh chan<- GraphQLCommit) error {
+	if pageSize <= 0 || pageSize > maxGraphQLPageSize {
+		pageSize = maxGraphQLPageSize
+	}
+
+	variables := map[string]any{
+		"owner": ownerName,
+		"name":  repositoryName,
+		"first": pageSize,
+	}
+	if since != nil && !since.IsZero() {
+		variables["since"] = since.UTC().Format(time.RFC3339)
+	}
+	if until != nil && !until.IsZero() {
+		variables["until"] = until.UTC().Format(time.RFC3339)
+	}
+
+	query := fmt.Sprintf(historyQuery, "defaultBranchRef", "")
+	if branch := branchOf(ctx); branch != "" {
+		query = fmt.Sprintf(historyQuery, "ref(qualifiedName: $ref)", ", $ref: String!")
+		if !strings.HasPrefix(branch, "refs/") {
+			branch = "refs/heads/" + branch
+		}
+		variables["ref"] = branch
+	}
+
+	for page := 1; ; page++ {
+		body, err := c.graphQL(ctx, ownerName, repositoryName, query, variables)
+		if err != nil {
+			return fmt.Errorf("failed to get history page %d: %w", page, err)
+		}
+
+		var history historyResponse
+		if err := sonic.Unmarshal(body, &history); err != nil {
+			return fmt.Errorf("failed to unmarshal history page %d: %w", page, err)
+		}
+		if len(history.Errors) > 0 {
+			messages := make([]string, 0, len(history.Errors))
+			for _, e := range history.Errors {
+				messages = append(messages, e.Message)
+			}
+			return fmt.Errorf("GitHub GraphQL API error on page %d: %s", page, strings.Join(messages, "; "))
+		}
+
+		repo := history.Data.Repository
+		if repo == nil {
+			return fmt.Errorf("repository %s/%s not found", ownerName, repositoryName)
+		}
+		ref := repo.DefaultBranchRef
+		if branch := branchOf(ctx); branch != "" {
+			if repo.Ref == nil {
+				return fmt.Errorf("branch %s of %s/%s not found", branch, ownerName, repositoryName)
+			}
+			ref = repo.Ref
+		}
+		if ref == nil {
+			// empty repository.
+			return nil
+		}
+
+		connection := ref.Target.History
+		for _, commit := range connection.Nodes {
+			commit.URL = fmt.Sprintf("%s/%s/%s/commits/%s", c.baseURL, ownerName, repositoryName, commit.OID)
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case commitCh <- commit:
+			}
+		}
+
+		c.logger.Debug("Fetched history page", zap.Int("page", page), zap.Int("commits", len(connection.Nodes)))
+
+		if !connection.PageInfo.HasNextPage {
+			return nil
+		}
+		if connection.PageInfo.EndCursor == "" {
+			return errors.New("graphql history reported a next page without a cursor")
+		}
+		variables["after"] = connection.PageInfo.EndCursor
+	}
+}
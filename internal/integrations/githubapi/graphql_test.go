@@ -0,0 +1,142 @@
+package githubapi_test
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/babyfaceeasy/lema/config"
+	"github.com/babyfaceeasy/lema/internal/domain"
+	"github.com/babyfaceeasy/lema/internal/integrations/githubapi"
+	"github.com/bytedance/sonic"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestGetCommitsGraphQL(t *testing.T) {
+	pages := map[string]string{
+		"": `{"data":{"repository":{"defaultBranchRef":{"target":{"history":{
+			"pageInfo":{"hasNextPage":true,"endCursor":"cursor-1"},
+			"nodes":[{"oid":"abc123","message":"second","additions":10,"deletions":2,"changedFilesIfAvailable":3,
+				"author":{"name":"John Doe","email":"john@example.com","date":"2025-03-13T23:09:53Z"},
+				"committer":{"name":"CQ Bot","email":"cq@example.com","date":"2025-03-14T10:00:00Z"},
+				"parents":{"nodes":[{"oid":"def456"}]}}]}}}}}}`,
+		"cursor-1": `{"data":{"repository":{"defaultBranchRef":{"target":{"history":{
+			"pageInfo":{"hasNextPage":false,"endCursor":"cursor-2"},
+			"nodes":[{"oid":"def456","message":"first",
+				"author":{"name":"John Doe","email":"john@example.com","date":"2025-03-12T23:09:53Z"},
+				"committer":{"name":"John Doe","email":"john@example.com","date":"2025-03-12T23:09:53Z"},
+				"parents":{"nodes":[]}}]}}}}}}`,
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPost, r.Method)
+		body, _ := io.ReadAll(r.Body)
+
+		var req struct {
+			Variables map[string]any `json:"variables"`
+		}
+		assert.NoError(t, sonic.Unmarshal(body, &req))
+		assert.Equal(t, "chromium", req.Variables["owner"])
+
+		after, _ := req.Variables["after"].(string)
+		w.Write([]byte(pages[after]))
+	}))
+	defer server.Close()
+
+	client := githubapi.NewClient(
+		server.URL+"/repos",
+		server.Client(),
+		zap.NewNop(),
+		&config.Config{},
+		githubapi.WithRateLimiter(githubapi.NewMemoryRateLimiter()),
+	)
+
+	commitCh := make(chan githubapi.GraphQLCommit, 10)
+	err := client.GetCommitsGraphQL(context.Background(), "chromium", "chromium", nil, nil, 100, commitCh)
+	require.NoError(t, err)
+	close(commitCh)
+
+	var commits []githubapi.GraphQLCommit
+	for commit := range commitCh {
+		commits = append(commits, commit)
+	}
+
+	require.Len(t, commits, 2)
+	require.Equal(t, "abc123", commits[0].OID)
+	require.Equal(t, server.URL+"/repos/chromium/chromium/commits/abc123", commits[0].URL)
+	require.Equal(t, "CQ Bot", commits[0].Committer.Name)
+	require.Equal(t, 10, commits[0].Additions)
+	require.Equal(t, "def456", commits[0].Parents.Nodes[0].OID)
+	require.Equal(t, "def456", commits[1].OID)
+}
+
+func TestGetCommitsGraphQLOnBranch(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+
+		var req struct {
+			Query     string         `json:"query"`
+			Variables map[string]any `json:"variables"`
+		}
+		assert.NoError(t, sonic.Unmarshal(body, &req))
+		assert.Contains(t, req.Query, "ref(qualifiedName: $ref)")
+		assert.Equal(t, "refs/heads/release-1", req.Variables["ref"])
+
+		w.Write([]byte(`{"data":{"repository":{"ref":{"target":{"history":{
+			"pageInfo":{"hasNextPage":false,"endCursor":"cursor-1"},
+			"nodes":[{"oid":"abc123","message":"on release",
+				"author":{"name":"John Doe","email":"john@example.com","date":"2025-03-13T23:09:53Z"},
+				"committer":{"name":"John Doe","email":"john@example.com","date":"2025-03-13T23:09:53Z"},
+				"parents":{"nodes":[]}}]}}}}}}`))
+	}))
+	defer server.Close()
+
+	client := githubapi.NewClient(
+		server.URL+"/repos",
+		server.Client(),
+		zap.NewNop(),
+		&config.Config{},
+		githubapi.WithRateLimiter(githubapi.NewMemoryRateLimiter()),
+	)
+
+	commitCh := make(chan githubapi.GraphQLCommit, 10)
+	ctx := githubapi.WithBranch(context.Background(), "release-1")
+	err := client.GetCommitsGraphQL(ctx, "chromium", "chromium", nil, nil, 100, commitCh)
+	require.NoError(t, err)
+	close(commitCh)
+
+	require.Len(t, commitCh, 1)
+	require.Equal(t, "abc123", (<-commitCh).OID)
+}
+
+func TestGetCommitsGraphQLIsNeverConditional(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Empty(t, r.Header.Get("If-None-Match"))
+		w.Header().Set("ETag", `"same-for-every-query"`)
+		w.Write([]byte(`{"data":{"repository":{"defaultBranchRef":{"target":{"history":{
+			"pageInfo":{"hasNextPage":false,"endCursor":""},"nodes":[]}}}}}}`))
+	}))
+	defer server.Close()
+
+	store := &memoryETagStore{etags: map[string]domain.ETag{}}
+	client := githubapi.NewClient(
+		server.URL+"/repos",
+		server.Client(),
+		zap.NewNop(),
+		&config.Config{},
+		githubapi.WithRateLimiter(githubapi.NewMemoryRateLimiter()),
+		githubapi.WithETagStore(store),
+	)
+
+	ctx := githubapi.WithConditionalRequests(context.Background())
+	for _, repo := range []string{"chromium", "v8"} {
+		commitCh := make(chan githubapi.GraphQLCommit, 10)
+		require.NoError(t, client.GetCommitsGraphQL(ctx, repo, "chromium", nil, nil, 100, commitCh))
+		require.NoError(t, githubapi.SaveValidators(ctx))
+	}
+	require.Empty(t, store.etags)
+}
@@ -0,0 +1,62 @@
+package githubapi
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"time"
+
+	"github.com/bytedance/sonic"
+)
+
+type IssueLabel struct {
+	Name string `json:"name"`
+}
+
+type IssueResponse struct {
+	Number    int             `json:"number"`
+	Title     string          `json:"title"`
+	State     string          `json:"state"`
+	HTMLURL   string          `json:"html_url"`
+	User      RepositoryOwner `json:"user"`
+	Labels    []IssueLabel    `json:"labels"`
+	CreatedAt time.Time       `json:"created_at"`
+	UpdatedAt time.Time       `json:"updated_at"`
+	ClosedAt  *time.Time      `json:"closed_at"`
+	// PullRequest is only set for pull requests, which the issues endpoint returns as well.
+	PullRequest *struct {
+		URL string `json:"url"`
+	} `json:"pull_request,omitempty"`
+}
+
+// GetIssues sends the issues of ownerName/repositoryName updated at or after since, or all of
+// them when since is nil, through issueCh. Pull requests are skipped.
+func (c *Client) GetIssues(ctx context.Context, repositoryName, ownerName string, since *time.Time, pageSize int, issueCh chan<- IssueResponse) error {
+	q := url.Values{}
+	q.Set("state", "all")
+	q.Set("sort", "updated")
+	q.Set("direction", "asc")
+	q.Set("per_page", fmt.Sprintf("%d", pageSize))
+	if since != nil {
+		q.Set("since", since.UTC().Format(time.RFC3339))
+	}
+	endpoint := fmt.Sprintf("%s/%s/%s/issues?%s", c.baseURL, ownerName, repositoryName, q.Encode())
+
+	return c.eachPage(ctx, ownerName, repositoryName, endpoint, func(body []byte) (bool, error) {
+		var issues []IssueResponse
+		if err := sonic.Unmarshal(body, &issues); err != nil {
+			return false, err
+		}
+		for _, issue := range issues {
+			if issue.PullRequest != nil {
+				continue
+			}
+			select {
+			case <-ctx.Done():
+				return false, ctx.Err()
+			case issueCh <- issue:
+			}
+		}
+		return len(issues) > 0, nil
+	})
+}
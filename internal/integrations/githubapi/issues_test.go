@@ -0,0 +1,48 @@
+package githubapi_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/babyfaceeasy/lema/config"
+	"github.com/babyfaceeasy/lema/internal/integrations/githubapi"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestGetIssuesSkipsPullRequests(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/repos/chromium/chromium/issues", r.URL.Path)
+		assert.Equal(t, "2025-03-05T00:00:00Z", r.URL.Query().Get("since"))
+		w.Write([]byte(`[
+			{"number":5,"title":"crash","state":"open","user":{"login":"alice"},"labels":[{"name":"bug"}],
+			 "created_at":"2025-03-10T00:00:00Z","updated_at":"2025-03-12T00:00:00Z"},
+			{"number":6,"title":"a pull request","state":"open","user":{"login":"bob"},"labels":[],
+			 "created_at":"2025-03-10T00:00:00Z","updated_at":"2025-03-12T00:00:00Z",
+			 "pull_request":{"url":"https://api.github.com/repos/chromium/chromium/pulls/6"}}]`))
+	}))
+	defer server.Close()
+
+	client := githubapi.NewClient(
+		server.URL+"/repos",
+		server.Client(),
+		zap.NewNop(),
+		&config.Config{},
+		githubapi.WithRateLimiter(githubapi.NewMemoryRateLimiter()),
+	)
+
+	since := time.Date(2025, time.March, 5, 0, 0, 0, 0, time.UTC)
+	issueCh := make(chan githubapi.IssueResponse, 10)
+	err := client.GetIssues(context.Background(), "chromium", "chromium", &since, 100, issueCh)
+	require.NoError(t, err)
+	close(issueCh)
+
+	require.Len(t, issueCh, 1)
+	issue := <-issueCh
+	require.Equal(t, 5, issue.Number)
+	require.Equal(t, "bug", issue.Labels[0].Name)
+}
@@ -0,0 +1,61 @@
+package githubapi
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"time"
+
+	"github.com/bytedance/sonic"
+)
+
+// PullRequestRef is the base or head branch of a pull request.
+type PullRequestRef struct {
+	Ref string `json:"ref"`
+	SHA string `json:"sha"`
+}
+
+type PullRequestResponse struct {
+	Number         int             `json:"number"`
+	Title          string          `json:"title"`
+	State          string          `json:"state"`
+	HTMLURL        string          `json:"html_url"`
+	Draft          bool            `json:"draft"`
+	User           RepositoryOwner `json:"user"`
+	Base           PullRequestRef  `json:"base"`
+	Head           PullRequestRef  `json:"head"`
+	MergeCommitSHA string          `json:"merge_commit_sha"`
+	CreatedAt      time.Time       `json:"created_at"`
+	UpdatedAt      time.Time       `json:"updated_at"`
+	ClosedAt       *time.Time      `json:"closed_at"`
+	MergedAt       *time.Time      `json:"merged_at"`
+}
+
+// GetPullRequests sends the pull requests of ownerName/repositoryName through prCh, most recently
+// updated first. When since is set it stops at the first pull request not updated after it.
+func (c *Client) GetPullRequests(ctx context.Context, repositoryName, ownerName string, since *time.Time, pageSize int, prCh chan<- PullRequestResponse) error {
+	q := url.Values{}
+	q.Set("state", "all")
+	q.Set("sort", "updated")
+	q.Set("direction", "desc")
+	q.Set("per_page", fmt.Sprintf("%d", pageSize))
+	endpoint := fmt.Sprintf("%s/%s/%s/pulls?%s", c.baseURL, ownerName, repositoryName, q.Encode())
+
+	return c.eachPage(ctx, ownerName, repositoryName, endpoint, func(body []byte) (bool, error) {
+		var pulls []PullRequestResponse
+		if err := sonic.Unmarshal(body, &pulls); err != nil {
+			return false, err
+		}
+		for _, pr := range pulls {
+			if since != nil && !pr.UpdatedAt.After(*since) {
+				return false, nil
+			}
+			select {
+			case <-ctx.Done():
+				return false, ctx.Err()
+			case prCh <- pr:
+			}
+		}
+		return len(pulls) > 0, nil
+	})
+}
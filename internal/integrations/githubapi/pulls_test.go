@@ -0,0 +1,63 @@
+package githubapi_test
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/babyfaceeasy/lema/config"
+	"github.com/babyfaceeasy/lema/internal/integrations/githubapi"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestGetPullRequests(t *testing.T) {
+	var server *httptest.Server
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/repos/chromium/chromium/pulls", r.URL.Path)
+		assert.Equal(t, "all", r.URL.Query().Get("state"))
+
+		switch r.URL.Query().Get("page") {
+		case "":
+			w.Header().Set("Link", fmt.Sprintf(`<%s/repos/chromium/chromium/pulls?state=all&page=2>; rel="next"`, server.URL))
+			w.Write([]byte(`[
+				{"number":3,"title":"third","state":"open","user":{"login":"alice"},"base":{"ref":"main"},"head":{"ref":"feature"},
+				 "created_at":"2025-03-10T00:00:00Z","updated_at":"2025-03-12T00:00:00Z"}]`))
+		case "2":
+			w.Write([]byte(`[
+				{"number":2,"title":"second","state":"closed","user":{"login":"bob"},"base":{"ref":"main"},"head":{"ref":"fix"},
+				 "merge_commit_sha":"abc123","created_at":"2025-03-08T00:00:00Z","updated_at":"2025-03-11T00:00:00Z",
+				 "closed_at":"2025-03-11T00:00:00Z","merged_at":"2025-03-11T00:00:00Z"},
+				{"number":1,"title":"first","state":"closed","user":{"login":"bob"},"base":{"ref":"main"},"head":{"ref":"old"},
+				 "created_at":"2025-03-01T00:00:00Z","updated_at":"2025-03-02T00:00:00Z"}]`))
+		default:
+			t.Errorf("unexpected page %s", r.URL.Query().Get("page"))
+		}
+	}))
+	defer server.Close()
+
+	client := githubapi.NewClient(
+		server.URL+"/repos",
+		server.Client(),
+		zap.NewNop(),
+		&config.Config{},
+		githubapi.WithRateLimiter(githubapi.NewMemoryRateLimiter()),
+	)
+
+	// pull request #1 was not updated since the previous sync.
+	since := time.Date(2025, time.March, 5, 0, 0, 0, 0, time.UTC)
+	prCh := make(chan githubapi.PullRequestResponse, 10)
+	err := client.GetPullRequests(context.Background(), "chromium", "chromium", &since, 100, prCh)
+	require.NoError(t, err)
+	close(prCh)
+
+	var numbers []int
+	for pr := range prCh {
+		numbers = append(numbers, pr.Number)
+	}
+	require.Equal(t, []int{3, 2}, numbers)
+}
@@ -0,0 +1,207 @@
+package githubapi
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/babyfaceeasy/lema/internal/domain"
+)
+
+// secondaryRateLimitWait is how long to back off after a secondary rate limit response
+// that carries no Retry-After header, as recommended by GitHub.
+const secondaryRateLimitWait = time.Minute
+
+// RateLimiter is a request budget shared by every GitHub call. GitHub accounts quota per
+// credential, so each budget is identified by a key naming the credential it belongs to.
+type RateLimiter interface {
+	// Acquire blocks until a request may be sent with key or ctx is done.
+	Acquire(ctx context.Context, key string) error
+	// Update records the rate limit state reported by a GitHub response.
+	Update(ctx context.Context, key string, rl domain.RateLimit) error
+	// Budget returns the current budget of key.
+	Budget(ctx context.Context, key string) (domain.RateLimit, error)
+}
+
+// defaultRateLimiter is shared by all clients of the process unless one is set explicitly.
+var defaultRateLimiter = NewMemoryRateLimiter()
+
+// scopedRateLimiter keeps the budgets of one GitHub host apart from those of the other hosts
+// sharing its limiter. Token IDs, the anonymous key and installation keys are only unique within
+// a client, so every key is prefixed with the host the client talks to.
+type scopedRateLimiter struct {
+	limiter RateLimiter
+	scope   string
+}
+
+// scopeRateLimiter returns limiter with its keys scoped to the host of baseURL.
+func scopeRateLimiter(limiter RateLimiter, baseURL string) RateLimiter {
+	scope := baseURL
+	if u, err := url.Parse(baseURL); err == nil && u.Host != "" {
+		scope = strings.ToLower(u.Host)
+	}
+	return &scopedRateLimiter{limiter: limiter, scope: scope}
+}
+
+func (l *scopedRateLimiter) key(key string) string {
+	return l.scope + ":" + key
+}
+
+func (l *scopedRateLimiter) Acquire(ctx context.Context, key string) error {
+	return l.limiter.Acquire(ctx, l.key(key))
+}
+
+func (l *scopedRateLimiter) Update(ctx context.Context, key string, rl domain.RateLimit) error {
+	return l.limiter.Update(ctx, l.key(key), rl)
+}
+
+func (l *scopedRateLimiter) Budget(ctx context.Context, key string) (domain.RateLimit, error) {
+	return l.limiter.Budget(ctx, l.key(key))
+}
+
+type memoryRateLimiter struct {
+	mu      sync.Mutex
+	budgets map[string]*domain.RateLimit
+}
+
+// NewMemoryRateLimiter returns a RateLimiter whose budget is kept in process memory.
+func NewMemoryRateLimiter() RateLimiter {
+	return &memoryRateLimiter{budgets: make(map[string]*domain.RateLimit)}
+}
+
+func (l *memoryRateLimiter) budgetLocked(key string) *domain.RateLimit {
+	budget, ok := l.budgets[key]
+	if !ok {
+		budget = &domain.RateLimit{}
+		l.budgets[key] = budget
+	}
+	return budget
+}
+
+func (l *memoryRateLimiter) Acquire(ctx context.Context, key string) error {
+	for {
+		l.mu.Lock()
+		budget := l.budgetLocked(key)
+		wait := waitFor(budget, time.Now())
+		if wait <= 0 {
+			if budget.Limit > 0 {
+				budget.Remaining--
+			}
+			l.mu.Unlock()
+			return nil
+		}
+		l.mu.Unlock()
+
+		if err := sleepContext(ctx, wait); err != nil {
+			return err
+		}
+	}
+}
+
+// waitFor returns how long a caller has to wait before sending a request with budget.
+func waitFor(budget *domain.RateLimit, now time.Time) time.Duration {
+	if now.Before(budget.BlockedUntil) {
+		return budget.BlockedUntil.Sub(now)
+	}
+	if budget.Limit > 0 && budget.Remaining <= 0 {
+		if now.Before(budget.Reset) {
+			return budget.Reset.Sub(now)
+		}
+		// the window has been reset since the last response.
+		budget.Remaining = budget.Limit
+	}
+	return 0
+}
+
+func (l *memoryRateLimiter) Update(_ context.Context, key string, rl domain.RateLimit) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	budget := l.budgetLocked(key)
+	if rl.Limit > 0 {
+		// responses of the same window may arrive out of order, keep the lowest count.
+		if !rl.Reset.After(budget.Reset) && budget.Remaining < rl.Remaining {
+			rl.Remaining = budget.Remaining
+		}
+		budget.Limit = rl.Limit
+		budget.Remaining = rl.Remaining
+		budget.Reset = rl.Reset
+	}
+	if rl.BlockedUntil.After(budget.BlockedUntil) {
+		budget.BlockedUntil = rl.BlockedUntil
+	}
+	return nil
+}
+
+func (l *memoryRateLimiter) Budget(_ context.Context, key string) (domain.RateLimit, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return *l.budgetLocked(key), nil
+}
+
+// parseRateLimit reads the rate limit headers of resp. limited reports whether the
+// request was rejected because of a primary or secondary rate limit.
+func parseRateLimit(resp *http.Response) (rl domain.RateLimit, limited bool) {
+	now := time.Now()
+
+	if limit, err := strconv.Atoi(resp.Header.Get("X-RateLimit-Limit")); err == nil {
+		rl.Limit = limit
+	}
+	if remaining, err := strconv.Atoi(resp.Header.Get("X-RateLimit-Remaining")); err == nil {
+		rl.Remaining = remaining
+	}
+	if reset, err := strconv.ParseInt(resp.Header.Get("X-RateLimit-Reset"), 10, 64); err == nil {
+		rl.Reset = time.Unix(reset, 0)
+	}
+
+	if resp.StatusCode != http.StatusForbidden && resp.StatusCode != http.StatusTooManyRequests {
+		return rl, false
+	}
+
+	if retryAfter, err := strconv.Atoi(resp.Header.Get("Retry-After")); err == nil {
+		rl.BlockedUntil = now.Add(time.Duration(retryAfter) * time.Second)
+		return rl, true
+	}
+
+	if rl.Limit > 0 && rl.Remaining == 0 {
+		rl.BlockedUntil = rl.Reset
+		return rl, true
+	}
+
+	if isSecondaryRateLimit(resp) {
+		rl.BlockedUntil = now.Add(secondaryRateLimitWait)
+		return rl, true
+	}
+
+	return rl, false
+}
+
+// isSecondaryRateLimit inspects the body of a 403 for GitHub's secondary rate limit message.
+// The body is restored so callers can still read it.
+func isSecondaryRateLimit(resp *http.Response) bool {
+	body, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+	if err != nil {
+		return false
+	}
+	return strings.Contains(strings.ToLower(string(body)), "secondary rate limit")
+}
+
+func sleepContext(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
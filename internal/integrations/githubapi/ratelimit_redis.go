@@ -0,0 +1,117 @@
+package githubapi
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/babyfaceeasy/lema/internal/domain"
+	"github.com/go-redis/redis/v8"
+)
+
+const redisRateLimitKey = "lema:github:ratelimit"
+
+// acquireScript takes one request from the budget, or returns how many milliseconds to wait.
+var acquireScript = redis.NewScript(`
+local now = tonumber(ARGV[1])
+local blocked = tonumber(redis.call('HGET', KEYS[1], 'blocked_until') or '0')
+if now < blocked then
+	return blocked - now
+end
+local limit = tonumber(redis.call('HGET', KEYS[1], 'limit') or '0')
+if limit > 0 then
+	local reset = tonumber(redis.call('HGET', KEYS[1], 'reset') or '0')
+	local remaining = tonumber(redis.call('HGET', KEYS[1], 'remaining') or '0')
+	if remaining <= 0 then
+		if now < reset then
+			return reset - now
+		end
+		remaining = limit
+	end
+	redis.call('HSET', KEYS[1], 'remaining', remaining - 1)
+end
+return 0
+`)
+
+// updateScript records the state of a response, keeping the lowest remaining count of a window.
+var updateScript = redis.NewScript(`
+local limit = tonumber(ARGV[1])
+if limit > 0 then
+	local remaining = tonumber(ARGV[2])
+	local reset = tonumber(ARGV[3])
+	local currentReset = tonumber(redis.call('HGET', KEYS[1], 'reset') or '0')
+	local currentRemaining = tonumber(redis.call('HGET', KEYS[1], 'remaining') or ARGV[2])
+	if reset <= currentReset and currentRemaining < remaining then
+		remaining = currentRemaining
+	end
+	redis.call('HSET', KEYS[1], 'limit', limit, 'remaining', remaining, 'reset', reset)
+end
+local blocked = tonumber(ARGV[4])
+if blocked > tonumber(redis.call('HGET', KEYS[1], 'blocked_until') or '0') then
+	redis.call('HSET', KEYS[1], 'blocked_until', blocked)
+end
+return 0
+`)
+
+type redisRateLimiter struct {
+	client *redis.Client
+}
+
+// NewRedisRateLimiter returns a RateLimiter whose budget is shared by every process using the same Redis.
+func NewRedisRateLimiter(client *redis.Client) RateLimiter {
+	return &redisRateLimiter{client: client}
+}
+
+func (l *redisRateLimiter) redisKey(key string) string {
+	return redisRateLimitKey + ":" + key
+}
+
+func (l *redisRateLimiter) Acquire(ctx context.Context, key string) error {
+	for {
+		wait, err := acquireScript.Run(ctx, l.client, []string{l.redisKey(key)}, time.Now().UnixMilli()).Int64()
+		if err != nil {
+			return fmt.Errorf("failed to acquire github rate limit: %w", err)
+		}
+		if wait <= 0 {
+			return nil
+		}
+
+		if err := sleepContext(ctx, time.Duration(wait)*time.Millisecond); err != nil {
+			return err
+		}
+	}
+}
+
+func (l *redisRateLimiter) Update(ctx context.Context, key string, rl domain.RateLimit) error {
+	var reset, blocked int64
+	if !rl.Reset.IsZero() {
+		reset = rl.Reset.UnixMilli()
+	}
+	if !rl.BlockedUntil.IsZero() {
+		blocked = rl.BlockedUntil.UnixMilli()
+	}
+
+	if err := updateScript.Run(ctx, l.client, []string{l.redisKey(key)}, rl.Limit, rl.Remaining, reset, blocked).Err(); err != nil {
+		return fmt.Errorf("failed to update github rate limit: %w", err)
+	}
+	return nil
+}
+
+func (l *redisRateLimiter) Budget(ctx context.Context, key string) (domain.RateLimit, error) {
+	values, err := l.client.HGetAll(ctx, l.redisKey(key)).Result()
+	if err != nil {
+		return domain.RateLimit{}, fmt.Errorf("failed to read github rate limit: %w", err)
+	}
+
+	var rl domain.RateLimit
+	rl.Limit, _ = strconv.Atoi(values["limit"])
+	rl.Remaining, _ = strconv.Atoi(values["remaining"])
+	if reset, err := strconv.ParseInt(values["reset"], 10, 64); err == nil && reset > 0 {
+		rl.Reset = time.UnixMilli(reset)
+	}
+	if blocked, err := strconv.ParseInt(values["blocked_until"], 10, 64); err == nil && blocked > 0 {
+		rl.BlockedUntil = time.UnixMilli(blocked)
+	}
+	return rl, nil
+}
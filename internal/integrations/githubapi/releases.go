@@ -0,0 +1,75 @@
+package githubapi
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"time"
+
+	"github.com/bytedance/sonic"
+)
+
+type TagResponse struct {
+	Name   string `json:"name"`
+	Commit struct {
+		SHA string `json:"sha"`
+	} `json:"commit"`
+}
+
+type ReleaseResponse struct {
+	ID              int64           `json:"id"`
+	TagName         string          `json:"tag_name"`
+	Name            string          `json:"name"`
+	TargetCommitish string          `json:"target_commitish"`
+	Draft           bool            `json:"draft"`
+	Prerelease      bool            `json:"prerelease"`
+	HTMLURL         string          `json:"html_url"`
+	Author          RepositoryOwner `json:"author"`
+	CreatedAt       time.Time       `json:"created_at"`
+	PublishedAt     *time.Time      `json:"published_at"`
+}
+
+// GetTags sends every tag of ownerName/repositoryName through tagCh.
+func (c *Client) GetTags(ctx context.Context, repositoryName, ownerName string, pageSize int, tagCh chan<- TagResponse) error {
+	q := url.Values{}
+	q.Set("per_page", fmt.Sprintf("%d", pageSize))
+	endpoint := fmt.Sprintf("%s/%s/%s/tags?%s", c.baseURL, ownerName, repositoryName, q.Encode())
+
+	return c.eachPage(ctx, ownerName, repositoryName, endpoint, func(body []byte) (bool, error) {
+		var tags []TagResponse
+		if err := sonic.Unmarshal(body, &tags); err != nil {
+			return false, err
+		}
+		for _, tag := range tags {
+			select {
+			case <-ctx.Done():
+				return false, ctx.Err()
+			case tagCh <- tag:
+			}
+		}
+		return len(tags) > 0, nil
+	})
+}
+
+// GetReleases sends every release of ownerName/repositoryName, drafts included, through releaseCh,
+// newest first.
+func (c *Client) GetReleases(ctx context.Context, repositoryName, ownerName string, pageSize int, releaseCh chan<- ReleaseResponse) error {
+	q := url.Values{}
+	q.Set("per_page", fmt.Sprintf("%d", pageSize))
+	endpoint := fmt.Sprintf("%s/%s/%s/releases?%s", c.baseURL, ownerName, repositoryName, q.Encode())
+
+	return c.eachPage(ctx, ownerName, repositoryName, endpoint, func(body []byte) (bool, error) {
+		var releases []ReleaseResponse
+		if err := sonic.Unmarshal(body, &releases); err != nil {
+			return false, err
+		}
+		for _, release := range releases {
+			select {
+			case <-ctx.Done():
+				return false, ctx.Err()
+			case releaseCh <- release:
+			}
+		}
+		return len(releases) > 0, nil
+	})
+}
@@ -0,0 +1,111 @@
+package githubapi_test
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/babyfaceeasy/lema/config"
+	"github.com/babyfaceeasy/lema/internal/domain"
+	"github.com/babyfaceeasy/lema/internal/integrations/githubapi"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestGetTagsFollowsPages(t *testing.T) {
+	var server *httptest.Server
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/repos/chromium/chromium/tags", r.URL.Path)
+		if r.URL.Query().Get("page") == "" {
+			w.Header().Set("Link", fmt.Sprintf(`<%s/repos/chromium/chromium/tags?page=2>; rel="next"`, server.URL))
+			w.Write([]byte(`[{"name":"135.0.7049.1","commit":{"sha":"aaa"}}]`))
+			return
+		}
+		w.Write([]byte(`[{"name":"135.0.7049.0","commit":{"sha":"bbb"}}]`))
+	}))
+	defer server.Close()
+
+	client := githubapi.NewClient(
+		server.URL+"/repos",
+		server.Client(),
+		zap.NewNop(),
+		&config.Config{},
+		githubapi.WithRateLimiter(githubapi.NewMemoryRateLimiter()),
+	)
+
+	tagCh := make(chan githubapi.TagResponse, 10)
+	err := client.GetTags(context.Background(), "chromium", "chromium", 100, tagCh)
+	require.NoError(t, err)
+	close(tagCh)
+
+	require.Len(t, tagCh, 2)
+	first, second := <-tagCh, <-tagCh
+	require.Equal(t, "aaa", first.Commit.SHA)
+	require.Equal(t, "135.0.7049.0", second.Name)
+}
+
+func TestGetReleases(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/repos/chromium/chromium/releases", r.URL.Path)
+		w.Write([]byte(`[
+			{"id":2,"tag_name":"v2.0.0-rc1","name":"","draft":false,"prerelease":true,"author":{"login":"alice"},
+			 "created_at":"2025-03-10T00:00:00Z","published_at":"2025-03-11T00:00:00Z"},
+			{"id":1,"tag_name":"v1.0.0","name":"First","draft":true,"prerelease":false,"author":{"login":"bob"},
+			 "created_at":"2025-03-01T00:00:00Z","published_at":null}]`))
+	}))
+	defer server.Close()
+
+	client := githubapi.NewClient(
+		server.URL+"/repos",
+		server.Client(),
+		zap.NewNop(),
+		&config.Config{},
+		githubapi.WithRateLimiter(githubapi.NewMemoryRateLimiter()),
+	)
+
+	releaseCh := make(chan githubapi.ReleaseResponse, 10)
+	err := client.GetReleases(context.Background(), "chromium", "chromium", 100, releaseCh)
+	require.NoError(t, err)
+	close(releaseCh)
+
+	require.Len(t, releaseCh, 2)
+	rc, draft := <-releaseCh, <-releaseCh
+	require.True(t, rc.Prerelease)
+	require.NotNil(t, rc.PublishedAt)
+	require.True(t, draft.Draft)
+	require.Nil(t, draft.PublishedAt)
+}
+
+func TestGetReleasesConditional(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("If-None-Match") == `"v1"` {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		w.Header().Set("ETag", `"v1"`)
+		w.Write([]byte(`[{"id":1,"tag_name":"v1.0.0","created_at":"2025-03-01T00:00:00Z"}]`))
+	}))
+	defer server.Close()
+
+	client := githubapi.NewClient(
+		server.URL+"/repos",
+		server.Client(),
+		zap.NewNop(),
+		&config.Config{},
+		githubapi.WithRateLimiter(githubapi.NewMemoryRateLimiter()),
+		githubapi.WithETagStore(&memoryETagStore{etags: map[string]domain.ETag{}}),
+	)
+
+	ctx := githubapi.WithConditionalRequests(context.Background())
+	releaseCh := make(chan githubapi.ReleaseResponse, 10)
+	require.NoError(t, client.GetReleases(ctx, "chromium", "chromium", 100, releaseCh))
+	require.Len(t, releaseCh, 1)
+	require.NoError(t, githubapi.SaveValidators(ctx))
+
+	err := client.GetReleases(ctx, "chromium", "chromium", 100, releaseCh)
+	require.ErrorIs(t, err, domain.ErrNotModified)
+	require.Len(t, releaseCh, 1)
+}
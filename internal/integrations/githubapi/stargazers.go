@@ -0,0 +1,41 @@
+package githubapi
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"time"
+
+	"github.com/bytedance/sonic"
+)
+
+// starMediaType makes stargazer listings carry when each user starred the repository.
+const starMediaType = "application/vnd.github.star+json"
+
+type StargazerResponse struct {
+	StarredAt time.Time       `json:"starred_at"`
+	User      RepositoryOwner `json:"user"`
+}
+
+// GetStargazers sends the current stargazers of ownerName/repositoryName through stargazerCh,
+// oldest first. GitHub only lists the first 400 pages of stargazers.
+func (c *Client) GetStargazers(ctx context.Context, repositoryName, ownerName string, pageSize int, stargazerCh chan<- StargazerResponse) error {
+	q := url.Values{}
+	q.Set("per_page", fmt.Sprintf("%d", pageSize))
+	endpoint := fmt.Sprintf("%s/%s/%s/stargazers?%s", c.baseURL, ownerName, repositoryName, q.Encode())
+
+	return c.eachPage(withMediaType(ctx, starMediaType), ownerName, repositoryName, endpoint, func(body []byte) (bool, error) {
+		var stargazers []StargazerResponse
+		if err := sonic.Unmarshal(body, &stargazers); err != nil {
+			return false, err
+		}
+		for _, stargazer := range stargazers {
+			select {
+			case <-ctx.Done():
+				return false, ctx.Err()
+			case stargazerCh <- stargazer:
+			}
+		}
+		return len(stargazers) > 0, nil
+	})
+}
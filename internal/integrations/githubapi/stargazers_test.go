@@ -0,0 +1,43 @@
+package githubapi_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/babyfaceeasy/lema/config"
+	"github.com/babyfaceeasy/lema/internal/integrations/githubapi"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestGetStargazersAsksForStarDates(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/repos/chromium/chromium/stargazers", r.URL.Path)
+		assert.Equal(t, "application/vnd.github.star+json", r.Header.Get("Accept"))
+		w.Write([]byte(`[
+			{"starred_at":"2025-03-01T10:00:00Z","user":{"login":"alice"}},
+			{"starred_at":"2025-03-02T11:00:00Z","user":{"login":"bob"}}]`))
+	}))
+	defer server.Close()
+
+	client := githubapi.NewClient(
+		server.URL+"/repos",
+		server.Client(),
+		zap.NewNop(),
+		&config.Config{},
+		githubapi.WithRateLimiter(githubapi.NewMemoryRateLimiter()),
+	)
+
+	stargazerCh := make(chan githubapi.StargazerResponse, 10)
+	err := client.GetStargazers(context.Background(), "chromium", "chromium", 100, stargazerCh)
+	require.NoError(t, err)
+	close(stargazerCh)
+
+	require.Len(t, stargazerCh, 2)
+	first := <-stargazerCh
+	require.Equal(t, "alice", first.User.Login)
+	require.Equal(t, 1, first.StarredAt.Day())
+}
@@ -0,0 +1,205 @@
+package githubapi
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/babyfaceeasy/lema/internal/domain"
+)
+
+// anonymousKey is the rate limit key of requests sent without a token.
+const anonymousKey = "anonymous"
+
+// ErrNoTokenAvailable is returned when every configured token has been retired.
+var ErrNoTokenAvailable = errors.New("no usable github token left in the pool")
+
+type poolToken struct {
+	id          string
+	fingerprint string
+	header      string
+	lastUsedAt  *time.Time
+	retiredAt   *time.Time
+}
+
+// TokenPool holds the GitHub tokens a Client rotates between.
+type TokenPool struct {
+	mu     sync.Mutex
+	tokens []*poolToken
+}
+
+// NewTokenPool builds a pool from raw tokens, ignoring blanks and duplicates. Tokens may be
+// given with their scheme ("Bearer ghp_...", "token ghp_...") or bare.
+func NewTokenPool(tokens []string) *TokenPool {
+	pool := &TokenPool{}
+	seen := make(map[string]bool)
+	for _, token := range tokens {
+		token = strings.TrimSpace(token)
+		if token == "" || seen[token] {
+			continue
+		}
+		seen[token] = true
+
+		header := token
+		if !strings.Contains(token, " ") {
+			header = "Bearer " + token
+		}
+		sum := sha256.Sum256([]byte(token))
+		pool.tokens = append(pool.tokens, &poolToken{
+			id:          fmt.Sprintf("token-%d", len(pool.tokens)+1),
+			fingerprint: hex.EncodeToString(sum[:])[:12],
+			header:      header,
+		})
+	}
+	return pool
+}
+
+// Len returns the number of tokens in the pool, retired ones included.
+func (p *TokenPool) Len() int {
+	return len(p.tokens)
+}
+
+// pick returns the active token with the most remaining quota. Tokens whose quota is unknown
+// are tried first; when every token is exhausted the one that resets first is returned.
+// A nil token means the pool is empty and requests are sent anonymously.
+// Budgets are read before taking the lock, so requests do not wait on each other's lookups.
+func (p *TokenPool) pick(ctx context.Context, limiter RateLimiter) (*poolToken, error) {
+	if len(p.tokens) == 0 {
+		return nil, nil
+	}
+
+	budgets := make([]domain.RateLimit, len(p.tokens))
+	for i, tok := range p.tokens {
+		budget, err := limiter.Budget(ctx, tok.id)
+		if err != nil {
+			return nil, err
+		}
+		budgets[i] = budget
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	now := time.Now()
+	var best, soonest *poolToken
+	bestRemaining := -1
+	var soonestWait time.Duration
+
+	for i, tok := range p.tokens {
+		if tok.retiredAt != nil {
+			continue
+		}
+
+		budget := budgets[i]
+		if wait := waitFor(&budget, now); wait > 0 {
+			if soonest == nil || wait < soonestWait {
+				soonest, soonestWait = tok, wait
+			}
+			continue
+		}
+
+		remaining := math.MaxInt
+		if budget.Limit > 0 {
+			remaining = budget.Remaining
+		}
+		if remaining > bestRemaining {
+			best, bestRemaining = tok, remaining
+		}
+	}
+
+	if best == nil {
+		best = soonest
+	}
+	if best == nil {
+		return nil, ErrNoTokenAvailable
+	}
+
+	best.lastUsedAt = &now
+	return best, nil
+}
+
+// active returns the tokens of the pool that are not retired.
+func (p *TokenPool) active() []*poolToken {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	var tokens []*poolToken
+	for _, tok := range p.tokens {
+		if tok.retiredAt == nil {
+			tokens = append(tokens, tok)
+		}
+	}
+	return tokens
+}
+
+// retired reports whether tok was taken out of rotation.
+func (p *TokenPool) retired(tok *poolToken) bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return tok.retiredAt != nil
+}
+
+type tokenKey struct{}
+
+// withToken makes requests made with ctx use tok instead of the token with the most remaining
+// quota, as long as tok is not retired.
+func withToken(ctx context.Context, tok *poolToken) context.Context {
+	return context.WithValue(ctx, tokenKey{}, tok)
+}
+
+// retire takes tok out of rotation, e.g. after GitHub rejected it with a 401.
+func (p *TokenPool) retire(tok *poolToken) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if tok.retiredAt == nil {
+		now := time.Now()
+		tok.retiredAt = &now
+	}
+}
+
+// health reports the state of every token in the pool.
+func (p *TokenPool) health(ctx context.Context, limiter RateLimiter) ([]domain.TokenHealth, error) {
+	budgets := make([]domain.RateLimit, len(p.tokens))
+	for i, tok := range p.tokens {
+		budget, err := limiter.Budget(ctx, tok.id)
+		if err != nil {
+			return nil, err
+		}
+		budgets[i] = budget
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	now := time.Now()
+	health := make([]domain.TokenHealth, 0, len(p.tokens))
+	for i, tok := range p.tokens {
+		budget := budgets[i]
+
+		status := domain.TokenStatusActive
+		if tok.retiredAt != nil {
+			status = domain.TokenStatusRetired
+		} else if waitFor(&budget, now) > 0 {
+			status = domain.TokenStatusExhausted
+		}
+
+		health = append(health, domain.TokenHealth{
+			ID:          tok.id,
+			Fingerprint: tok.fingerprint,
+			Status:      status,
+			Limit:       budget.Limit,
+			Remaining:   budget.Remaining,
+			Reset:       budget.Reset,
+			LastUsedAt:  tok.lastUsedAt,
+			RetiredAt:   tok.retiredAt,
+		})
+	}
+	return health, nil
+}
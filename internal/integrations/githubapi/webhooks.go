@@ -0,0 +1,148 @@
+package githubapi
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/babyfaceeasy/lema/pkg/botdetect"
+)
+
+// MaxPushCommits is the most commits GitHub lists in a push event, longer pushes being truncated.
+const MaxPushCommits = 2048
+
+// zeroSHA is the before or after SHA of a push creating or deleting a ref.
+const zeroSHA = "0000000000000000000000000000000000000000"
+
+var (
+	ErrMissingSignature = errors.New("missing webhook signature")
+	ErrInvalidSignature = errors.New("invalid webhook signature")
+)
+
+// VerifyWebhookSignature checks the X-Hub-Signature-256 header of a webhook delivery against the
+// HMAC-SHA256 of its body, keyed with the webhook secret.
+func VerifyWebhookSignature(secret string, body []byte, signature string) error {
+	if signature == "" {
+		return ErrMissingSignature
+	}
+
+	got, err := hex.DecodeString(strings.TrimPrefix(signature, "sha256="))
+	if err != nil || !strings.HasPrefix(signature, "sha256=") {
+		return ErrInvalidSignature
+	}
+
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	if !hmac.Equal(got, mac.Sum(nil)) {
+		return ErrInvalidSignature
+	}
+	return nil
+}
+
+type WebhookRepository struct {
+	ID            int64           `json:"id"`
+	Name          string          `json:"name"`
+	FullName      string          `json:"full_name"`
+	Owner         RepositoryOwner `json:"owner"`
+	URL           string          `json:"url"`
+	CommitsURL    string          `json:"commits_url"`
+	DefaultBranch string          `json:"default_branch"`
+}
+
+// CommitURL returns the API URL of the commit sha, as stored for commits fetched from the API.
+func (r WebhookRepository) CommitURL(sha string) string {
+	return strings.Replace(r.CommitsURL, "{/sha}", "/"+sha, 1)
+}
+
+type PushCommitAuthor struct {
+	Name     string `json:"name"`
+	Email    string `json:"email"`
+	Username string `json:"username"`
+}
+
+// IsBot reports whether the author is a GitHub App, push events not carrying account types.
+func (a PushCommitAuthor) IsBot() bool {
+	return botdetect.IsGithubApp(a.Username)
+}
+
+type PushCommit struct {
+	ID        string           `json:"id"`
+	Message   string           `json:"message"`
+	Timestamp time.Time        `json:"timestamp"`
+	URL       string           `json:"url"`
+	Author    PushCommitAuthor `json:"author"`
+	Committer PushCommitAuthor `json:"committer"`
+	Distinct  bool             `json:"distinct"`
+}
+
+type PushEvent struct {
+	Ref        string            `json:"ref"`
+	Before     string            `json:"before"`
+	After      string            `json:"after"`
+	Created    bool              `json:"created"`
+	Deleted    bool              `json:"deleted"`
+	Forced     bool              `json:"forced"`
+	Commits    []PushCommit      `json:"commits"`
+	Repository WebhookRepository `json:"repository"`
+}
+
+// Branch returns the branch pushed to, or "" when a tag was pushed.
+func (e PushEvent) Branch() string {
+	branch, ok := strings.CutPrefix(e.Ref, "refs/heads/")
+	if !ok {
+		return ""
+	}
+	return branch
+}
+
+// Truncated reports whether the commits of the push may not all be listed in the event.
+func (e PushEvent) Truncated() bool {
+	return len(e.Commits) >= MaxPushCommits
+}
+
+// RefDeleted reports whether the push deleted its ref.
+func (e PushEvent) RefDeleted() bool {
+	return e.Deleted || e.After == zeroSHA
+}
+
+type webhookLogin struct {
+	Login string `json:"login"`
+}
+
+type RepositoryEventChanges struct {
+	Repository struct {
+		Name struct {
+			From string `json:"from"`
+		} `json:"name"`
+	} `json:"repository"`
+	Owner struct {
+		From struct {
+			User         *webhookLogin `json:"user"`
+			Organization *webhookLogin `json:"organization"`
+		} `json:"from"`
+	} `json:"owner"`
+}
+
+type RepositoryEvent struct {
+	Action     string                 `json:"action"`
+	Changes    RepositoryEventChanges `json:"changes"`
+	Repository WebhookRepository      `json:"repository"`
+}
+
+// PreviousFullName returns the owner and name of the repository before it was renamed or transferred.
+func (e RepositoryEvent) PreviousFullName() (owner, name string) {
+	owner, name = e.Repository.Owner.Login, e.Repository.Name
+	if from := e.Changes.Repository.Name.From; from != "" {
+		name = from
+	}
+	if from := e.Changes.Owner.From.User; from != nil && from.Login != "" {
+		owner = from.Login
+	}
+	if from := e.Changes.Owner.From.Organization; from != nil && from.Login != "" {
+		owner = from.Login
+	}
+	return owner, name
+}
@@ -0,0 +1,114 @@
+package githubapi_test
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"testing"
+
+	"github.com/babyfaceeasy/lema/internal/integrations/githubapi"
+	"github.com/bytedance/sonic"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestVerifyWebhookSignature(t *testing.T) {
+	body := []byte(`{"zen":"Design for failure."}`)
+	mac := hmac.New(sha256.New, []byte("s3cret"))
+	mac.Write(body)
+	signature := "sha256=" + hex.EncodeToString(mac.Sum(nil))
+
+	assert.NoError(t, githubapi.VerifyWebhookSignature("s3cret", body, signature))
+	assert.ErrorIs(t, githubapi.VerifyWebhookSignature("other", body, signature), githubapi.ErrInvalidSignature)
+	assert.ErrorIs(t, githubapi.VerifyWebhookSignature("s3cret", []byte(`{}`), signature), githubapi.ErrInvalidSignature)
+	assert.ErrorIs(t, githubapi.VerifyWebhookSignature("s3cret", body, "sha1="+hex.EncodeToString(mac.Sum(nil))), githubapi.ErrInvalidSignature)
+	assert.ErrorIs(t, githubapi.VerifyWebhookSignature("s3cret", body, "sha256=zz"), githubapi.ErrInvalidSignature)
+	assert.ErrorIs(t, githubapi.VerifyWebhookSignature("s3cret", body, ""), githubapi.ErrMissingSignature)
+}
+
+func TestPushEvent(t *testing.T) {
+	payload := `{
+		"ref": "refs/heads/main",
+		"before": "6113728f27ae82c7b1a177c8d03f9e96e0adf246",
+		"after": "0d1a26e67d8f5eaf1f6ba5c57fc3c7d91ac0fd1c",
+		"commits": [{
+			"id": "0d1a26e67d8f5eaf1f6ba5c57fc3c7d91ac0fd1c",
+			"message": "Update README.md",
+			"timestamp": "2025-03-31T10:15:04+02:00",
+			"author": {"name": "Ada", "email": "ada@example.com", "username": "ada"},
+			"committer": {"name": "GitHub", "email": "noreply@github.com", "username": "web-flow"}
+		}],
+		"repository": {
+			"id": 35129377,
+			"name": "public-repo",
+			"owner": {"login": "baxterthehacker"},
+			"commits_url": "https://api.github.com/repos/baxterthehacker/public-repo/commits{/sha}"
+		}
+	}`
+
+	var event githubapi.PushEvent
+	require.NoError(t, sonic.Unmarshal([]byte(payload), &event))
+
+	assert.Equal(t, "main", event.Branch())
+	assert.False(t, event.RefDeleted())
+	assert.False(t, event.Truncated())
+	require.Len(t, event.Commits, 1)
+	assert.Equal(t, "Ada", event.Commits[0].Author.Name)
+	assert.False(t, event.Commits[0].Author.IsBot())
+	assert.True(t, githubapi.PushCommitAuthor{Username: "dependabot[bot]"}.IsBot())
+	assert.Equal(t, "https://api.github.com/repos/baxterthehacker/public-repo/commits/0d1a26e67d8f5eaf1f6ba5c57fc3c7d91ac0fd1c", event.Repository.CommitURL(event.Commits[0].ID))
+
+	tag := githubapi.PushEvent{Ref: "refs/tags/v1.0.0"}
+	assert.Empty(t, tag.Branch())
+
+	deleted := githubapi.PushEvent{Ref: "refs/heads/feature", After: "0000000000000000000000000000000000000000"}
+	assert.True(t, deleted.RefDeleted())
+
+	truncated := githubapi.PushEvent{Commits: make([]githubapi.PushCommit, githubapi.MaxPushCommits)}
+	assert.True(t, truncated.Truncated())
+}
+
+func TestRepositoryEventPreviousFullName(t *testing.T) {
+	tests := []struct {
+		name      string
+		payload   string
+		wantOwner string
+		wantName  string
+	}{
+		{
+			name:      "renamed",
+			payload:   `{"action":"renamed","changes":{"repository":{"name":{"from":"old-name"}}},"repository":{"name":"new-name","owner":{"login":"octo"}}}`,
+			wantOwner: "octo",
+			wantName:  "old-name",
+		},
+		{
+			name:      "transferred from a user",
+			payload:   `{"action":"transferred","changes":{"owner":{"from":{"user":{"login":"octocat"}}}},"repository":{"name":"repo","owner":{"login":"octo-org"}}}`,
+			wantOwner: "octocat",
+			wantName:  "repo",
+		},
+		{
+			name:      "transferred from an organization",
+			payload:   `{"action":"transferred","changes":{"owner":{"from":{"organization":{"login":"old-org"}}}},"repository":{"name":"repo","owner":{"login":"octo-org"}}}`,
+			wantOwner: "old-org",
+			wantName:  "repo",
+		},
+		{
+			name:      "archived",
+			payload:   `{"action":"archived","repository":{"name":"repo","owner":{"login":"octo-org"}}}`,
+			wantOwner: "octo-org",
+			wantName:  "repo",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var event githubapi.RepositoryEvent
+			require.NoError(t, sonic.Unmarshal([]byte(tt.payload), &event))
+
+			owner, name := event.PreviousFullName()
+			assert.Equal(t, tt.wantOwner, owner)
+			assert.Equal(t, tt.wantName, name)
+		})
+	}
+}
@@ -0,0 +1,214 @@
+package gitlabapi
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/babyfaceeasy/lema/internal/domain"
+	"github.com/babyfaceeasy/lema/internal/integrations/httpretry"
+	"github.com/bytedance/sonic"
+	"go.uber.org/zap"
+)
+
+type HttpClient interface {
+	Do(*http.Request) (*http.Response, error)
+}
+
+// Client talks to the REST v4 API of gitlab.com or of a self-managed GitLab instance.
+type Client struct {
+	baseURL string
+	token   string
+	http    *httpretry.Client
+	logger  *zap.Logger
+
+	// rate limiting, as last reported by the RateLimit-* headers
+	mu        sync.Mutex
+	rateLimit domain.RateLimit
+}
+
+type GitlabError struct {
+	Message any `json:"message"`
+}
+
+// NewClient creates a client for the API rooted at baseURL, e.g. https://gitlab.com/api/v4,
+// authenticating with a personal, project or group access token when token is not empty.
+func NewClient(baseURL, token string, httpClient HttpClient, logger *zap.Logger) *Client {
+	c := &Client{
+		baseURL: strings.TrimSuffix(baseURL, "/"),
+		token:   token,
+		logger:  logger.With(zap.String("package", "gitlabapi")),
+	}
+	c.http = &httpretry.Client{
+		HttpClient: httpClient,
+		Logger:     c.logger,
+		Before:     c.authorize,
+		After:      func(resp *http.Response) { c.recordRateLimit(resp.Header) },
+		RetryAfter: func(h http.Header) time.Duration { return retryAfter(h, time.Now()) },
+	}
+	return c
+}
+
+// RateLimit returns the request budget GitLab last reported.
+func (c *Client) RateLimit() domain.RateLimit {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.rateLimit
+}
+
+// projectPath returns the URL encoded ID of the project at namespace/name, as GitLab accepts in
+// place of its numeric ID. Namespaces can be nested groups.
+func projectPath(namespace, name string) string {
+	return url.PathEscape(namespace + "/" + name)
+}
+
+// authorize waits for the budget to reset once GitLab reports it exhausted, then sets the access
+// token on req. Requests answered with 429 are retried after the delay GitLab asks for.
+func (c *Client) authorize(req *http.Request) error {
+	if err := c.waitForBudget(req.Context()); err != nil {
+		return err
+	}
+	if c.token != "" {
+		req.Header.Set("PRIVATE-TOKEN", c.token)
+	}
+	return nil
+}
+
+// waitForBudget blocks until the rate limit window resets when no request is left in it.
+func (c *Client) waitForBudget(ctx context.Context) error {
+	budget := c.RateLimit()
+	if budget.Limit == 0 || budget.Remaining > 0 {
+		return nil
+	}
+	wait := time.Until(budget.Reset)
+	if wait <= 0 {
+		return nil
+	}
+
+	c.logger.Info("gitlab budget exhausted, waiting for reset", zap.Time("reset", budget.Reset))
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-time.After(wait):
+		return nil
+	}
+}
+
+// recordRateLimit keeps the budget reported by the RateLimit-* response headers, which only
+// rate limited endpoints send.
+func (c *Client) recordRateLimit(h http.Header) {
+	limit, err := strconv.Atoi(h.Get("RateLimit-Limit"))
+	if err != nil {
+		return
+	}
+	remaining, _ := strconv.Atoi(h.Get("RateLimit-Remaining"))
+	reset, _ := strconv.ParseInt(h.Get("RateLimit-Reset"), 10, 64)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.rateLimit = domain.RateLimit{Limit: limit, Remaining: remaining, Reset: time.Unix(reset, 0)}
+}
+
+// retryAfter returns how long to wait before retrying a rate limited request, from Retry-After
+// or else RateLimit-Reset.
+func retryAfter(h http.Header, now time.Time) time.Duration {
+	if seconds, err := strconv.Atoi(h.Get("Retry-After")); err == nil && seconds >= 0 {
+		return time.Duration(seconds) * time.Second
+	}
+	if reset, err := strconv.ParseInt(h.Get("RateLimit-Reset"), 10, 64); err == nil {
+		if wait := time.Unix(reset, 0).Sub(now); wait > 0 {
+			return wait
+		}
+	}
+	return httpretry.DefaultRetryAfter
+}
+
+// get requests endpoint and returns the body of a 200 response.
+func (c *Client) get(ctx context.Context, endpoint string) ([]byte, http.Header, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to read response: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		var glErr GitlabError
+		_ = sonic.Unmarshal(body, &glErr)
+		return nil, nil, fmt.Errorf("GitLab API error %d: %v", resp.StatusCode, glErr.Message)
+	}
+	return body, resp.Header, nil
+}
+
+// eachPage requests endpoint and hands every page body to visit until it returns false or no page
+// is left. Endpoints asked for keyset pagination return the cursor of the next page in their
+// rel="next" link. Endpoints that do not support it fall back to offset pagination, whose next
+// page is read from X-Next-Page when no link is sent.
+func (c *Client) eachPage(ctx context.Context, endpoint string, visit func(body []byte) (bool, error)) error {
+	for page := 1; endpoint != ""; page++ {
+		body, header, err := c.get(ctx, endpoint)
+		if err != nil {
+			return fmt.Errorf("failed to get page %d: %w", page, err)
+		}
+
+		more, err := visit(body)
+		if err != nil {
+			return fmt.Errorf("failed to process page %d: %w", page, err)
+		}
+		if !more {
+			return nil
+		}
+		endpoint = nextPage(endpoint, header)
+	}
+	return nil
+}
+
+// nextPage returns the URL of the page following endpoint, or "" when it was the last one.
+func nextPage(endpoint string, header http.Header) string {
+	if next := parseNextLink(header.Get("Link")); next != "" {
+		return next
+	}
+
+	nextPage := header.Get("X-Next-Page")
+	if nextPage == "" {
+		return ""
+	}
+	u, err := url.Parse(endpoint)
+	if err != nil {
+		return ""
+	}
+	q := u.Query()
+	q.Set("page", nextPage)
+	u.RawQuery = q.Encode()
+	return u.String()
+}
+
+func parseNextLink(linkHeader string) string {
+	for _, part := range strings.Split(linkHeader, ",") {
+		sections := strings.Split(part, ";")
+		if len(sections) < 2 {
+			continue
+		}
+		urlPart := strings.TrimSpace(sections[0])
+		for _, param := range sections[1:] {
+			if strings.TrimSpace(param) == `rel="next"` && len(urlPart) >= 2 && urlPart[0] == '<' && urlPart[len(urlPart)-1] == '>' {
+				return urlPart[1 : len(urlPart)-1]
+			}
+		}
+	}
+	return ""
+}
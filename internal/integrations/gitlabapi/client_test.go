@@ -0,0 +1,77 @@
+package gitlabapi_test
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/babyfaceeasy/lema/internal/domain"
+	"github.com/babyfaceeasy/lema/internal/integrations/gitlabapi"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestGetProject(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/api/v4/projects/gitlab-org%2Fcli", r.URL.EscapedPath())
+		assert.Equal(t, "secret", r.Header.Get("PRIVATE-TOKEN"))
+		w.Write([]byte(`{"id":34675721,"path":"cli","description":"GitLab CLI","web_url":"https://gitlab.com/gitlab-org/cli",
+			"default_branch":"main","star_count":10,"forks_count":2,"open_issues_count":3,"namespace":{"full_path":"gitlab-org"}}`))
+	}))
+	defer server.Close()
+
+	client := gitlabapi.NewClient(server.URL+"/api/v4", "secret", server.Client(), zap.NewNop())
+
+	project, err := client.GetProject(context.Background(), "cli", "gitlab-org")
+	require.NoError(t, err)
+	require.Equal(t, "gitlab-org", project.Namespace.FullPath)
+	require.Equal(t, "main", project.DefaultBranch)
+}
+
+func TestGetCommitsFollowsNextLinks(t *testing.T) {
+	var server *httptest.Server
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/api/v4/projects/group%2Fsub%2Fproject/repository/commits", r.URL.EscapedPath())
+		w.Header().Set("RateLimit-Limit", "2000")
+		w.Header().Set("RateLimit-Remaining", "1999")
+		w.Header().Set("RateLimit-Reset", "1742000000")
+
+		if r.URL.Query().Get("id_after") == "" {
+			assert.Equal(t, "keyset", r.URL.Query().Get("pagination"))
+			assert.Equal(t, "desc", r.URL.Query().Get("sort"))
+			assert.Equal(t, "true", r.URL.Query().Get("with_stats"))
+			assert.Equal(t, "stable", r.URL.Query().Get("ref_name"))
+			assert.Equal(t, "2025-03-01T00:00:00Z", r.URL.Query().Get("since"))
+			w.Header().Set("Link", fmt.Sprintf(`<%s/api/v4/projects/group%%2Fsub%%2Fproject/repository/commits?id_after=bbb&pagination=keyset>; rel="next"`, server.URL))
+			w.Write([]byte(`[{"id":"bbb","message":"second","author_name":"alice","authored_date":"2025-03-02T00:00:00Z",
+				"committed_date":"2025-03-02T00:00:00Z","parent_ids":["aaa"],"stats":{"additions":3,"deletions":1,"total":4}}]`))
+			return
+		}
+		w.Write([]byte(`[{"id":"aaa","message":"first","author_name":"bob","authored_date":"2025-03-01T00:00:00Z",
+			"committed_date":"2025-03-01T00:00:00Z","parent_ids":[]}]`))
+	}))
+	defer server.Close()
+
+	client := gitlabapi.NewClient(server.URL+"/api/v4", "", server.Client(), zap.NewNop())
+
+	ctx := domain.WithBranch(context.Background(), "refs/heads/stable")
+	since := time.Date(2025, time.March, 1, 0, 0, 0, 0, time.UTC)
+	commitCh := make(chan gitlabapi.CommitResponse, 10)
+	err := client.GetCommits(ctx, "project", "group/sub", &since, nil, 100, commitCh)
+	require.NoError(t, err)
+	close(commitCh)
+
+	require.Len(t, commitCh, 2)
+	second, first := <-commitCh, <-commitCh
+	require.Equal(t, 3, second.Stats.Additions)
+	require.Equal(t, "aaa", first.ID)
+	require.Nil(t, first.Stats)
+
+	budget := client.RateLimit()
+	require.Equal(t, 2000, budget.Limit)
+	require.Equal(t, 1999, budget.Remaining)
+}
@@ -0,0 +1,96 @@
+package gitlabapi
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/babyfaceeasy/lema/internal/domain"
+	"github.com/bytedance/sonic"
+)
+
+type ProjectResponse struct {
+	ID              int    `json:"id"`
+	Path            string `json:"path"`
+	Description     string `json:"description"`
+	WebURL          string `json:"web_url"`
+	DefaultBranch   string `json:"default_branch"`
+	StarCount       int    `json:"star_count"`
+	ForksCount      int    `json:"forks_count"`
+	OpenIssuesCount int    `json:"open_issues_count"`
+	Namespace       struct {
+		FullPath string `json:"full_path"`
+	} `json:"namespace"`
+}
+
+type CommitStats struct {
+	Additions int `json:"additions"`
+	Deletions int `json:"deletions"`
+}
+
+type CommitResponse struct {
+	ID             string       `json:"id"`
+	Message        string       `json:"message"`
+	WebURL         string       `json:"web_url"`
+	AuthorName     string       `json:"author_name"`
+	AuthorEmail    string       `json:"author_email"`
+	AuthoredDate   time.Time    `json:"authored_date"`
+	CommitterName  string       `json:"committer_name"`
+	CommitterEmail string       `json:"committer_email"`
+	CommittedDate  time.Time    `json:"committed_date"`
+	ParentIDs      []string     `json:"parent_ids"`
+	Stats          *CommitStats `json:"stats"`
+}
+
+// GetProject returns the project at namespace/name.
+func (c *Client) GetProject(ctx context.Context, repositoryName, namespace string) (*ProjectResponse, error) {
+	body, _, err := c.get(ctx, fmt.Sprintf("%s/projects/%s", c.baseURL, projectPath(namespace, repositoryName)))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get project %s/%s: %w", namespace, repositoryName, err)
+	}
+
+	var project ProjectResponse
+	if err := sonic.Unmarshal(body, &project); err != nil {
+		return nil, fmt.Errorf("failed to decode project %s/%s: %w", namespace, repositoryName, err)
+	}
+	return &project, nil
+}
+
+// GetCommits sends the commits of namespace/name committed between since and until, newest first,
+// through commitCh with their line statistics. The branch set with domain.WithBranch is walked
+// instead of the default branch.
+func (c *Client) GetCommits(ctx context.Context, repositoryName, namespace string, since, until *time.Time, pageSize int, commitCh chan<- CommitResponse) error {
+	q := url.Values{}
+	q.Set("pagination", "keyset")
+	q.Set("order_by", "created_at")
+	q.Set("sort", "desc")
+	q.Set("per_page", fmt.Sprintf("%d", pageSize))
+	q.Set("with_stats", "true")
+	if since != nil && !since.IsZero() {
+		q.Set("since", since.UTC().Format(time.RFC3339))
+	}
+	if until != nil && !until.IsZero() {
+		q.Set("until", until.UTC().Format(time.RFC3339))
+	}
+	if branch := domain.BranchFromContext(ctx); branch != "" {
+		q.Set("ref_name", strings.TrimPrefix(branch, "refs/heads/"))
+	}
+	endpoint := fmt.Sprintf("%s/projects/%s/repository/commits?%s", c.baseURL, projectPath(namespace, repositoryName), q.Encode())
+
+	return c.eachPage(ctx, endpoint, func(body []byte) (bool, error) {
+		var commits []CommitResponse
+		if err := sonic.Unmarshal(body, &commits); err != nil {
+			return false, err
+		}
+		for _, commit := range commits {
+			select {
+			case <-ctx.Done():
+				return false, ctx.Err()
+			case commitCh <- commit:
+			}
+		}
+		return len(commits) > 0, nil
+	})
+}
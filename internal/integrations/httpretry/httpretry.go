@@ -0,0 +1,85 @@
+// Package httpretry sends requests to the REST APIs of code hosting providers, retrying those
+// answered with 429 Too Many Requests.
+package httpretry
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+type HttpClient interface {
+	Do(*http.Request) (*http.Response, error)
+}
+
+// MaxRetries bounds how often a request answered with 429 Too Many Requests is retried.
+const MaxRetries = 3
+
+// DefaultRetryAfter is how long a rate limited request waits when the provider does not say.
+const DefaultRetryAfter = time.Minute
+
+// Client sends requests through HttpClient, retrying rate limited ones up to MaxRetries times.
+type Client struct {
+	HttpClient HttpClient
+	Logger     *zap.Logger
+
+	// Before is called before every attempt, to authenticate the request or to wait for the
+	// provider's budget. An error stops the request.
+	Before func(req *http.Request) error
+	// After is called with every response, e.g. to record the budget its headers report.
+	After func(resp *http.Response)
+	// RetryAfter returns how long to wait before retrying a rate limited request. The package's
+	// RetryAfter is used when nil.
+	RetryAfter func(h http.Header) time.Duration
+}
+
+// Do sends req and returns the first response that is not a 429, or the last one once MaxRetries
+// retries were made.
+func (c *Client) Do(req *http.Request) (*http.Response, error) {
+	ctx := req.Context()
+	for attempt := 0; ; attempt++ {
+		if c.Before != nil {
+			if err := c.Before(req); err != nil {
+				return nil, err
+			}
+		}
+		resp, err := c.HttpClient.Do(req)
+		if err != nil {
+			return nil, err
+		}
+		if c.After != nil {
+			c.After(resp)
+		}
+
+		if resp.StatusCode != http.StatusTooManyRequests || attempt == MaxRetries {
+			return resp, nil
+		}
+		resp.Body.Close()
+
+		wait := c.retryAfter(resp.Header)
+		c.Logger.Warn("rate limit hit, retrying", zap.String("url", req.URL.String()), zap.Duration("wait", wait))
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(wait):
+		}
+	}
+}
+
+func (c *Client) retryAfter(h http.Header) time.Duration {
+	if c.RetryAfter != nil {
+		return c.RetryAfter(h)
+	}
+	return RetryAfter(h)
+}
+
+// RetryAfter returns the delay asked for by the Retry-After header in seconds, or
+// DefaultRetryAfter when there is none.
+func RetryAfter(h http.Header) time.Duration {
+	if seconds, err := strconv.Atoi(h.Get("Retry-After")); err == nil && seconds >= 0 {
+		return time.Duration(seconds) * time.Second
+	}
+	return DefaultRetryAfter
+}
@@ -0,0 +1,78 @@
+package httpretry_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/babyfaceeasy/lema/internal/integrations/httpretry"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestDoRetriesRateLimitedRequests(t *testing.T) {
+	attempts := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts++
+		require.Equal(t, "token secret", r.Header.Get("Authorization"))
+		if attempts == 1 {
+			w.Header().Set("Retry-After", "0")
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		w.Write([]byte(`{}`))
+	}))
+	defer server.Close()
+
+	responses := 0
+	client := &httpretry.Client{
+		HttpClient: server.Client(),
+		Logger:     zap.NewNop(),
+		Before: func(req *http.Request) error {
+			req.Header.Set("Authorization", "token secret")
+			return nil
+		},
+		After: func(resp *http.Response) { responses++ },
+	}
+
+	req, err := http.NewRequestWithContext(context.Background(), http.MethodGet, server.URL, nil)
+	require.NoError(t, err)
+	resp, err := client.Do(req)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	require.Equal(t, 2, attempts)
+	require.Equal(t, 2, responses)
+}
+
+func TestDoGivesUpAfterMaxRetries(t *testing.T) {
+	attempts := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts++
+		w.WriteHeader(http.StatusTooManyRequests)
+	}))
+	defer server.Close()
+
+	client := &httpretry.Client{
+		HttpClient: server.Client(),
+		Logger:     zap.NewNop(),
+		RetryAfter: func(h http.Header) time.Duration { return 0 },
+	}
+
+	req, err := http.NewRequestWithContext(context.Background(), http.MethodGet, server.URL, nil)
+	require.NoError(t, err)
+	resp, err := client.Do(req)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+
+	require.Equal(t, http.StatusTooManyRequests, resp.StatusCode)
+	require.Equal(t, httpretry.MaxRetries+1, attempts)
+}
+
+func TestRetryAfter(t *testing.T) {
+	require.Equal(t, 30*time.Second, httpretry.RetryAfter(http.Header{"Retry-After": []string{"30"}}))
+	require.Equal(t, httpretry.DefaultRetryAfter, httpretry.RetryAfter(http.Header{}))
+}
@@ -0,0 +1,255 @@
+package localgit
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// Client reads repositories from clones and bare mirrors kept under a root directory, running the
+// git command line. The repository owner/name lives at {root}/owner/name or {root}/owner/name.git.
+type Client struct {
+	root   string
+	logger *zap.Logger
+}
+
+// ErrRepositoryNotFound is returned when no repository exists at the path of owner/name.
+var ErrRepositoryNotFound = errors.New("local repository not found")
+
+// ErrInvalidRevision is returned for revisions git would read as an option.
+var ErrInvalidRevision = errors.New("invalid revision")
+
+// NewClient creates a client for the repositories under root.
+func NewClient(root string, logger *zap.Logger) *Client {
+	return &Client{
+		root:   filepath.Clean(root),
+		logger: logger.With(zap.String("package", "localgit")),
+	}
+}
+
+type RepositoryResponse struct {
+	Path          string
+	Description   string
+	DefaultBranch string
+}
+
+type Person struct {
+	Name  string
+	Email string
+	Date  time.Time
+}
+
+type CommitResponse struct {
+	SHA          string
+	Parents      []string
+	Author       Person
+	Committer    Person
+	Message      string
+	Additions    int
+	Deletions    int
+	ChangedFiles int
+}
+
+// RepositoryPath returns the directory of owner/name, refusing names that would leave the root.
+func (c *Client) RepositoryPath(repositoryName, owner string) (string, error) {
+	for _, segment := range []string{owner, repositoryName} {
+		if segment == "" || segment == "." || segment == ".." || strings.ContainsAny(segment, `/\`) {
+			return "", fmt.Errorf("invalid local repository %s/%s", owner, repositoryName)
+		}
+	}
+
+	base := filepath.Join(c.root, owner, repositoryName)
+	for _, path := range []string{base + ".git", base} {
+		if info, err := os.Stat(path); err == nil && info.IsDir() {
+			return path, nil
+		}
+	}
+	return "", fmt.Errorf("%w: %s/%s", ErrRepositoryNotFound, owner, repositoryName)
+}
+
+// git runs git in the repository at path and returns its standard output.
+func (c *Client) git(ctx context.Context, path string, args ...string) ([]byte, error) {
+	cmd := exec.CommandContext(ctx, "git", append([]string{"-C", path}, args...)...)
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("git %s: %w: %s", args[0], err, strings.TrimSpace(stderr.String()))
+	}
+	return out, nil
+}
+
+// GetRepository returns the repository owner/name with the branch its HEAD points to. The
+// description of bare repositories is read from their description file.
+func (c *Client) GetRepository(ctx context.Context, repositoryName, owner string) (*RepositoryResponse, error) {
+	path, err := c.RepositoryPath(repositoryName, owner)
+	if err != nil {
+		return nil, err
+	}
+
+	head, err := c.git(ctx, path, "symbolic-ref", "--short", "HEAD")
+	if err != nil {
+		return nil, fmt.Errorf("failed to read HEAD of %s/%s: %w", owner, repositoryName, err)
+	}
+
+	repo := &RepositoryResponse{Path: path, DefaultBranch: strings.TrimSpace(string(head))}
+	gitDir, err := c.git(ctx, path, "rev-parse", "--absolute-git-dir")
+	if err == nil {
+		description, _ := os.ReadFile(filepath.Join(strings.TrimSpace(string(gitDir)), "description"))
+		// git init writes a placeholder description.
+		if d := strings.TrimSpace(string(description)); !strings.HasPrefix(d, "Unnamed repository") {
+			repo.Description = d
+		}
+	}
+	return repo, nil
+}
+
+// Fields of a commit in the output of git log, separated by unitSep. Records start with recordSep
+// and end with the statistics printed by --numstat.
+const (
+	recordSep = "\x1e"
+	unitSep   = "\x1f"
+	logFormat = recordSep + "%H" + unitSep + "%P" + unitSep + "%an" + unitSep + "%ae" + unitSep + "%aI" +
+		unitSep + "%cn" + unitSep + "%ce" + unitSep + "%cI" + unitSep + "%B" + unitSep
+)
+
+// GetCommits sends the commits of owner/name reachable from rev, or HEAD when rev is empty,
+// through commitCh, newest first. When syncedSHA names a commit of the repository only the
+// commits not reachable from it are sent, otherwise since and until bound the committer dates.
+// Revisions starting with a dash are refused, and the revision follows --end-of-options, so a
+// branch name cannot pass options to git.
+func (c *Client) GetCommits(ctx context.Context, repositoryName, owner, rev, syncedSHA string, since, until *time.Time, commitCh chan<- CommitResponse) error {
+	path, err := c.RepositoryPath(repositoryName, owner)
+	if err != nil {
+		return err
+	}
+	if rev == "" {
+		rev = "HEAD"
+	}
+	if strings.HasPrefix(rev, "-") {
+		return fmt.Errorf("%w: %q", ErrInvalidRevision, rev)
+	}
+
+	args := []string{"log", "--numstat", "--no-renames", "--format=" + logFormat}
+	if syncedSHA != "" && c.hasCommit(ctx, path, syncedSHA) {
+		args = append(args, "--end-of-options", syncedSHA+".."+rev)
+	} else {
+		if syncedSHA != "" {
+			c.logger.Warn("synced commit missing from local repository, falling back to dates", zap.String("path", path), zap.String("sha", syncedSHA))
+		}
+		if since != nil && !since.IsZero() {
+			args = append(args, "--since="+since.UTC().Format(time.RFC3339))
+		}
+		if until != nil && !until.IsZero() {
+			args = append(args, "--until="+until.UTC().Format(time.RFC3339))
+		}
+		args = append(args, "--end-of-options", rev)
+	}
+	args = append(args, "--")
+
+	cmd := exec.CommandContext(ctx, "git", append([]string{"-C", path}, args...)...)
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return fmt.Errorf("failed to run git log: %w", err)
+	}
+	if err := cmd.Start(); err != nil {
+		return fmt.Errorf("failed to run git log: %w", err)
+	}
+
+	if err := readLog(ctx, stdout, commitCh); err != nil {
+		_ = cmd.Process.Kill()
+		_ = cmd.Wait()
+		return err
+	}
+	if err := cmd.Wait(); err != nil {
+		return fmt.Errorf("git log: %w: %s", err, strings.TrimSpace(stderr.String()))
+	}
+	return nil
+}
+
+// hasCommit reports whether sha names a commit of the repository at path.
+func (c *Client) hasCommit(ctx context.Context, path, sha string) bool {
+	if strings.HasPrefix(sha, "-") {
+		return false
+	}
+	_, err := c.git(ctx, path, "cat-file", "-e", sha+"^{commit}")
+	return err == nil
+}
+
+// readLog parses the records of git log from r and sends them through commitCh.
+func readLog(ctx context.Context, r io.Reader, commitCh chan<- CommitResponse) error {
+	reader := bufio.NewReader(r)
+	for {
+		record, err := reader.ReadString(recordSep[0])
+		if err != nil && err != io.EOF {
+			return fmt.Errorf("failed to read git log: %w", err)
+		}
+		record = strings.TrimSuffix(record, recordSep)
+		if strings.TrimSpace(record) != "" {
+			commit, perr := parseRecord(record)
+			if perr != nil {
+				return perr
+			}
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case commitCh <- commit:
+			}
+		}
+		if err == io.EOF {
+			return nil
+		}
+	}
+}
+
+// parseRecord parses a commit printed with logFormat followed by its --numstat lines.
+func parseRecord(record string) (CommitResponse, error) {
+	fields := strings.Split(record, unitSep)
+	if len(fields) != 10 {
+		return CommitResponse{}, fmt.Errorf("unexpected git log record with %d fields", len(fields))
+	}
+
+	authorDate, err := time.Parse(time.RFC3339, fields[4])
+	if err != nil {
+		return CommitResponse{}, fmt.Errorf("invalid author date of %s: %w", fields[0], err)
+	}
+	committerDate, err := time.Parse(time.RFC3339, fields[7])
+	if err != nil {
+		return CommitResponse{}, fmt.Errorf("invalid committer date of %s: %w", fields[0], err)
+	}
+
+	commit := CommitResponse{
+		SHA:       fields[0],
+		Parents:   strings.Fields(fields[1]),
+		Author:    Person{Name: fields[2], Email: fields[3], Date: authorDate},
+		Committer: Person{Name: fields[5], Email: fields[6], Date: committerDate},
+		Message:   strings.TrimRight(fields[8], "\n"),
+	}
+
+	// Binary files are counted as changed, with "-" in place of their line counts.
+	for _, line := range strings.Split(fields[9], "\n") {
+		parts := strings.SplitN(line, "\t", 3)
+		if len(parts) != 3 {
+			continue
+		}
+		commit.ChangedFiles++
+		added, _ := strconv.Atoi(parts[0])
+		deleted, _ := strconv.Atoi(parts[1])
+		commit.Additions += added
+		commit.Deletions += deleted
+	}
+	return commit, nil
+}
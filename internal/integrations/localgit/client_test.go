@@ -0,0 +1,141 @@
+package localgit_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/babyfaceeasy/lema/internal/integrations/localgit"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+// newRepository creates a repository at root/owner/name with a commit per message, authored a day
+// apart, and returns the runner of git commands in it.
+func newRepository(t *testing.T, root, owner, name string, messages ...string) func(args ...string) string {
+	t.Helper()
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+
+	dir := filepath.Join(root, owner, name)
+	require.NoError(t, os.MkdirAll(dir, 0o755))
+
+	run := func(args ...string) string {
+		t.Helper()
+		cmd := exec.Command("git", append([]string{"-C", dir}, args...)...)
+		cmd.Env = append(os.Environ(),
+			"GIT_CONFIG_GLOBAL=/dev/null", "GIT_CONFIG_SYSTEM=/dev/null",
+			"GIT_AUTHOR_NAME=Alice", "GIT_AUTHOR_EMAIL=alice@example.com",
+			"GIT_COMMITTER_NAME=Bob", "GIT_COMMITTER_EMAIL=bob@example.com",
+		)
+		out, err := cmd.CombinedOutput()
+		require.NoError(t, err, string(out))
+		return strings.TrimSpace(string(out))
+	}
+	run("init", "--initial-branch=main")
+	for i, message := range messages {
+		file := filepath.Join(dir, "file.txt")
+		content := strings.Repeat("line\n", i+1)
+		require.NoError(t, os.WriteFile(file, []byte(content), 0o644))
+		date := fmt.Sprintf("2025-03-%02dT10:00:00Z", i+1)
+		run("add", "file.txt")
+		run("commit", "-m", message, "--date="+date)
+	}
+	return run
+}
+
+func collect(t *testing.T, client *localgit.Client, name, owner, rev, syncedSHA string) []localgit.CommitResponse {
+	t.Helper()
+	commitCh := make(chan localgit.CommitResponse, 100)
+	require.NoError(t, client.GetCommits(context.Background(), name, owner, rev, syncedSHA, nil, nil, commitCh))
+	close(commitCh)
+
+	var commits []localgit.CommitResponse
+	for commit := range commitCh {
+		commits = append(commits, commit)
+	}
+	return commits
+}
+
+func TestGetCommits(t *testing.T) {
+	root := t.TempDir()
+	newRepository(t, root, "team", "service", "first", "second\n\nWith a body.")
+
+	client := localgit.NewClient(root, zap.NewNop())
+
+	commits := collect(t, client, "service", "team", "", "")
+	require.Len(t, commits, 2)
+	require.Equal(t, "second\n\nWith a body.", commits[0].Message)
+	require.Equal(t, "Alice", commits[0].Author.Name)
+	require.Equal(t, "alice@example.com", commits[0].Author.Email)
+	require.Equal(t, "bob@example.com", commits[0].Committer.Email)
+	require.Equal(t, []string{commits[1].SHA}, commits[0].Parents)
+	require.Equal(t, 1, commits[0].Additions)
+	require.Equal(t, 1, commits[0].ChangedFiles)
+	require.Empty(t, commits[1].Parents)
+}
+
+func TestGetCommitsFromSyncedSHA(t *testing.T) {
+	root := t.TempDir()
+	run := newRepository(t, root, "team", "service", "first", "second")
+	synced := run("rev-parse", "HEAD")
+	run("commit", "--allow-empty", "-m", "third")
+
+	client := localgit.NewClient(root, zap.NewNop())
+
+	commits := collect(t, client, "service", "team", "main", synced)
+	require.Len(t, commits, 1)
+	require.Equal(t, "third", commits[0].Message)
+
+	// a SHA the repository lost, e.g. to a force push, falls back to a full walk
+	commits = collect(t, client, "service", "team", "main", strings.Repeat("0", 40))
+	require.Len(t, commits, 3)
+}
+
+func TestGetCommitsRefusesOptionLikeRevisions(t *testing.T) {
+	root := t.TempDir()
+	newRepository(t, root, "team", "service", "first")
+	output := filepath.Join(t.TempDir(), "written")
+
+	client := localgit.NewClient(root, zap.NewNop())
+
+	commitCh := make(chan localgit.CommitResponse, 10)
+	err := client.GetCommits(context.Background(), "service", "team", "--output="+output, "", nil, nil, commitCh)
+	require.ErrorIs(t, err, localgit.ErrInvalidRevision)
+	require.NoFileExists(t, output)
+}
+
+func TestGetRepositoryFindsBareMirrors(t *testing.T) {
+	root := t.TempDir()
+	newRepository(t, root, "team", "source", "first")
+	mirror := filepath.Join(root, "team", "mirror.git")
+	out, err := exec.Command("git", "clone", "--mirror", filepath.Join(root, "team", "source"), mirror).CombinedOutput()
+	require.NoError(t, err, string(out))
+	require.NoError(t, os.WriteFile(filepath.Join(mirror, "description"), []byte("Team mirror\n"), 0o644))
+
+	client := localgit.NewClient(root, zap.NewNop())
+
+	repo, err := client.GetRepository(context.Background(), "mirror", "team")
+	require.NoError(t, err)
+	require.Equal(t, mirror, repo.Path)
+	require.Equal(t, "main", repo.DefaultBranch)
+	require.Equal(t, "Team mirror", repo.Description)
+
+	require.Len(t, collect(t, client, "mirror", "team", "", ""), 1)
+}
+
+func TestRepositoryPathStaysUnderRoot(t *testing.T) {
+	client := localgit.NewClient(t.TempDir(), zap.NewNop())
+
+	_, err := client.RepositoryPath("..", "team")
+	require.Error(t, err)
+
+	_, err = client.RepositoryPath("missing", "team")
+	require.True(t, errors.Is(err, localgit.ErrRepositoryNotFound))
+}
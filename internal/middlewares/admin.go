@@ -0,0 +1,29 @@
+package middlewares
+
+import (
+	"crypto/subtle"
+	"net/http"
+	"strings"
+
+	"github.com/babyfaceeasy/lema/internal/utils"
+	"go.uber.org/zap"
+)
+
+// AdminAuth only lets through requests carrying the admin API token as a bearer token.
+// Every request is refused while no token is configured.
+func (m Middleware) AdminAuth(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		want := m.config.GetAdminApiToken()
+		got, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+		if want == "" || !ok || subtle.ConstantTimeCompare([]byte(got), []byte(want)) != 1 {
+			m.logger.Warn("refused unauthenticated admin request", zap.String("path", r.URL.Path))
+			utils.SendResponse(w, http.StatusUnauthorized, map[string]any{
+				"status":  false,
+				"message": "Unauthorized",
+			})
+			return
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
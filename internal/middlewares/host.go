@@ -0,0 +1,26 @@
+package middlewares
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/babyfaceeasy/lema/internal/domain"
+	"github.com/babyfaceeasy/lema/internal/utils"
+)
+
+// Host scopes the request to the repositories of the host named by the `host` query parameter,
+// github.com when it is missing. Hosts without configured credentials are rejected.
+func (m Middleware) Host(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		host := domain.NormalizeHost(r.URL.Query().Get("host"))
+		if _, ok := m.config.HostProvider(host); !ok {
+			utils.SendResponse(w, http.StatusBadRequest, map[string]any{
+				"status":  false,
+				"message": fmt.Sprintf("Unknown host %s", host),
+			})
+			return
+		}
+
+		next.ServeHTTP(w, r.WithContext(domain.WithHost(r.Context(), host)))
+	})
+}
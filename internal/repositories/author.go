@@ -0,0 +1,17 @@
+package repositories
+
+import (
+	"context"
+
+	"github.com/babyfaceeasy/lema/internal/domain"
+	"github.com/babyfaceeasy/lema/pkg/mailmap"
+	"github.com/google/uuid"
+)
+
+type AuthorRepository interface {
+	ByUID(ctx context.Context, uid uuid.UUID) (*domain.Author, error)
+	GetIdentity(ctx context.Context, authorID int) (*domain.AuthorIdentity, error)
+	Merge(ctx context.Context, sourceID int, targetID int) (int, error)
+	Split(ctx context.Context, authorID int) error
+	ReplaceMailmap(ctx context.Context, repositoryID int, entries []mailmap.Entry) (int, error)
+}
@@ -0,0 +1,16 @@
+package repositories
+
+import (
+	"context"
+	"time"
+
+	"github.com/babyfaceeasy/lema/internal/domain"
+)
+
+type BranchRepository interface {
+	Create(ctx context.Context, branch domain.Branch) error
+	ByRepositoryID(ctx context.Context, repositoryID int) ([]domain.Branch, error)
+	UpdateSinceDate(ctx context.Context, branchID int, newSinceDate time.Time) error
+	ResetSinceDates(ctx context.Context, repositoryID int) error
+	AddUnbranchedCommits(ctx context.Context, branchID int, repositoryID int) error
+}
@@ -8,8 +8,19 @@ import (
 
 type CommitRepository interface {
 	StoreCommits(ctx context.Context, commits []domain.Commit) error
-	GetCommitsByRepositoryName(ctx context.Context, owner, name string, page, pageSize int) ([]domain.Commit, int, error)
-	GetTopCommitAuthors(ctx context.Context, limit int) ([]domain.CommitAuthor, error)
+	GetCommitsByRepositoryName(ctx context.Context, owner, name string, filter domain.CommitFilter, page, pageSize int) ([]domain.Commit, int, error)
+	GetTopCommitAuthors(ctx context.Context, filter domain.AuthorFilter, limit int) ([]domain.CommitAuthor, error)
 	UpsertCommits(ctx context.Context, commits []domain.Commit) error
 	DeleteCommitsByRepositoryID(ctx context.Context, repositoryID uint) error
+	GetCommitsWithoutStats(ctx context.Context, repositoryID int, limit int) ([]domain.Commit, error)
+	CountCommitsWithoutStats(ctx context.Context, repositoryID int) (int, error)
+	UpdateCommitStats(ctx context.Context, commitID int, stats domain.CommitStats) error
+	MarkCommitStatsUnavailable(ctx context.Context, commitID int) error
+	GetCommitReview(ctx context.Context, repositoryID int, sha string) (*domain.Review, error)
+	LatestCommitSHA(ctx context.Context, repositoryID int, branchID int) (string, error)
+	LinkCommitAuthors(ctx context.Context, afterID int, limit int) (int, int, error)
+	GetTopReviewers(ctx context.Context, ownerName, repositoryName string, limit int) ([]domain.TopReviewer, error)
+	StoreCommitTrailers(ctx context.Context, afterID int, limit int) (int, int, error)
+	GetBotShare(ctx context.Context, owner, name string, filter domain.CommitFilter) (*domain.BotShare, error)
+	ClassifyBotAuthors(ctx context.Context) (int, error)
 }
@@ -0,0 +1,12 @@
+package repositories
+
+import (
+	"context"
+
+	"github.com/babyfaceeasy/lema/internal/domain"
+)
+
+type ETagRepository interface {
+	ByURL(ctx context.Context, url string) (*domain.ETag, error)
+	Save(ctx context.Context, etag domain.ETag) error
+}
@@ -0,0 +1,15 @@
+package repositories
+
+import (
+	"context"
+	"time"
+
+	"github.com/babyfaceeasy/lema/internal/domain"
+)
+
+type IssueRepository interface {
+	UpsertIssues(ctx context.Context, issues []domain.Issue) error
+	GetIssues(ctx context.Context, repositoryID int, filter domain.IssueFilter, page, pageSize int) ([]domain.Issue, int, error)
+	LatestUpdatedAt(ctx context.Context, repositoryID int) (*time.Time, error)
+	GetReferencedIssues(ctx context.Context, repositoryID int, sha string) ([]domain.IssueReference, error)
+}
@@ -0,0 +1,13 @@
+package repositories
+
+import (
+	"context"
+
+	"github.com/babyfaceeasy/lema/internal/domain"
+)
+
+type PopularityRepository interface {
+	SaveSnapshot(ctx context.Context, snapshot domain.RepositorySnapshot) error
+	ReplaceStarHistory(ctx context.Context, repositoryID int, snapshots []domain.RepositorySnapshot) error
+	GetHistory(ctx context.Context, repositoryID int, filter domain.PopularityFilter) ([]domain.PopularityPoint, error)
+}
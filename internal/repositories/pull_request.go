@@ -0,0 +1,17 @@
+package repositories
+
+import (
+	"context"
+	"time"
+
+	"github.com/babyfaceeasy/lema/internal/domain"
+)
+
+type PullRequestRepository interface {
+	UpsertPullRequests(ctx context.Context, pullRequests []domain.PullRequest) error
+	GetPullRequests(ctx context.Context, repositoryID int, filter domain.PullRequestFilter, page, pageSize int) ([]domain.PullRequest, int, error)
+	GetPullRequestStats(ctx context.Context, repositoryID int, filter domain.PullRequestFilter) (*domain.PullRequestStats, error)
+	SyncedUntil(ctx context.Context, repositoryID int) (*time.Time, error)
+	UpdateSyncedUntil(ctx context.Context, repositoryID int, syncedUntil time.Time) error
+	LinkMergeCommits(ctx context.Context, repositoryID int) (int, error)
+}
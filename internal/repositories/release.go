@@ -0,0 +1,14 @@
+package repositories
+
+import (
+	"context"
+
+	"github.com/babyfaceeasy/lema/internal/domain"
+)
+
+type ReleaseRepository interface {
+	UpsertTags(ctx context.Context, tags []domain.Tag) error
+	UpsertReleases(ctx context.Context, releases []domain.Release) error
+	GetReleases(ctx context.Context, repositoryID int, includePrereleases bool, page, pageSize int) ([]domain.Release, int, error)
+	GetReleaseCadence(ctx context.Context, repositoryID int, includePrereleases bool) (*domain.ReleaseCadence, error)
+}
@@ -14,4 +14,9 @@ type RepositoryRepository interface {
 	UpdateStartDate(ctx context.Context, owner string, name string, newStartDate time.Time) error
 	Exists(ctx context.Context, owner, name string) (bool, error)
 	GetAll(ctx context.Context) ([]domain.Repository, error)
+	Rename(ctx context.Context, repositoryID int, newOwner, newName, url string) error
+	UpdateState(ctx context.Context, repositoryID int, state string) error
+	UpdateCounters(ctx context.Context, repositoryID int, repo domain.Repository) error
+	UpdateGithubID(ctx context.Context, repositoryID int, githubID int64) error
+	UpdateDefaultBranch(ctx context.Context, repositoryID int, branch string) error
 }
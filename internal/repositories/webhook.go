@@ -0,0 +1,10 @@
+package repositories
+
+import (
+	"context"
+)
+
+type WebhookRepository interface {
+	RecordDelivery(ctx context.Context, deliveryID, event string) (bool, error)
+	ForgetDelivery(ctx context.Context, deliveryID string) error
+}
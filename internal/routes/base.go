@@ -6,6 +6,7 @@ import (
 	"github.com/babyfaceeasy/lema/internal/handlers"
 	"github.com/babyfaceeasy/lema/internal/middlewares"
 	"github.com/babyfaceeasy/lema/internal/queue"
+	"github.com/babyfaceeasy/lema/internal/services/githubservice"
 	"github.com/babyfaceeasy/lema/internal/store"
 	"github.com/gorilla/mux"
 	"go.uber.org/zap"
@@ -22,26 +23,60 @@ func RegisterRoutes(
 	store *store.Store,
 	commitSvc domain.CommitService,
 	repositorySvc domain.RepositoryService,
+	pullRequestSvc domain.PullRequestService,
+	issueSvc domain.IssueService,
+	releaseSvc domain.ReleaseService,
+	webhookSvc domain.WebhookService,
+	popularitySvc domain.PopularityService,
+	authorSvc domain.AuthorService,
+	githubSvc githubservice.GitHubService,
 	taskQueue queue.TaskQueue,
 ) *mux.Router {
 	router := mux.NewRouter()
 
-	handler = handlers.New(config, logger, store, commitSvc, repositorySvc, taskQueue)
+	handler = handlers.New(config, logger, store, commitSvc, repositorySvc, pullRequestSvc, issueSvc, releaseSvc, webhookSvc, popularitySvc, authorSvc, githubSvc, taskQueue)
 	middleware = middlewares.New(config, logger)
 
 	// global middlewares
 	router.Use(middleware.CORS)
 	router.Use(middleware.LoggerMiddleware)
+	router.Use(middleware.Host)
 
 	// v1 endpoints
 	apiV1 := router.PathPrefix("/v1").Subrouter()
 	apiV1.HandleFunc("", handler.Ping).Methods("GET")
 	apiV1.HandleFunc("/repositories/{repository_name}", handler.GetRepository).Methods("GET")
 	apiV1.HandleFunc("/repositories/{repository_name}/commits", handler.GetRepositoryCommits).Methods("GET")
+	apiV1.HandleFunc("/repositories/{repository_name}/bot-share", handler.GetRepositoryBotShare).Methods("GET")
+	apiV1.HandleFunc("/repositories/{repository_name}/branches", handler.GetRepositoryBranches).Methods("GET")
+	apiV1.HandleFunc("/repositories/{repository_name}/branches", handler.AddRepositoryBranches).Methods("POST")
+	apiV1.HandleFunc("/repositories/{repository_name}/pulls", handler.GetRepositoryPullRequests).Methods("GET")
+	apiV1.HandleFunc("/repositories/{repository_name}/pulls/stats", handler.GetRepositoryPullRequestStats).Methods("GET")
+	apiV1.HandleFunc("/repositories/{repository_name}/issues", handler.GetRepositoryIssues).Methods("GET")
+	apiV1.HandleFunc("/repositories/{repository_name}/issues/{issue_number}/commits", handler.GetIssueCommits).Methods("GET")
+	apiV1.HandleFunc("/repositories/{repository_name}/commits/{sha}/issues", handler.GetCommitIssues).Methods("GET")
+	apiV1.HandleFunc("/repositories/{repository_name}/commits/{sha}/review", handler.GetCommitReview).Methods("GET")
+	apiV1.HandleFunc("/repositories/{repository_name}/releases", handler.GetRepositoryReleases).Methods("GET")
+	apiV1.HandleFunc("/repositories/{repository_name}/releases/cadence", handler.GetRepositoryReleaseCadence).Methods("GET")
+	apiV1.HandleFunc("/repositories/{repository_name}/popularity", handler.GetRepositoryPopularity).Methods("GET")
+	apiV1.HandleFunc("/repositories/{repository_name}/popularity/backfill", handler.BackfillRepositoryStarHistory).Methods("POST")
 	apiV1.HandleFunc("/repositories/monitor", handler.MonitorRepository).Methods("POST")
 	apiV1.HandleFunc("/repositories/reset-collection", handler.ResetCollection).Methods("POST")
+	apiV1.HandleFunc("/repositories/{repository_name}/mbox", handler.ImportMailbox).Methods("POST")
 	// commits
 	apiV1.HandleFunc("/commit-authors/top", handler.GetTopCommitAuthors).Methods("GET")
+	apiV1.HandleFunc("/commit-reviewers/top", handler.GetTopCommitReviewers).Methods("GET")
+	// webhooks
+	apiV1.HandleFunc("/webhooks/github", handler.GithubWebhook).Methods("POST")
+	// admin
+	admin := apiV1.PathPrefix("/admin").Subrouter()
+	admin.Use(middleware.AdminAuth)
+	admin.HandleFunc("/github/tokens", handler.GetGithubTokens).Methods("GET")
+	admin.HandleFunc("/authors/merge", handler.MergeAuthors).Methods("POST")
+	admin.HandleFunc("/authors/{author_id}", handler.GetAuthorIdentity).Methods("GET")
+	admin.HandleFunc("/authors/{author_id}/split", handler.SplitAuthor).Methods("POST")
+	admin.HandleFunc("/repositories/{repository_name}/mailmap", handler.ImportRepositoryMailmap).Methods("PUT")
+	admin.HandleFunc("/repositories/{repository_name}/mailmap/sync", handler.SyncRepositoryMailmap).Methods("POST")
 
 	return router
 }
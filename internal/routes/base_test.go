@@ -0,0 +1,34 @@
+package routes_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/babyfaceeasy/lema/config"
+	"github.com/babyfaceeasy/lema/internal/routes"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestAdminRoutesRefuseUnauthenticatedRequests(t *testing.T) {
+	router := routes.RegisterRoutes(&config.Config{}, zap.NewNop(), nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil)
+
+	requests := []*http.Request{
+		httptest.NewRequest(http.MethodPut, "/v1/admin/repositories/chromium/mailmap?owner_name=chromium", strings.NewReader("Jane <jane@example.com> <j@example.com>\n")),
+		httptest.NewRequest(http.MethodPost, "/v1/admin/repositories/chromium/mailmap/sync?owner_name=chromium", nil),
+		httptest.NewRequest(http.MethodPost, "/v1/admin/authors/merge", strings.NewReader(`{"source_id":"a","target_id":"b"}`)),
+		httptest.NewRequest(http.MethodGet, "/v1/admin/github/tokens", nil),
+	}
+	for _, req := range requests {
+		rr := httptest.NewRecorder()
+		router.ServeHTTP(rr, req)
+		require.Equal(t, http.StatusUnauthorized, rr.Code, "%s %s", req.Method, req.URL.Path)
+	}
+
+	// the former public route of the mailmap import is gone
+	rr := httptest.NewRecorder()
+	router.ServeHTTP(rr, httptest.NewRequest(http.MethodPut, "/v1/repositories/chromium/mailmap?owner_name=chromium", nil))
+	require.Equal(t, http.StatusNotFound, rr.Code)
+}
@@ -30,6 +30,13 @@ func (s *ApiServer) Start(ctx context.Context, diContainer *container.Container)
 		s.store, 
 		diContainer.GetCommitService(), 
 		diContainer.GetRepositoryService(), 
+		diContainer.GetPullRequestService(),
+		diContainer.GetIssueService(),
+		diContainer.GetReleaseService(),
+		diContainer.GetWebhookService(),
+		diContainer.GetPopularityService(),
+		diContainer.GetAuthorService(),
+		diContainer.GetGithubService(),
 		diContainer.GetTaskQueue(),
 	)
 	server := &http.Server{
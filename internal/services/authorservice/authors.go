@@ -0,0 +1,136 @@
+package authorservice
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/babyfaceeasy/lema/internal/domain"
+	"github.com/babyfaceeasy/lema/internal/repositories"
+	"github.com/babyfaceeasy/lema/internal/services/githubservice"
+	"github.com/babyfaceeasy/lema/pkg/mailmap"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+type authorService struct {
+	githubService     githubservice.GitHubService
+	logger            *zap.Logger
+	authorRepo        repositories.AuthorRepository
+	repositoryService domain.RepositoryService
+}
+
+func NewAuthorService(gitHubService githubservice.GitHubService, authorRepo repositories.AuthorRepository, logger *zap.Logger, repoSvc domain.RepositoryService) domain.AuthorService {
+	logger = logger.With(zap.String("package", "authorservice"))
+	return &authorService{
+		githubService:     gitHubService,
+		authorRepo:        authorRepo,
+		logger:            logger,
+		repositoryService: repoSvc,
+	}
+}
+
+func (as *authorService) repository(ctx context.Context, ownerName, repoName string) (*domain.Repository, error) {
+	repoDetails, err := as.repositoryService.GetRepository(ctx, ownerName, repoName)
+	if err != nil {
+		return nil, err
+	}
+	if repoDetails == nil {
+		return nil, fmt.Errorf("repository %s/%s does not exist in our system: %w", ownerName, repoName, domain.ErrNotFound)
+	}
+	return repoDetails, nil
+}
+
+func (as *authorService) author(ctx context.Context, uid uuid.UUID) (*domain.Author, error) {
+	author, err := as.authorRepo.ByUID(ctx, uid)
+	if err != nil {
+		return nil, err
+	}
+	if author == nil {
+		return nil, fmt.Errorf("author %s: %w", uid, domain.ErrNotFound)
+	}
+	return author, nil
+}
+
+// GetAuthorIdentity returns the canonical author an author resolves to, with its aliases.
+func (as *authorService) GetAuthorIdentity(ctx context.Context, uid uuid.UUID) (*domain.AuthorIdentity, error) {
+	author, err := as.author(ctx, uid)
+	if err != nil {
+		return nil, err
+	}
+	return as.authorRepo.GetIdentity(ctx, author.ID)
+}
+
+// MergeAuthors resolves the source author, and its aliases, to the canonical author of the target.
+func (as *authorService) MergeAuthors(ctx context.Context, sourceUID, targetUID uuid.UUID) (*domain.AuthorIdentity, error) {
+	logr := as.logger.With(zap.String("method", "MergeAuthors"))
+
+	if sourceUID == targetUID {
+		return nil, fmt.Errorf("%w: an author cannot be merged into itself", domain.ErrInvalidAuthorMerge)
+	}
+	source, err := as.author(ctx, sourceUID)
+	if err != nil {
+		return nil, err
+	}
+	target, err := as.author(ctx, targetUID)
+	if err != nil {
+		return nil, err
+	}
+
+	canonicalID, err := as.authorRepo.Merge(ctx, source.ID, target.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	logr.Info("Authors merged", zap.String("source_email", source.Email), zap.String("target_email", target.Email))
+	return as.authorRepo.GetIdentity(ctx, canonicalID)
+}
+
+// SplitAuthor makes an author canonical again, detaching it from the author it resolved to, or
+// detaching its aliases when it is canonical itself.
+func (as *authorService) SplitAuthor(ctx context.Context, uid uuid.UUID) (*domain.AuthorIdentity, error) {
+	logr := as.logger.With(zap.String("method", "SplitAuthor"))
+
+	author, err := as.author(ctx, uid)
+	if err != nil {
+		return nil, err
+	}
+	if err := as.authorRepo.Split(ctx, author.ID); err != nil {
+		return nil, err
+	}
+
+	logr.Info("Author split", zap.String("email", author.Email))
+	return as.authorRepo.GetIdentity(ctx, author.ID)
+}
+
+// ImportMailmap replaces the .mailmap rules of a repository with those of contents and applies them.
+func (as *authorService) ImportMailmap(ctx context.Context, ownerName, repoName string, contents string) (*domain.MailmapImport, error) {
+	logr := as.logger.With(zap.String("method", "ImportMailmap"))
+
+	repoDetails, err := as.repository(ctx, ownerName, repoName)
+	if err != nil {
+		return nil, err
+	}
+
+	entries := mailmap.Parse(contents)
+	aliases, err := as.authorRepo.ReplaceMailmap(ctx, repoDetails.ID, entries)
+	if err != nil {
+		return nil, err
+	}
+
+	logr.Info("Mailmap imported", zap.String("repo_name", repoDetails.Name), zap.Int("entries", len(entries)), zap.Int("aliases", aliases))
+	return &domain.MailmapImport{Entries: len(entries), Aliases: aliases}, nil
+}
+
+// SyncMailmap imports the .mailmap file of the default branch of a GitHub repository.
+func (as *authorService) SyncMailmap(ctx context.Context, ownerName, repoName string) (*domain.MailmapImport, error) {
+	repoDetails, err := as.repository(ctx, ownerName, repoName)
+	if err != nil {
+		return nil, err
+	}
+
+	contents, err := as.githubService.GetFileContents(ctx, repoDetails.Name, repoDetails.OwnerName, ".mailmap")
+	if err != nil {
+		return nil, err
+	}
+	return as.ImportMailmap(ctx, repoDetails.OwnerName, repoDetails.Name, string(contents))
+}
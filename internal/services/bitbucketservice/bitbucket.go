@@ -0,0 +1,93 @@
+package bitbucketservice
+
+import (
+	"context"
+	"time"
+
+	"github.com/babyfaceeasy/lema/internal/domain"
+	"github.com/babyfaceeasy/lema/internal/integrations/bitbucketapi"
+	"go.uber.org/zap"
+)
+
+type bitbucketService struct {
+	client *bitbucketapi.Client
+	logger *zap.Logger
+}
+
+// NewBitbucketService creates a domain.Provider for the repositories of Bitbucket Cloud.
+func NewBitbucketService(client *bitbucketapi.Client, logger *zap.Logger) domain.Provider {
+	logger = logger.With(zap.String("package", "bitbucketservice"))
+	return &bitbucketService{
+		client: client,
+		logger: logger,
+	}
+}
+
+// GetRepositoryDetails returns the repository with slug repositoryName in workspace ownerName.
+// Bitbucket has no stars nor repository-wide issue count.
+func (s *bitbucketService) GetRepositoryDetails(ctx context.Context, repositoryName, ownerName string) (*domain.Repository, error) {
+	repo, err := s.client.GetRepository(ctx, repositoryName, ownerName)
+	if err != nil {
+		return nil, err
+	}
+
+	return &domain.Repository{
+		Provider:            domain.ProviderBitbucket,
+		Name:                repo.Slug,
+		OwnerName:           repo.Workspace.Slug,
+		Description:         repo.Description,
+		URL:                 repo.Links.HTML.Href,
+		ProgrammingLanguage: repo.Language,
+		ForksCount:          repo.ForksCount,
+		WatchersCount:       repo.WatchersCount,
+		DefaultBranch:       repo.Mainbranch.Name,
+	}, nil
+}
+
+func (s *bitbucketService) GetCommitsNew(ctx context.Context, repositoryName, ownerName string, since, until *time.Time, pageSize int, commitCh chan<- domain.Commit) error {
+	tempCh := make(chan bitbucketapi.CommitResponse, 200)
+	errCh := make(chan error, 1)
+	go func() {
+		defer close(tempCh)
+		errCh <- s.client.GetCommits(ctx, repositoryName, ownerName, since, until, pageSize, tempCh)
+	}()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case cr, ok := <-tempCh:
+			if !ok {
+				return <-errCh
+			}
+			commitCh <- convertToDomainCommit(cr)
+		}
+	}
+}
+
+// convertToDomainCommit converts a bitbucketapi.CommitResponse to a domain.Commit. Bitbucket only
+// reports the author of a commit, so the committer is left to default to them.
+func convertToDomainCommit(cr bitbucketapi.CommitResponse) domain.Commit {
+	name, email := cr.AuthorNameAndEmail()
+	if name == "" && cr.Author.User != nil {
+		name = cr.Author.User.DisplayName
+	}
+
+	parents := make([]string, 0, len(cr.Parents))
+	for _, p := range cr.Parents {
+		parents = append(parents, p.Hash)
+	}
+
+	return domain.Commit{
+		SHA:        cr.Hash,
+		URL:        cr.Links.HTML.Href,
+		Message:    cr.Message,
+		CommitDate: cr.Date,
+		CreatedAt:  time.Now(),
+		Author: domain.Author{
+			Name:  name,
+			Email: email,
+		},
+		Parents: parents,
+	}
+}
@@ -0,0 +1,32 @@
+package commitsservice
+
+import (
+	"context"
+
+	"go.uber.org/zap"
+)
+
+// BackfillCommitAuthors credits every stored commit to its author, committer and co-authors, and
+// returns how many commits were handled. Commits stored since co-authors are recorded are credited
+// when stored, so this is only needed once for older commits.
+func (cs *commitService) BackfillCommitAuthors(ctx context.Context) (int, error) {
+	logr := cs.logger.With(zap.String("method", "BackfillCommitAuthors"))
+
+	batchSize := 500
+	lastID, total := 0, 0
+	for {
+		nextID, count, err := cs.commitRepo.LinkCommitAuthors(ctx, lastID, batchSize)
+		if err != nil {
+			return total, err
+		}
+		if count == 0 {
+			break
+		}
+
+		lastID = nextID
+		total += count
+		logr.Info("Credited commit authors", zap.Int("last_commit_id", lastID), zap.Int("total", total))
+	}
+
+	return total, nil
+}
@@ -0,0 +1,45 @@
+package commitsservice
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/babyfaceeasy/lema/internal/domain"
+	"go.uber.org/zap"
+)
+
+// GetBotShare splits the commits of a repository matching filter, and their authors, between bots
+// and people. filter.Bots is ignored.
+func (cs *commitService) GetBotShare(ctx context.Context, ownerName, repoName string, filter domain.CommitFilter) (*domain.BotShare, error) {
+	repoDetails, err := cs.repositoryService.GetRepository(ctx, ownerName, repoName)
+	if err != nil {
+		return nil, err
+	}
+	if repoDetails == nil {
+		return nil, fmt.Errorf("repository %s/%s does not exist in our system", ownerName, repoName)
+	}
+
+	filter.Bots = ""
+	share, err := cs.commitRepo.GetBotShare(ctx, repoDetails.OwnerName, repoDetails.Name, filter)
+	if err != nil {
+		return nil, err
+	}
+	if share.TotalCommits > 0 {
+		share.BotCommitShare = float64(share.BotCommits) / float64(share.TotalCommits)
+	}
+	return share, nil
+}
+
+// ClassifyBotAuthors flags the stored authors matching the bot patterns, and returns how many
+// were. Authors stored since the patterns were configured are flagged when stored.
+func (cs *commitService) ClassifyBotAuthors(ctx context.Context) (int, error) {
+	logr := cs.logger.With(zap.String("method", "ClassifyBotAuthors"))
+
+	count, err := cs.commitRepo.ClassifyBotAuthors(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	logr.Info("Classified bot authors", zap.Int("flagged", count))
+	return count, nil
+}
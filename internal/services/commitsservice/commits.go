@@ -2,6 +2,7 @@ package commitsservice
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"time"
 
@@ -30,10 +31,10 @@ func NewCommitService(gitHubService githubservice.GitHubService, commitRepo repo
 	}
 }
 
-func (cs *commitService) GetTopCommitAuthors(ctx context.Context, owner, name string, limit int) ([]domain.CommitAuthor, error) {
+func (cs *commitService) GetTopCommitAuthors(ctx context.Context, owner, name string, filter domain.AuthorFilter, limit int) ([]domain.CommitAuthor, error) {
 	logr := cs.logger.With(zap.String("method", "GetTopCommitAuthors"))
 
-	authors, err := cs.commitRepo.GetTopCommitAuthors(ctx, limit)
+	authors, err := cs.commitRepo.GetTopCommitAuthors(ctx, filter, limit)
 	if err != nil {
 		logr.Error("error in getting GetTopCommitAuthors", zap.Error(err))
 	}
@@ -41,10 +42,10 @@ func (cs *commitService) GetTopCommitAuthors(ctx context.Context, owner, name st
 	return authors, nil
 }
 
-func (cs *commitService) GetCommitsByRepositoryName(ctx context.Context, owner, name string, page, pageSize int) ([]domain.Commit, *pagination.Pagination, error) {
+func (cs *commitService) GetCommitsByRepositoryName(ctx context.Context, owner, name string, filter domain.CommitFilter, page, pageSize int) ([]domain.Commit, *pagination.Pagination, error) {
 	logr := cs.logger.With(zap.String("method", "GetCommitsByRepositoryName"))
 
-	commits, totalItems, err := cs.commitRepo.GetCommitsByRepositoryName(ctx, owner, name, page, pageSize)
+	commits, totalItems, err := cs.commitRepo.GetCommitsByRepositoryName(ctx, owner, name, filter, page, pageSize)
 	if err != nil {
 		logr.Error("error in GetCommitsByRepositoryName", zap.Error(err))
 		return nil, nil, err
@@ -65,6 +66,8 @@ func (cs *commitService) LoadCommits(ctx context.Context, ownerName, repoName st
 		return fmt.Errorf("repository %s/%s does not exist in our system", ownerName, repoName)
 	}
 
+	branchID := cs.defaultBranchID(ctx, repoDetails)
+
 	// Create a buffered channel for domain.Commit values.
 	commitCh := make(chan domain.Commit, 200)
 
@@ -106,6 +109,7 @@ func (cs *commitService) LoadCommits(ctx context.Context, ownerName, repoName st
 			// attach repository details
 			commit.Repository = *repoDetails
 			commit.RepositoryID = repoDetails.ID
+			commit.BranchID = branchID
 			commits = append(commits, commit)
 			if len(commits) >= batchSize {
 				logr.Info("Storing batch of commits", zap.Int("batchSize", len(commits)))
@@ -130,15 +134,23 @@ func (cs *commitService) GetLatestCommitsNew(ctx context.Context, ownerName, rep
 		return fmt.Errorf("repository %s/%s does not exist in our system", ownerName, repoName)
 	}
 
+	branchID := cs.defaultBranchID(ctx, repoDetails)
+	fetchCtx := githubservice.WithConditionalRequests(cs.withSyncedSHA(ctx, repoDetails, branchID))
+
 	// Create a buffered channel for domain.Commit values.
 	commitCh := make(chan domain.Commit, 200)
 
 	// Launch the GitHub service to fetch commits concurrently.
+	// The request is conditional so an unchanged history costs no rate limit.
+	var fetchErr error
 	go func() {
 		defer close(commitCh)
-		err := cs.githubService.GetCommitsNew(ctx, repoName, ownerName, &repoDetails.SinceDate, repoDetails.UntilDate, 100, commitCh)
-		if err != nil {
+		err := cs.githubService.GetCommitsNew(fetchCtx, repoName, ownerName, &repoDetails.SinceDate, repoDetails.UntilDate, 100, commitCh)
+		if errors.Is(err, domain.ErrNotModified) {
+			logr.Info("No new commits since last sync", zap.String("repo_name", repoDetails.Name))
+		} else if err != nil {
 			logr.Error("Error fetching commits", zap.Error(err))
+			fetchErr = err
 		} else {
 			logr.Info("Finished fetching commits from GitHub")
 		}
@@ -162,6 +174,15 @@ func (cs *commitService) GetLatestCommitsNew(ctx context.Context, ownerName, rep
 					}
 					logr.Info("Stored final batch of commits", zap.Int("batchSize", len(commits)))
 				}
+				if errors.Is(fetchErr, domain.ErrNotFound) {
+					return cs.followMissingRepository(ctx, repoDetails)
+				}
+				if fetchErr == nil {
+					// only now that every commit is stored may the next sync be answered with a 304.
+					if err := githubservice.SaveValidators(fetchCtx); err != nil {
+						logr.Error("failed to save etags", zap.String("repo_name", repoDetails.Name), zap.Error(err))
+					}
+				}
 				logr.Info("Loaded all commits successfully", zap.Int("totalCommitsSaved", commitCount))
 				return nil
 			}
@@ -172,6 +193,7 @@ func (cs *commitService) GetLatestCommitsNew(ctx context.Context, ownerName, rep
 			// Attach repository data to the commit before saving
 			commit.RepositoryID = repoDetails.ID
 			commit.Repository = *repoDetails
+			commit.BranchID = branchID
 
 			commits = append(commits, commit)
 			// If batch is full, save to DB and reset the slice.
@@ -193,6 +215,24 @@ func (cs *commitService) GetLatestCommitsNew(ctx context.Context, ownerName, rep
 	}
 }
 
+// followMissingRepository refreshes a repository whose commits GitHub no longer finds, so that a
+// deleted repository gets the deleted state and is no longer synced instead of failing every sync.
+func (cs *commitService) followMissingRepository(ctx context.Context, repoDetails *domain.Repository) error {
+	logr := cs.logger.With(zap.String("method", "followMissingRepository"))
+
+	refreshed, err := cs.repositoryService.RefreshRepository(ctx, repoDetails.OwnerName, repoDetails.Name)
+	if err != nil {
+		return fmt.Errorf("failed to refresh repository %s/%s: %w", repoDetails.OwnerName, repoDetails.Name, err)
+	}
+
+	logr.Info("Repository refreshed after its commits were not found",
+		zap.String("repo_name", refreshed.Name),
+		zap.String("owner_name", refreshed.OwnerName),
+		zap.String("state", refreshed.State),
+	)
+	return nil
+}
+
 func (cs *commitService) ResetCommits(ctx context.Context, ownerName, repoName string) error {
 	logr := cs.logger.With(zap.String("method", "ResetCommits"))
 	repoDetails, err := cs.repositoryService.GetRepository(ctx, ownerName, repoName)
@@ -204,10 +244,213 @@ func (cs *commitService) ResetCommits(ctx context.Context, ownerName, repoName s
 		return err
 	}
 
-	if err := tasks.CallLoadCommitsTask(repoDetails.OwnerName, repoDetails.Name); err != nil {
+	if err := cs.repositoryService.ResetRepositoryBranches(ctx, repoDetails.OwnerName, repoDetails.Name); err != nil {
+		return err
+	}
+
+	if err := tasks.CallLoadCommitsTask(repoDetails.Host, repoDetails.OwnerName, repoDetails.Name); err != nil {
 		return err
 	}
 
 	logr.Debug("reset collection for", zap.String("repo_name", repoDetails.Name))
 	return nil
 }
+
+// LoadCommitStats fetches the line statistics of up to limit stored commits of the repository that
+// do not have them yet. Progress is saved per commit so an interrupted backfill resumes where it
+// stopped. It stops early, reporting when to resume, once the GitHub budget runs low.
+func (cs *commitService) LoadCommitStats(ctx context.Context, ownerName, repoName string, limit int) (*domain.CommitStatsProgress, error) {
+	logr := cs.logger.With(zap.String("method", "LoadCommitStats"))
+
+	repoDetails, err := cs.repositoryService.GetRepository(ctx, ownerName, repoName)
+	if err != nil {
+		return nil, err
+	}
+	if repoDetails == nil {
+		return nil, fmt.Errorf("repository %s/%s does not exist in our system", ownerName, repoName)
+	}
+
+	// other providers return statistics with the history, when they have them at all
+	if !repoDetails.HostedOnGithub() {
+		return &domain.CommitStatsProgress{}, nil
+	}
+
+	commits, err := cs.commitRepo.GetCommitsWithoutStats(ctx, repoDetails.ID, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	progress := &domain.CommitStatsProgress{}
+	for _, commit := range commits {
+		budget, err := cs.githubService.GetRateLimit(ctx)
+		if err != nil {
+			return nil, err
+		}
+		// leave a tenth of the budget to the other GitHub calls.
+		if budget.Limit > 0 && budget.Remaining <= budget.Limit/10 && time.Now().Before(budget.Reset) {
+			logr.Info("GitHub budget running low, pausing commit stats", zap.Int("remaining", budget.Remaining), zap.Time("reset", budget.Reset))
+			progress.ResumeAt = budget.Reset
+			break
+		}
+
+		stats, err := cs.githubService.GetCommitStats(ctx, repoDetails.Name, repoDetails.OwnerName, commit.SHA)
+		if errors.Is(err, domain.ErrNotFound) {
+			// asking again would fail the same way and hold back the rest of the backfill.
+			logr.Warn("GitHub has no stats for commit, skipping it", zap.String("sha", commit.SHA))
+			if err := cs.commitRepo.MarkCommitStatsUnavailable(ctx, commit.ID); err != nil {
+				return nil, err
+			}
+			progress.Skipped++
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to fetch stats of commit %s: %w", commit.SHA, err)
+		}
+
+		if err := cs.commitRepo.UpdateCommitStats(ctx, commit.ID, *stats); err != nil {
+			return nil, err
+		}
+		progress.Processed++
+	}
+
+	progress.Pending, err = cs.commitRepo.CountCommitsWithoutStats(ctx, repoDetails.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	logr.Info("Loaded commit stats", zap.String("repo_name", repoDetails.Name), zap.Int("processed", progress.Processed), zap.Int("skipped", progress.Skipped), zap.Int("pending", progress.Pending))
+	return progress, nil
+}
+
+// withSyncedSHA records the latest stored commit of the repository, or of its monitored branch
+// when branchID is not 0, in ctx for local repositories, the only ones walked from it. Other
+// providers skip the lookup.
+func (cs *commitService) withSyncedSHA(ctx context.Context, repoDetails *domain.Repository, branchID int) context.Context {
+	if repoDetails.Provider != domain.ProviderLocal {
+		return ctx
+	}
+	sha, err := cs.commitRepo.LatestCommitSHA(ctx, repoDetails.ID, branchID)
+	if err != nil {
+		cs.logger.Error("error in getting latest stored commit", zap.String("repo_name", repoDetails.Name), zap.Error(err))
+		return ctx
+	}
+	if sha == "" {
+		return ctx
+	}
+	return domain.WithSyncedSHA(ctx, sha)
+}
+
+// defaultBranchID returns the ID of the monitored branch row of the repository's default branch,
+// registering it when missing, or 0 when it is not known.
+func (cs *commitService) defaultBranchID(ctx context.Context, repoDetails *domain.Repository) int {
+	branch, err := cs.repositoryService.EnsureDefaultBranch(ctx, repoDetails.OwnerName, repoDetails.Name)
+	if err != nil {
+		cs.logger.Error("error in getting default branch", zap.String("repo_name", repoDetails.Name), zap.Error(err))
+		return 0
+	}
+	if branch == nil {
+		return 0
+	}
+	return branch.ID
+}
+
+// SyncBranchCommits fetches the commits of a monitored branch made since its last sync, stores
+// them and records their branch membership. The first sync of a branch reads its whole history.
+func (cs *commitService) SyncBranchCommits(ctx context.Context, ownerName, repoName, branchName string) error {
+	logr := cs.logger.With(zap.String("method", "SyncBranchCommits"))
+
+	repoDetails, err := cs.repositoryService.GetRepository(ctx, ownerName, repoName)
+	if err != nil {
+		return err
+	}
+	if repoDetails == nil {
+		return fmt.Errorf("repository %s/%s does not exist in our system", ownerName, repoName)
+	}
+
+	branches, err := cs.repositoryService.GetRepositoryBranches(ctx, ownerName, repoName)
+	if err != nil {
+		return err
+	}
+	var branch *domain.Branch
+	for i := range branches {
+		if branches[i].Name == branchName {
+			branch = &branches[i]
+			break
+		}
+	}
+	if branch == nil {
+		return fmt.Errorf("branch %s of %s/%s is not monitored", branchName, ownerName, repoName)
+	}
+
+	// The watermark is taken before fetching so commits pushed during the sync are picked up next time.
+	syncStartedAt := time.Now()
+
+	commitCh := make(chan domain.Commit, 200)
+	errCh := make(chan error, 1)
+
+	fetchCtx := githubservice.WithBranch(ctx, branch.Name)
+	if branch.SinceDate != nil {
+		fetchCtx = cs.withSyncedSHA(githubservice.WithConditionalRequests(fetchCtx), repoDetails, branch.ID)
+	}
+
+	go func() {
+		defer close(commitCh)
+		errCh <- cs.githubService.GetCommitsNew(fetchCtx, repoDetails.Name, repoDetails.OwnerName, branch.SinceDate, repoDetails.UntilDate, 100, commitCh)
+	}()
+
+	var commits []domain.Commit
+	batchSize := 50
+	commitCount := 0
+
+	for {
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("context canceled while processing commits")
+		case commit, ok := <-commitCh:
+			if !ok {
+				if err := <-errCh; err != nil && !errors.Is(err, domain.ErrNotModified) {
+					return fmt.Errorf("failed to fetch commits of branch %s: %w", branch.Name, err)
+				}
+				if len(commits) > 0 {
+					if err := cs.commitRepo.UpsertCommits(ctx, commits); err != nil {
+						return fmt.Errorf("failed to upsert remaining commits: %w", err)
+					}
+				}
+				if err := cs.repositoryService.UpdateBranchSinceDate(ctx, branch.ID, syncStartedAt); err != nil {
+					return err
+				}
+				if err := githubservice.SaveValidators(fetchCtx); err != nil {
+					logr.Error("failed to save etags", zap.String("branch", branch.Name), zap.Error(err))
+				}
+				logr.Info("Synced branch", zap.String("repo_name", repoDetails.Name), zap.String("branch", branch.Name), zap.Int("totalCommitsSaved", commitCount))
+				return nil
+			}
+
+			commitCount++
+			commit.RepositoryID = repoDetails.ID
+			commit.Repository = *repoDetails
+			commit.BranchID = branch.ID
+
+			commits = append(commits, commit)
+			if len(commits) >= batchSize {
+				if err := cs.commitRepo.UpsertCommits(ctx, commits); err != nil {
+					return fmt.Errorf("failed to upsert commit batch: %w", err)
+				}
+				commits = commits[:0]
+			}
+		}
+	}
+}
+
+// GetCommitReview returns the code review of a stored commit, or nil when its provider recorded none.
+func (cs *commitService) GetCommitReview(ctx context.Context, ownerName, repoName, sha string) (*domain.Review, error) {
+	repoDetails, err := cs.repositoryService.GetRepository(ctx, ownerName, repoName)
+	if err != nil {
+		return nil, err
+	}
+	if repoDetails == nil {
+		return nil, fmt.Errorf("repository %s/%s does not exist in our system", ownerName, repoName)
+	}
+
+	return cs.commitRepo.GetCommitReview(ctx, repoDetails.ID, sha)
+}
@@ -0,0 +1,89 @@
+package commitsservice
+
+import (
+	"context"
+	"crypto/sha1"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/babyfaceeasy/lema/internal/domain"
+	"github.com/babyfaceeasy/lema/pkg/mailpatch"
+	"go.uber.org/zap"
+)
+
+// ImportMailbox stores the patches of an mbox file or of git format-patch output as commits of
+// the pseudo-repository owner/name on domain.MailboxHost, creating it when needed. Importing the
+// same mailbox again updates the commits it stored.
+func (cs *commitService) ImportMailbox(ctx context.Context, ownerName, repoName string, mbox io.Reader) (*domain.MailboxImport, error) {
+	logr := cs.logger.With(zap.String("method", "ImportMailbox"))
+
+	parsed, err := mailpatch.Parse(mbox)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &domain.MailboxImport{Skipped: parsed.Skipped, Errors: []domain.MailboxImportError{}}
+	for _, perr := range parsed.Errors {
+		logr.Warn("malformed message in mailbox", zap.String("repo_name", repoName), zap.Int("message", perr.Message), zap.Int("line", perr.Line), zap.Error(perr.Err))
+		result.Errors = append(result.Errors, domain.MailboxImportError{Message: perr.Message, Line: perr.Line, Error: perr.Err.Error()})
+	}
+
+	ctx = domain.WithHost(ctx, domain.MailboxHost)
+	repo := domain.Repository{
+		Host:      domain.MailboxHost,
+		Provider:  domain.ProviderMailbox,
+		Name:      repoName,
+		OwnerName: ownerName,
+	}
+
+	var commits []domain.Commit
+	batchSize := 50
+	for _, patch := range parsed.Patches {
+		commits = append(commits, domain.Commit{
+			SHA:        patchSHA(patch),
+			Message:    patch.Message(),
+			CommitDate: patch.Date,
+			CreatedAt:  time.Now(),
+			Repository: repo,
+			Author: domain.Author{
+				Name:  patch.AuthorName,
+				Email: patch.AuthorEmail,
+			},
+		})
+		if len(commits) >= batchSize {
+			if err := cs.commitRepo.UpsertCommits(ctx, commits); err != nil {
+				return nil, fmt.Errorf("failed to store imported patches: %w", err)
+			}
+			result.Imported += len(commits)
+			commits = commits[:0]
+		}
+	}
+	if len(commits) > 0 {
+		if err := cs.commitRepo.UpsertCommits(ctx, commits); err != nil {
+			return nil, fmt.Errorf("failed to store imported patches: %w", err)
+		}
+		result.Imported += len(commits)
+	}
+
+	logr.Info("Imported mailbox", zap.String("owner_name", ownerName), zap.String("repo_name", repoName),
+		zap.Int("imported", result.Imported), zap.Int("skipped", result.Skipped), zap.Int("malformed", len(result.Errors)))
+	return result, nil
+}
+
+// patchSHA returns the SHA of the commit a patch was made from. Patches sent without it are given
+// a stable pseudo SHA derived from their Message-ID, or else from their author, date and subject,
+// so they are stored once however often they are imported.
+func patchSHA(patch mailpatch.Patch) string {
+	if patch.SHA != "" {
+		return patch.SHA
+	}
+
+	key := "message-id:" + patch.MessageID
+	if patch.MessageID == "" {
+		key = fmt.Sprintf("patch:%s\n%s\n%s", patch.AuthorEmail, patch.Date.UTC().Format(time.RFC3339), patch.Subject)
+	}
+	sum := sha1.Sum([]byte(key))
+	return hex.EncodeToString(sum[:])
+}
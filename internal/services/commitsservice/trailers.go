@@ -0,0 +1,46 @@
+package commitsservice
+
+import (
+	"context"
+
+	"github.com/babyfaceeasy/lema/internal/domain"
+	"go.uber.org/zap"
+)
+
+// GetTopReviewers returns the people most often credited by "Reviewed-by:" trailers of the
+// commits of the host ctx is scoped to, or of ownerName/repoName when repoName is not empty.
+func (cs *commitService) GetTopReviewers(ctx context.Context, ownerName, repoName string, limit int) ([]domain.TopReviewer, error) {
+	logr := cs.logger.With(zap.String("method", "GetTopReviewers"))
+
+	reviewers, err := cs.commitRepo.GetTopReviewers(ctx, ownerName, repoName, limit)
+	if err != nil {
+		logr.Error("error in getting top reviewers", zap.Error(err))
+		return nil, err
+	}
+	return reviewers, nil
+}
+
+// BackfillCommitTrailers records the trailers of every stored commit, and returns how many commits
+// were handled. Trailers of commits stored since they are recorded are read when stored, so this
+// is only needed once for older commits.
+func (cs *commitService) BackfillCommitTrailers(ctx context.Context) (int, error) {
+	logr := cs.logger.With(zap.String("method", "BackfillCommitTrailers"))
+
+	batchSize := 500
+	lastID, total := 0, 0
+	for {
+		nextID, count, err := cs.commitRepo.StoreCommitTrailers(ctx, lastID, batchSize)
+		if err != nil {
+			return total, err
+		}
+		if count == 0 {
+			break
+		}
+
+		lastID = nextID
+		total += count
+		logr.Info("Recorded commit trailers", zap.Int("last_commit_id", lastID), zap.Int("total", total))
+	}
+
+	return total, nil
+}
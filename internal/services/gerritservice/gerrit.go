@@ -0,0 +1,197 @@
+package gerritservice
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/babyfaceeasy/lema/internal/domain"
+	"github.com/babyfaceeasy/lema/internal/integrations/gerritapi"
+	"go.uber.org/zap"
+)
+
+type gerritService struct {
+	client *gerritapi.Client
+	logger *zap.Logger
+}
+
+// NewGerritService creates a domain.Provider for the projects of a Gerrit server. The merged
+// changes of a project stand for its commits and carry their review.
+func NewGerritService(client *gerritapi.Client, logger *zap.Logger) domain.Provider {
+	logger = logger.With(zap.String("package", "gerritservice"))
+	return &gerritService{
+		client: client,
+		logger: logger,
+	}
+}
+
+// projectName returns the Gerrit project a repository stands for. The last segment of the project
+// name is the repository name and the ones before it its owner, e.g. chromium/src.
+func projectName(repositoryName, ownerName string) string {
+	return ownerName + "/" + repositoryName
+}
+
+// GetRepositoryDetails returns the project ownerName/repositoryName. Gerrit has no stars, forks nor
+// issues.
+func (s *gerritService) GetRepositoryDetails(ctx context.Context, repositoryName, ownerName string) (*domain.Repository, error) {
+	project, err := s.client.GetProject(ctx, projectName(repositoryName, ownerName))
+	if err != nil {
+		return nil, err
+	}
+	head, err := s.client.GetHead(ctx, project.Name)
+	if err != nil {
+		return nil, err
+	}
+
+	owner, name := ownerName, repositoryName
+	if i := strings.LastIndex(project.Name, "/"); i >= 0 {
+		owner, name = project.Name[:i], project.Name[i+1:]
+	}
+	return &domain.Repository{
+		Provider:      domain.ProviderGerrit,
+		Name:          name,
+		OwnerName:     owner,
+		Description:   project.Description,
+		URL:           s.client.ProjectURL(project.Name),
+		DefaultBranch: head,
+	}, nil
+}
+
+// GetCommitsNew sends the changes merged into the branch set with domain.WithBranch, or else the
+// branch HEAD points to, as commits.
+func (s *gerritService) GetCommitsNew(ctx context.Context, repositoryName, ownerName string, since, until *time.Time, pageSize int, commitCh chan<- domain.Commit) error {
+	project := projectName(repositoryName, ownerName)
+	branch := strings.TrimPrefix(domain.BranchFromContext(ctx), "refs/heads/")
+	if branch == "" {
+		head, err := s.client.GetHead(ctx, project)
+		if err != nil {
+			return err
+		}
+		branch = head
+	}
+
+	tempCh := make(chan gerritapi.ChangeResponse, 200)
+	errCh := make(chan error, 1)
+	go func() {
+		defer close(tempCh)
+		errCh <- s.client.GetMergedChanges(ctx, project, branch, since, until, pageSize, tempCh)
+	}()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case change, ok := <-tempCh:
+			if !ok {
+				return <-errCh
+			}
+			commit, ok := convertToDomainCommit(change, s.client.ChangeURL(change))
+			if !ok {
+				s.logger.Warn("skipping merged change without its current revision", zap.Int("change", change.Number))
+				continue
+			}
+			commitCh <- commit
+		}
+	}
+}
+
+// convertToDomainCommit converts the current revision of a merged change to a domain.Commit, with
+// the review of the change. It returns false when the revision is missing from the change.
+func convertToDomainCommit(change gerritapi.ChangeResponse, url string) (domain.Commit, bool) {
+	revision, ok := change.Revisions[change.CurrentRevision]
+	if !ok {
+		return domain.Commit{}, false
+	}
+
+	now := time.Now()
+	parents := make([]string, 0, len(revision.Commit.Parents))
+	for _, p := range revision.Commit.Parents {
+		parents = append(parents, p.Commit)
+	}
+
+	// Gerrit lists the commit message among the files of a revision.
+	changedFiles := 0
+	for name := range revision.Files {
+		if !strings.HasPrefix(name, "/") {
+			changedFiles++
+		}
+	}
+
+	review := &domain.Review{
+		ChangeNumber: change.Number,
+		ChangeID:     change.ChangeID,
+		URL:          url,
+		Owner:        reviewAccount(change.Owner),
+		OpenedAt:     change.Created.Time,
+		Reviewers:    reviewers(change),
+	}
+	if change.Submitted != nil {
+		submitted := change.Submitted.Time
+		review.SubmittedAt = &submitted
+	}
+
+	return domain.Commit{
+		SHA:        change.CurrentRevision,
+		URL:        url,
+		Message:    revision.Commit.Message,
+		CommitDate: revision.Commit.Author.Date.Time,
+		CreatedAt:  now,
+		Author: domain.Author{
+			Name:  revision.Commit.Author.Name,
+			Email: revision.Commit.Author.Email,
+		},
+		Committer: &domain.Author{
+			Name:  revision.Commit.Committer.Name,
+			Email: revision.Commit.Committer.Email,
+		},
+		CommitterDate:  revision.Commit.Committer.Date.Time,
+		Parents:        parents,
+		Additions:      change.Insertions,
+		Deletions:      change.Deletions,
+		ChangedFiles:   changedFiles,
+		StatsFetchedAt: &now,
+		Review:         review,
+	}, true
+}
+
+func reviewAccount(account gerritapi.AccountInfo) domain.ReviewAccount {
+	name := account.Name
+	if name == "" {
+		name = account.Username
+	}
+	return domain.ReviewAccount{AccountID: account.AccountID, Name: name, Email: account.Email}
+}
+
+// reviewers returns the reviewers of a change other than its owner, with their Code-Review votes.
+// Accounts that voted without being listed as reviewers, e.g. removed since, are kept.
+func reviewers(change gerritapi.ChangeResponse) []domain.Reviewer {
+	byAccount := make(map[int]*domain.Reviewer)
+	add := func(account gerritapi.AccountInfo) *domain.Reviewer {
+		if account.AccountID == change.Owner.AccountID {
+			return nil
+		}
+		if r, ok := byAccount[account.AccountID]; ok {
+			return r
+		}
+		r := &domain.Reviewer{ReviewAccount: reviewAccount(account)}
+		byAccount[account.AccountID] = r
+		return r
+	}
+
+	for _, account := range change.Reviewers["REVIEWER"] {
+		add(account)
+	}
+	for _, approval := range change.Labels["Code-Review"].All {
+		if r := add(approval.AccountInfo); r != nil {
+			r.CodeReview = approval.Value
+		}
+	}
+
+	result := make([]domain.Reviewer, 0, len(byAccount))
+	for _, r := range byAccount {
+		result = append(result, *r)
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].AccountID < result[j].AccountID })
+	return result
+}
@@ -0,0 +1,100 @@
+package giteaservice
+
+import (
+	"context"
+	"time"
+
+	"github.com/babyfaceeasy/lema/internal/domain"
+	"github.com/babyfaceeasy/lema/internal/integrations/giteaapi"
+	"go.uber.org/zap"
+)
+
+type giteaService struct {
+	client *giteaapi.Client
+	logger *zap.Logger
+}
+
+// NewGiteaService creates a domain.Provider for the repositories of a Gitea or Forgejo instance.
+func NewGiteaService(client *giteaapi.Client, logger *zap.Logger) domain.Provider {
+	logger = logger.With(zap.String("package", "giteaservice"))
+	return &giteaService{
+		client: client,
+		logger: logger,
+	}
+}
+
+func (s *giteaService) GetRepositoryDetails(ctx context.Context, repositoryName, ownerName string) (*domain.Repository, error) {
+	repo, err := s.client.GetRepository(ctx, repositoryName, ownerName)
+	if err != nil {
+		return nil, err
+	}
+
+	return &domain.Repository{
+		Provider:            domain.ProviderGitea,
+		Name:                repo.Name,
+		OwnerName:           repo.Owner.Login,
+		Description:         repo.Description,
+		URL:                 repo.HTMLURL,
+		ProgrammingLanguage: repo.Language,
+		ForksCount:          repo.ForksCount,
+		StarsCount:          repo.StarsCount,
+		WatchersCount:       repo.WatchersCount,
+		OpenIssuesCount:     repo.OpenIssuesCount,
+		DefaultBranch:       repo.DefaultBranch,
+	}, nil
+}
+
+func (s *giteaService) GetCommitsNew(ctx context.Context, repositoryName, ownerName string, since, until *time.Time, pageSize int, commitCh chan<- domain.Commit) error {
+	tempCh := make(chan giteaapi.CommitResponse, 200)
+	errCh := make(chan error, 1)
+	go func() {
+		defer close(tempCh)
+		errCh <- s.client.GetCommits(ctx, repositoryName, ownerName, since, until, pageSize, tempCh)
+	}()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case cr, ok := <-tempCh:
+			if !ok {
+				return <-errCh
+			}
+			commitCh <- convertToDomainCommit(cr)
+		}
+	}
+}
+
+// convertToDomainCommit converts a giteaapi.CommitResponse to a domain.Commit.
+func convertToDomainCommit(cr giteaapi.CommitResponse) domain.Commit {
+	now := time.Now()
+	parents := make([]string, 0, len(cr.Parents))
+	for _, p := range cr.Parents {
+		parents = append(parents, p.SHA)
+	}
+
+	commit := domain.Commit{
+		SHA:        cr.SHA,
+		URL:        cr.HTMLURL,
+		Message:    cr.Commit.Message,
+		CommitDate: cr.Commit.Author.Date,
+		CreatedAt:  now,
+		Author: domain.Author{
+			Name:  cr.Commit.Author.Name,
+			Email: cr.Commit.Author.Email,
+		},
+		Committer: &domain.Author{
+			Name:  cr.Commit.Committer.Name,
+			Email: cr.Commit.Committer.Email,
+		},
+		CommitterDate: cr.Commit.Committer.Date,
+		Parents:       parents,
+	}
+	if cr.Stats != nil {
+		commit.Additions = cr.Stats.Additions
+		commit.Deletions = cr.Stats.Deletions
+		commit.ChangedFiles = len(cr.Files)
+		commit.StatsFetchedAt = &now
+	}
+	return commit
+}
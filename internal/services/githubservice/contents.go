@@ -0,0 +1,8 @@
+package githubservice
+
+import "context"
+
+// GetFileContents fetches a file of the default branch of a repository.
+func (s *githubService) GetFileContents(ctx context.Context, repositoryName, ownerName, path string) ([]byte, error) {
+	return s.client.GetFileContents(ctx, repositoryName, ownerName, path)
+}
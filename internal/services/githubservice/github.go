@@ -10,8 +10,16 @@ import (
 )
 
 type GitHubService interface {
-	GetRepositoryDetails(repositoryName, ownerName string) (*domain.Repository, error)
-	GetCommitsNew(ctx context.Context, repositoryName, ownerName string, since, until *time.Time, pageSize int, commitCh chan<- domain.Commit) error
+	domain.Provider
+	GetCommitStats(ctx context.Context, repositoryName, ownerName, sha string) (*domain.CommitStats, error)
+	GetRateLimit(ctx context.Context) (domain.RateLimit, error)
+	GetTokenHealth(ctx context.Context) ([]domain.TokenHealth, error)
+	GetPullRequests(ctx context.Context, repositoryName, ownerName string, since *time.Time, prCh chan<- domain.PullRequest) error
+	GetIssues(ctx context.Context, repositoryName, ownerName string, since *time.Time, issueCh chan<- domain.Issue) error
+	GetTags(ctx context.Context, repositoryName, ownerName string, tagCh chan<- domain.Tag) error
+	GetReleases(ctx context.Context, repositoryName, ownerName string, releaseCh chan<- domain.Release) error
+	GetStargazers(ctx context.Context, repositoryName, ownerName string, stargazerCh chan<- domain.Stargazer) error
+	GetFileContents(ctx context.Context, repositoryName, ownerName, path string) ([]byte, error)
 }
 
 type githubService struct {
@@ -28,14 +36,38 @@ func NewGithubService(client *githubapi.Client, logger *zap.Logger) GitHubServic
 	}
 }
 
+// WithConditionalRequests marks ctx so that GitHub calls made with it only download data that
+// changed since the previous call, returning domain.ErrNotModified otherwise.
+func WithConditionalRequests(ctx context.Context) context.Context {
+	return githubapi.WithConditionalRequests(ctx)
+}
+
+// SaveValidators caches the validators of the GitHub responses received with ctx, a context
+// returned by WithConditionalRequests. Call it once their data has been stored.
+func SaveValidators(ctx context.Context) error {
+	return githubapi.SaveValidators(ctx)
+}
+
+// WithBranch makes commit fetches made with ctx walk branch instead of the default branch.
+func WithBranch(ctx context.Context, branch string) context.Context {
+	return githubapi.WithBranch(ctx, branch)
+}
+
 // GetRepositoryDetails calls the underlying client's GetRepositoryDetails
-func (s *githubService) GetRepositoryDetails(repositoryName, ownerName string) (*domain.Repository, error) {
-	repoResp, err := s.client.GetRepositoryDetails(repositoryName, ownerName)
+func (s *githubService) GetRepositoryDetails(ctx context.Context, repositoryName, ownerName string) (*domain.Repository, error) {
+	repoResp, err := s.client.GetRepositoryDetailsWithContext(ctx, repositoryName, ownerName)
 	if err != nil {
 		return nil, err
 	}
 
+	state := domain.RepositoryStateActive
+	if repoResp.Archived {
+		state = domain.RepositoryStateArchived
+	}
+
 	domainRepo := domain.Repository{
+		Provider:            domain.ProviderGithub,
+		State:               state,
 		Name:                repoResp.Name,
 		OwnerName:           repoResp.Owner.Login,
 		Description:         repoResp.Description,
@@ -45,20 +77,47 @@ func (s *githubService) GetRepositoryDetails(repositoryName, ownerName string) (
 		StarsCount:          repoResp.StarsCount,
 		WatchersCount:       repoResp.WatchersCount,
 		OpenIssuesCount:     repoResp.OpenIssuesCount,
+		DefaultBranch:       repoResp.DefaultBranch,
+	}
+	if repoResp.ID != 0 {
+		domainRepo.GithubID = &repoResp.ID
 	}
 	return &domainRepo, nil
 }
 
+// GetCommitStats returns the line statistics of a single commit.
+func (s *githubService) GetCommitStats(ctx context.Context, repositoryName, ownerName, sha string) (*domain.CommitStats, error) {
+	commit, err := s.client.GetCommitDetails(ctx, repositoryName, ownerName, sha)
+	if err != nil {
+		return nil, err
+	}
+
+	stats := domain.CommitStats{ChangedFiles: len(commit.Files)}
+	if commit.Stats != nil {
+		stats.Additions = commit.Stats.Additions
+		stats.Deletions = commit.Stats.Deletions
+	}
+	return &stats, nil
+}
+
+// GetRateLimit returns the GitHub request budget currently available.
+func (s *githubService) GetRateLimit(ctx context.Context) (domain.RateLimit, error) {
+	return s.client.RateLimitBudget(ctx)
+}
+
+// GetTokenHealth returns the status of every configured GitHub token.
+func (s *githubService) GetTokenHealth(ctx context.Context) ([]domain.TokenHealth, error) {
+	return s.client.TokenHealth(ctx)
+}
+
 func (s *githubService) GetCommitsNew(ctx context.Context, repositoryName, ownerName string, since, until *time.Time, pageSize int, commitCh chan<- domain.Commit) error {
 	// Create a temporary channel for commit responses from the client.
 	tempCh := make(chan githubapi.CommitResponse, 200)
+	errCh := make(chan error, 1)
 	// Launch the client's GetCommitsNew concurrently.
 	go func() {
 		defer close(tempCh)
-		err := s.client.GetCommitsNew(ctx, repositoryName, ownerName, since, until, pageSize, tempCh)
-		if err != nil {
-			s.logger.Error("Error fetching commits", zap.Error(err))
-		}
+		errCh <- s.client.GetCommitsNew(ctx, repositoryName, ownerName, since, until, pageSize, tempCh)
 	}()
 
 	// Convert each githubapi.CommitResponse to domain.Commit and send it.
@@ -68,8 +127,8 @@ func (s *githubService) GetCommitsNew(ctx context.Context, repositoryName, owner
 			return ctx.Err()
 		case cr, ok := <-tempCh:
 			if !ok {
-				// Channel is closed; we're done.
-				return nil
+				// Channel is closed; report how the client finished.
+				return <-errCh
 			}
 			dc := convertToDomainCommit(cr)
 			commitCh <- dc
@@ -88,7 +147,14 @@ func convertToDomainCommit(cr githubapi.CommitResponse) domain.Commit {
 		Author: domain.Author{
 			Name:  cr.Commit.Author.Name,
 			Email: cr.Commit.Author.Email,
+			IsBot: cr.Author.IsBot(),
+		},
+		Committer: &domain.Author{
+			Name:  cr.Commit.Committer.Name,
+			Email: cr.Commit.Committer.Email,
+			IsBot: cr.Committer.IsBot(),
 		},
+		CommitterDate: cr.Commit.Committer.Date,
 		// The Repository field might be set later in the commit service.
 	}
 }
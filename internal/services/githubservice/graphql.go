@@ -0,0 +1,78 @@
+package githubservice
+
+import (
+	"context"
+	"time"
+
+	"github.com/babyfaceeasy/lema/internal/domain"
+	"github.com/babyfaceeasy/lema/internal/integrations/githubapi"
+	"go.uber.org/zap"
+)
+
+type githubGraphQLService struct {
+	*githubService
+}
+
+// NewGithubGraphQLService creates a GitHubService that fetches commit history through the
+// GraphQL API. Everything else is served by the REST API like NewGithubService.
+func NewGithubGraphQLService(client *githubapi.Client, logger *zap.Logger) GitHubService {
+	logger = logger.With(zap.String("package", "githubservice"), zap.String("commits_api", "graphql"))
+	return &githubGraphQLService{
+		githubService: &githubService{
+			client: client,
+			logger: logger,
+		},
+	}
+}
+
+func (s *githubGraphQLService) GetCommitsNew(ctx context.Context, repositoryName, ownerName string, since, until *time.Time, pageSize int, commitCh chan<- domain.Commit) error {
+	tempCh := make(chan githubapi.GraphQLCommit, 200)
+	errCh := make(chan error, 1)
+	go func() {
+		defer close(tempCh)
+		errCh <- s.client.GetCommitsGraphQL(ctx, repositoryName, ownerName, since, until, pageSize, tempCh)
+	}()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case gc, ok := <-tempCh:
+			if !ok {
+				return <-errCh
+			}
+			commitCh <- convertGraphQLCommit(gc)
+		}
+	}
+}
+
+// convertGraphQLCommit converts a githubapi.GraphQLCommit to a domain.Commit.
+func convertGraphQLCommit(gc githubapi.GraphQLCommit) domain.Commit {
+	now := time.Now()
+	parents := make([]string, 0, len(gc.Parents.Nodes))
+	for _, p := range gc.Parents.Nodes {
+		parents = append(parents, p.OID)
+	}
+
+	return domain.Commit{
+		SHA:        gc.OID,
+		URL:        gc.URL,
+		Message:    gc.Message,
+		CommitDate: gc.Author.Date,
+		CreatedAt:  now,
+		Author: domain.Author{
+			Name:  gc.Author.Name,
+			Email: gc.Author.Email,
+		},
+		Additions:      gc.Additions,
+		Deletions:      gc.Deletions,
+		ChangedFiles:   gc.ChangedFiles,
+		StatsFetchedAt: &now,
+		Committer: &domain.Author{
+			Name:  gc.Committer.Name,
+			Email: gc.Committer.Email,
+		},
+		CommitterDate: gc.Committer.Date,
+		Parents:       parents,
+	}
+}
@@ -0,0 +1,131 @@
+package githubservice
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/babyfaceeasy/lema/internal/domain"
+)
+
+// hostRouter serves every call with the provider of the host its context is scoped to.
+type hostRouter struct {
+	providers map[string]domain.Provider
+}
+
+// NewHostRouter creates a GitHubService that dispatches to providers by domain.HostFromContext,
+// so repositories of github.com, GitHub Enterprise Servers and GitLab instances can be synced side
+// by side. Calls beyond domain.Provider fail with domain.ErrUnsupportedByProvider on hosts whose
+// provider is not a GitHubService.
+func NewHostRouter(providers map[string]domain.Provider) GitHubService {
+	return &hostRouter{providers: providers}
+}
+
+func (r *hostRouter) provider(ctx context.Context) (domain.Provider, error) {
+	host := domain.HostFromContext(ctx)
+	p, ok := r.providers[host]
+	if !ok {
+		return nil, fmt.Errorf("no provider configured for host %s", host)
+	}
+	return p, nil
+}
+
+func (r *hostRouter) service(ctx context.Context) (GitHubService, error) {
+	p, err := r.provider(ctx)
+	if err != nil {
+		return nil, err
+	}
+	svc, ok := p.(GitHubService)
+	if !ok {
+		return nil, fmt.Errorf("%w: host %s is not served by GitHub", domain.ErrUnsupportedByProvider, domain.HostFromContext(ctx))
+	}
+	return svc, nil
+}
+
+func (r *hostRouter) GetRepositoryDetails(ctx context.Context, repositoryName, ownerName string) (*domain.Repository, error) {
+	svc, err := r.provider(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return svc.GetRepositoryDetails(ctx, repositoryName, ownerName)
+}
+
+func (r *hostRouter) GetCommitsNew(ctx context.Context, repositoryName, ownerName string, since, until *time.Time, pageSize int, commitCh chan<- domain.Commit) error {
+	svc, err := r.provider(ctx)
+	if err != nil {
+		return err
+	}
+	return svc.GetCommitsNew(ctx, repositoryName, ownerName, since, until, pageSize, commitCh)
+}
+
+func (r *hostRouter) GetCommitStats(ctx context.Context, repositoryName, ownerName, sha string) (*domain.CommitStats, error) {
+	svc, err := r.service(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return svc.GetCommitStats(ctx, repositoryName, ownerName, sha)
+}
+
+func (r *hostRouter) GetRateLimit(ctx context.Context) (domain.RateLimit, error) {
+	svc, err := r.service(ctx)
+	if err != nil {
+		return domain.RateLimit{}, err
+	}
+	return svc.GetRateLimit(ctx)
+}
+
+func (r *hostRouter) GetTokenHealth(ctx context.Context) ([]domain.TokenHealth, error) {
+	svc, err := r.service(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return svc.GetTokenHealth(ctx)
+}
+
+func (r *hostRouter) GetPullRequests(ctx context.Context, repositoryName, ownerName string, since *time.Time, prCh chan<- domain.PullRequest) error {
+	svc, err := r.service(ctx)
+	if err != nil {
+		return err
+	}
+	return svc.GetPullRequests(ctx, repositoryName, ownerName, since, prCh)
+}
+
+func (r *hostRouter) GetIssues(ctx context.Context, repositoryName, ownerName string, since *time.Time, issueCh chan<- domain.Issue) error {
+	svc, err := r.service(ctx)
+	if err != nil {
+		return err
+	}
+	return svc.GetIssues(ctx, repositoryName, ownerName, since, issueCh)
+}
+
+func (r *hostRouter) GetTags(ctx context.Context, repositoryName, ownerName string, tagCh chan<- domain.Tag) error {
+	svc, err := r.service(ctx)
+	if err != nil {
+		return err
+	}
+	return svc.GetTags(ctx, repositoryName, ownerName, tagCh)
+}
+
+func (r *hostRouter) GetReleases(ctx context.Context, repositoryName, ownerName string, releaseCh chan<- domain.Release) error {
+	svc, err := r.service(ctx)
+	if err != nil {
+		return err
+	}
+	return svc.GetReleases(ctx, repositoryName, ownerName, releaseCh)
+}
+
+func (r *hostRouter) GetStargazers(ctx context.Context, repositoryName, ownerName string, stargazerCh chan<- domain.Stargazer) error {
+	svc, err := r.service(ctx)
+	if err != nil {
+		return err
+	}
+	return svc.GetStargazers(ctx, repositoryName, ownerName, stargazerCh)
+}
+
+func (r *hostRouter) GetFileContents(ctx context.Context, repositoryName, ownerName, path string) ([]byte, error) {
+	svc, err := r.service(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return svc.GetFileContents(ctx, repositoryName, ownerName, path)
+}
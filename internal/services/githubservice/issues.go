@@ -0,0 +1,57 @@
+package githubservice
+
+import (
+	"context"
+	"time"
+
+	"github.com/babyfaceeasy/lema/internal/domain"
+	"github.com/babyfaceeasy/lema/internal/integrations/githubapi"
+)
+
+// GetIssues streams the issues of a repository updated since since, or all of them when since is nil.
+func (s *githubService) GetIssues(ctx context.Context, repositoryName, ownerName string, since *time.Time, issueCh chan<- domain.Issue) error {
+	tempCh := make(chan githubapi.IssueResponse, 100)
+	errCh := make(chan error, 1)
+	go func() {
+		defer close(tempCh)
+		errCh <- s.client.GetIssues(ctx, repositoryName, ownerName, since, 100, tempCh)
+	}()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case issue, ok := <-tempCh:
+			if !ok {
+				return <-errCh
+			}
+			issueCh <- convertToDomainIssue(issue)
+		}
+	}
+}
+
+// convertToDomainIssue converts a githubapi.IssueResponse to a domain.Issue.
+func convertToDomainIssue(issue githubapi.IssueResponse) domain.Issue {
+	labels := make([]string, 0, len(issue.Labels))
+	for _, label := range issue.Labels {
+		labels = append(labels, label.Name)
+	}
+
+	state := domain.IssueStateOpen
+	if issue.State == "closed" {
+		state = domain.IssueStateClosed
+	}
+
+	return domain.Issue{
+		Number:      issue.Number,
+		Title:       issue.Title,
+		State:       state,
+		Labels:      labels,
+		AuthorLogin: issue.User.Login,
+		URL:         issue.HTMLURL,
+		OpenedAt:    issue.CreatedAt,
+		UpdatedAt:   issue.UpdatedAt,
+		ClosedAt:    issue.ClosedAt,
+		CreatedAt:   time.Now(),
+	}
+}
@@ -0,0 +1,60 @@
+package githubservice
+
+import (
+	"context"
+	"time"
+
+	"github.com/babyfaceeasy/lema/internal/domain"
+	"github.com/babyfaceeasy/lema/internal/integrations/githubapi"
+)
+
+// GetPullRequests streams the pull requests of a repository updated after since, or all of them
+// when since is nil, most recently updated first.
+func (s *githubService) GetPullRequests(ctx context.Context, repositoryName, ownerName string, since *time.Time, prCh chan<- domain.PullRequest) error {
+	tempCh := make(chan githubapi.PullRequestResponse, 100)
+	errCh := make(chan error, 1)
+	go func() {
+		defer close(tempCh)
+		errCh <- s.client.GetPullRequests(ctx, repositoryName, ownerName, since, 100, tempCh)
+	}()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case pr, ok := <-tempCh:
+			if !ok {
+				return <-errCh
+			}
+			prCh <- convertToDomainPullRequest(pr)
+		}
+	}
+}
+
+// convertToDomainPullRequest converts a githubapi.PullRequestResponse to a domain.PullRequest.
+// GitHub reports merged pull requests as closed, they get their own state here.
+func convertToDomainPullRequest(pr githubapi.PullRequestResponse) domain.PullRequest {
+	state := domain.PullRequestStateOpen
+	switch {
+	case pr.MergedAt != nil:
+		state = domain.PullRequestStateMerged
+	case pr.State == "closed":
+		state = domain.PullRequestStateClosed
+	}
+
+	return domain.PullRequest{
+		Number:         pr.Number,
+		Title:          pr.Title,
+		State:          state,
+		AuthorLogin:    pr.User.Login,
+		URL:            pr.HTMLURL,
+		BaseBranch:     pr.Base.Ref,
+		HeadBranch:     pr.Head.Ref,
+		MergeCommitSHA: pr.MergeCommitSHA,
+		OpenedAt:       pr.CreatedAt,
+		UpdatedAt:      pr.UpdatedAt,
+		ClosedAt:       pr.ClosedAt,
+		MergedAt:       pr.MergedAt,
+		CreatedAt:      time.Now(),
+	}
+}
@@ -0,0 +1,73 @@
+package githubservice
+
+import (
+	"context"
+	"time"
+
+	"github.com/babyfaceeasy/lema/internal/domain"
+	"github.com/babyfaceeasy/lema/internal/integrations/githubapi"
+)
+
+// GetTags streams every tag of a repository with the SHA it points at.
+func (s *githubService) GetTags(ctx context.Context, repositoryName, ownerName string, tagCh chan<- domain.Tag) error {
+	tempCh := make(chan githubapi.TagResponse, 100)
+	errCh := make(chan error, 1)
+	go func() {
+		defer close(tempCh)
+		errCh <- s.client.GetTags(ctx, repositoryName, ownerName, 100, tempCh)
+	}()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case tag, ok := <-tempCh:
+			if !ok {
+				return <-errCh
+			}
+			tagCh <- domain.Tag{Name: tag.Name, SHA: tag.Commit.SHA, CreatedAt: time.Now()}
+		}
+	}
+}
+
+// GetReleases streams every release of a repository.
+func (s *githubService) GetReleases(ctx context.Context, repositoryName, ownerName string, releaseCh chan<- domain.Release) error {
+	tempCh := make(chan githubapi.ReleaseResponse, 100)
+	errCh := make(chan error, 1)
+	go func() {
+		defer close(tempCh)
+		errCh <- s.client.GetReleases(ctx, repositoryName, ownerName, 100, tempCh)
+	}()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case release, ok := <-tempCh:
+			if !ok {
+				return <-errCh
+			}
+			releaseCh <- convertToDomainRelease(release)
+		}
+	}
+}
+
+// convertToDomainRelease converts a githubapi.ReleaseResponse to a domain.Release.
+func convertToDomainRelease(release githubapi.ReleaseResponse) domain.Release {
+	name := release.Name
+	if name == "" {
+		name = release.TagName
+	}
+
+	return domain.Release{
+		GithubID:    release.ID,
+		TagName:     release.TagName,
+		Name:        name,
+		Draft:       release.Draft,
+		Prerelease:  release.Prerelease,
+		AuthorLogin: release.Author.Login,
+		URL:         release.HTMLURL,
+		PublishedAt: release.PublishedAt,
+		CreatedAt:   time.Now(),
+	}
+}
@@ -0,0 +1,30 @@
+package githubservice
+
+import (
+	"context"
+
+	"github.com/babyfaceeasy/lema/internal/domain"
+	"github.com/babyfaceeasy/lema/internal/integrations/githubapi"
+)
+
+// GetStargazers streams the current stargazers of a repository with when they starred it.
+func (s *githubService) GetStargazers(ctx context.Context, repositoryName, ownerName string, stargazerCh chan<- domain.Stargazer) error {
+	tempCh := make(chan githubapi.StargazerResponse, 100)
+	errCh := make(chan error, 1)
+	go func() {
+		defer close(tempCh)
+		errCh <- s.client.GetStargazers(ctx, repositoryName, ownerName, 100, tempCh)
+	}()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case stargazer, ok := <-tempCh:
+			if !ok {
+				return <-errCh
+			}
+			stargazerCh <- domain.Stargazer{Login: stargazer.User.Login, StarredAt: stargazer.StarredAt}
+		}
+	}
+}
@@ -0,0 +1,94 @@
+package gitlabservice
+
+import (
+	"context"
+	"time"
+
+	"github.com/babyfaceeasy/lema/internal/domain"
+	"github.com/babyfaceeasy/lema/internal/integrations/gitlabapi"
+	"go.uber.org/zap"
+)
+
+type gitlabService struct {
+	client *gitlabapi.Client
+	logger *zap.Logger
+}
+
+// NewGitlabService creates a domain.Provider for the projects of a GitLab instance.
+func NewGitlabService(client *gitlabapi.Client, logger *zap.Logger) domain.Provider {
+	logger = logger.With(zap.String("package", "gitlabservice"))
+	return &gitlabService{
+		client: client,
+		logger: logger,
+	}
+}
+
+// GetRepositoryDetails returns the project at ownerName/repositoryName, ownerName being its namespace.
+func (s *gitlabService) GetRepositoryDetails(ctx context.Context, repositoryName, ownerName string) (*domain.Repository, error) {
+	project, err := s.client.GetProject(ctx, repositoryName, ownerName)
+	if err != nil {
+		return nil, err
+	}
+
+	return &domain.Repository{
+		Provider:        domain.ProviderGitlab,
+		Name:            project.Path,
+		OwnerName:       project.Namespace.FullPath,
+		Description:     project.Description,
+		URL:             project.WebURL,
+		ForksCount:      project.ForksCount,
+		StarsCount:      project.StarCount,
+		OpenIssuesCount: project.OpenIssuesCount,
+		DefaultBranch:   project.DefaultBranch,
+	}, nil
+}
+
+func (s *gitlabService) GetCommitsNew(ctx context.Context, repositoryName, ownerName string, since, until *time.Time, pageSize int, commitCh chan<- domain.Commit) error {
+	tempCh := make(chan gitlabapi.CommitResponse, 200)
+	errCh := make(chan error, 1)
+	go func() {
+		defer close(tempCh)
+		errCh <- s.client.GetCommits(ctx, repositoryName, ownerName, since, until, pageSize, tempCh)
+	}()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case cr, ok := <-tempCh:
+			if !ok {
+				return <-errCh
+			}
+			commitCh <- convertToDomainCommit(cr)
+		}
+	}
+}
+
+// convertToDomainCommit converts a gitlabapi.CommitResponse to a domain.Commit. GitLab does not
+// count changed files in its commit statistics.
+func convertToDomainCommit(cr gitlabapi.CommitResponse) domain.Commit {
+	now := time.Now()
+	commit := domain.Commit{
+		SHA:        cr.ID,
+		URL:        cr.WebURL,
+		Message:    cr.Message,
+		CommitDate: cr.AuthoredDate,
+		CreatedAt:  now,
+		Author: domain.Author{
+			Name:  cr.AuthorName,
+			Email: cr.AuthorEmail,
+		},
+		Committer: &domain.Author{
+			Name:  cr.CommitterName,
+			Email: cr.CommitterEmail,
+		},
+		CommitterDate: cr.CommittedDate,
+		Parents:       cr.ParentIDs,
+	}
+	if cr.Stats != nil {
+		commit.Additions = cr.Stats.Additions
+		commit.Deletions = cr.Stats.Deletions
+		commit.StatsFetchedAt = &now
+	}
+	return commit
+}
@@ -0,0 +1,125 @@
+package issueservice
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/babyfaceeasy/lema/internal/domain"
+	"github.com/babyfaceeasy/lema/internal/repositories"
+	"github.com/babyfaceeasy/lema/internal/services/githubservice"
+	"github.com/babyfaceeasy/lema/pkg/pagination"
+	"go.uber.org/zap"
+)
+
+type issueService struct {
+	githubService     githubservice.GitHubService
+	logger            *zap.Logger
+	issueRepo         repositories.IssueRepository
+	repositoryService domain.RepositoryService
+}
+
+func NewIssueService(gitHubService githubservice.GitHubService, issueRepo repositories.IssueRepository, logger *zap.Logger, repoSvc domain.RepositoryService) domain.IssueService {
+	logger = logger.With(zap.String("package", "issueservice"))
+	return &issueService{
+		githubService:     gitHubService,
+		issueRepo:         issueRepo,
+		logger:            logger,
+		repositoryService: repoSvc,
+	}
+}
+
+func (is *issueService) repository(ctx context.Context, ownerName, repoName string) (*domain.Repository, error) {
+	repoDetails, err := is.repositoryService.GetRepository(ctx, ownerName, repoName)
+	if err != nil {
+		return nil, err
+	}
+	if repoDetails == nil {
+		return nil, fmt.Errorf("repository %s/%s does not exist in our system", ownerName, repoName)
+	}
+	return repoDetails, nil
+}
+
+// SyncIssues stores the issues of a repository updated since the last sync.
+func (is *issueService) SyncIssues(ctx context.Context, ownerName, repoName string) error {
+	logr := is.logger.With(zap.String("method", "SyncIssues"))
+
+	repoDetails, err := is.repository(ctx, ownerName, repoName)
+	if err != nil {
+		return err
+	}
+	if !repoDetails.HostedOnGithub() {
+		logr.Info("Skipping issues, only synced for GitHub repositories", zap.String("repo_name", repoDetails.Name), zap.String("provider", repoDetails.Provider))
+		return nil
+	}
+
+	since, err := is.issueRepo.LatestUpdatedAt(ctx, repoDetails.ID)
+	if err != nil {
+		return err
+	}
+
+	issueCh := make(chan domain.Issue, 100)
+	errCh := make(chan error, 1)
+	go func() {
+		defer close(issueCh)
+		errCh <- is.githubService.GetIssues(ctx, repoDetails.Name, repoDetails.OwnerName, since, issueCh)
+	}()
+
+	var issues []domain.Issue
+	batchSize := 50
+	count := 0
+
+	for {
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("context canceled while processing issues")
+		case issue, ok := <-issueCh:
+			if !ok {
+				if err := <-errCh; err != nil {
+					return fmt.Errorf("failed to fetch issues: %w", err)
+				}
+				if err := is.issueRepo.UpsertIssues(ctx, issues); err != nil {
+					return fmt.Errorf("failed to upsert remaining issues: %w", err)
+				}
+				logr.Info("Synced issues", zap.String("repo_name", repoDetails.Name), zap.Int("count", count))
+				return nil
+			}
+
+			count++
+			issue.RepositoryID = repoDetails.ID
+			issues = append(issues, issue)
+			if len(issues) >= batchSize {
+				if err := is.issueRepo.UpsertIssues(ctx, issues); err != nil {
+					return fmt.Errorf("failed to upsert issue batch: %w", err)
+				}
+				issues = issues[:0]
+			}
+		}
+	}
+}
+
+func (is *issueService) GetIssues(ctx context.Context, ownerName, repoName string, filter domain.IssueFilter, page, pageSize int) ([]domain.Issue, *pagination.Pagination, error) {
+	logr := is.logger.With(zap.String("method", "GetIssues"))
+
+	repoDetails, err := is.repository(ctx, ownerName, repoName)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	issues, totalItems, err := is.issueRepo.GetIssues(ctx, repoDetails.ID, filter, page, pageSize)
+	if err != nil {
+		logr.Error("error in GetIssues", zap.Error(err))
+		return nil, nil, err
+	}
+
+	return issues, pagination.NewPagination(page, pageSize, totalItems), nil
+}
+
+// GetCommitIssues returns the issues referenced by the message of a stored commit.
+func (is *issueService) GetCommitIssues(ctx context.Context, ownerName, repoName, sha string) ([]domain.IssueReference, error) {
+	repoDetails, err := is.repository(ctx, ownerName, repoName)
+	if err != nil {
+		return nil, err
+	}
+
+	return is.issueRepo.GetReferencedIssues(ctx, repoDetails.ID, sha)
+}
@@ -0,0 +1,90 @@
+package localgitservice
+
+import (
+	"context"
+	"time"
+
+	"github.com/babyfaceeasy/lema/internal/domain"
+	"github.com/babyfaceeasy/lema/internal/integrations/localgit"
+	"go.uber.org/zap"
+)
+
+type localGitService struct {
+	client *localgit.Client
+	logger *zap.Logger
+}
+
+// NewLocalGitService creates a domain.Provider for the clones and bare mirrors read by client.
+func NewLocalGitService(client *localgit.Client, logger *zap.Logger) domain.Provider {
+	logger = logger.With(zap.String("package", "localgitservice"))
+	return &localGitService{
+		client: client,
+		logger: logger,
+	}
+}
+
+// GetRepositoryDetails returns the local repository ownerName/repositoryName. Its URL is its path.
+func (s *localGitService) GetRepositoryDetails(ctx context.Context, repositoryName, ownerName string) (*domain.Repository, error) {
+	repo, err := s.client.GetRepository(ctx, repositoryName, ownerName)
+	if err != nil {
+		return nil, err
+	}
+
+	return &domain.Repository{
+		Provider:      domain.ProviderLocal,
+		Name:          repositoryName,
+		OwnerName:     ownerName,
+		Description:   repo.Description,
+		URL:           "file://" + repo.Path,
+		DefaultBranch: repo.DefaultBranch,
+	}, nil
+}
+
+// GetCommitsNew walks the branch set with domain.WithBranch, or HEAD, from the commit set with
+// domain.WithSyncedSHA when there is one, and from since otherwise.
+func (s *localGitService) GetCommitsNew(ctx context.Context, repositoryName, ownerName string, since, until *time.Time, pageSize int, commitCh chan<- domain.Commit) error {
+	tempCh := make(chan localgit.CommitResponse, 200)
+	errCh := make(chan error, 1)
+	go func() {
+		defer close(tempCh)
+		errCh <- s.client.GetCommits(ctx, repositoryName, ownerName, domain.BranchFromContext(ctx), domain.SyncedSHAFromContext(ctx), since, until, tempCh)
+	}()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case cr, ok := <-tempCh:
+			if !ok {
+				return <-errCh
+			}
+			commitCh <- convertToDomainCommit(cr)
+		}
+	}
+}
+
+// convertToDomainCommit converts a localgit.CommitResponse to a domain.Commit. Local commits have
+// no web page, so their URL is left empty.
+func convertToDomainCommit(cr localgit.CommitResponse) domain.Commit {
+	now := time.Now()
+	return domain.Commit{
+		SHA:        cr.SHA,
+		Message:    cr.Message,
+		CommitDate: cr.Author.Date,
+		CreatedAt:  now,
+		Author: domain.Author{
+			Name:  cr.Author.Name,
+			Email: cr.Author.Email,
+		},
+		Committer: &domain.Author{
+			Name:  cr.Committer.Name,
+			Email: cr.Committer.Email,
+		},
+		CommitterDate:  cr.Committer.Date,
+		Parents:        cr.Parents,
+		Additions:      cr.Additions,
+		Deletions:      cr.Deletions,
+		ChangedFiles:   cr.ChangedFiles,
+		StatsFetchedAt: &now,
+	}
+}
@@ -0,0 +1,152 @@
+package popularityservice
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/babyfaceeasy/lema/internal/domain"
+	"github.com/babyfaceeasy/lema/internal/repositories"
+	"github.com/babyfaceeasy/lema/internal/services/githubservice"
+	"go.uber.org/zap"
+)
+
+type popularityService struct {
+	githubService     githubservice.GitHubService
+	logger            *zap.Logger
+	popularityRepo    repositories.PopularityRepository
+	repositoryService domain.RepositoryService
+}
+
+func NewPopularityService(gitHubService githubservice.GitHubService, popularityRepo repositories.PopularityRepository, logger *zap.Logger, repoSvc domain.RepositoryService) domain.PopularityService {
+	logger = logger.With(zap.String("package", "popularityservice"))
+	return &popularityService{
+		githubService:     gitHubService,
+		popularityRepo:    popularityRepo,
+		logger:            logger,
+		repositoryService: repoSvc,
+	}
+}
+
+func (ps *popularityService) repository(ctx context.Context, ownerName, repoName string) (*domain.Repository, error) {
+	repoDetails, err := ps.repositoryService.GetRepository(ctx, ownerName, repoName)
+	if err != nil {
+		return nil, err
+	}
+	if repoDetails == nil {
+		return nil, fmt.Errorf("repository %s/%s does not exist in our system", ownerName, repoName)
+	}
+	return repoDetails, nil
+}
+
+// SnapshotRepository refreshes the counters of a repository from its provider and records them in
+// its popularity history.
+func (ps *popularityService) SnapshotRepository(ctx context.Context, ownerName, repoName string) error {
+	logr := ps.logger.With(zap.String("method", "SnapshotRepository"))
+
+	repoDetails, err := ps.repositoryService.RefreshRepository(ctx, ownerName, repoName)
+	if err != nil {
+		return err
+	}
+	if repoDetails.State == domain.RepositoryStateDeleted {
+		logr.Info("Repository deleted, no snapshot taken", zap.String("repo_name", repoDetails.Name))
+		return nil
+	}
+
+	snapshot := domain.RepositorySnapshot{
+		RepositoryID:    repoDetails.ID,
+		Source:          domain.SnapshotSourceSnapshot,
+		StarsCount:      &repoDetails.StarsCount,
+		ForksCount:      &repoDetails.ForksCount,
+		WatchersCount:   &repoDetails.WatchersCount,
+		OpenIssuesCount: &repoDetails.OpenIssuesCount,
+		RecordedAt:      time.Now(),
+	}
+	if err := ps.popularityRepo.SaveSnapshot(ctx, snapshot); err != nil {
+		return err
+	}
+
+	logr.Info("Snapshot taken", zap.String("repo_name", repoDetails.Name), zap.Int("stars_count", repoDetails.StarsCount))
+	return nil
+}
+
+// BackfillStarHistory rebuilds the star history of a GitHub repository from when its stargazers
+// starred it, with one point per day, and returns how many stargazers were read. Users who
+// unstarred the repository are not listed, so the history is a lower bound.
+func (ps *popularityService) BackfillStarHistory(ctx context.Context, ownerName, repoName string) (int, error) {
+	logr := ps.logger.With(zap.String("method", "BackfillStarHistory"))
+
+	repoDetails, err := ps.repository(ctx, ownerName, repoName)
+	if err != nil {
+		return 0, err
+	}
+	if !repoDetails.HostedOnGithub() {
+		return 0, fmt.Errorf("%w: star history is only backfilled for GitHub repositories", domain.ErrUnsupportedByProvider)
+	}
+
+	stargazerCh := make(chan domain.Stargazer, 100)
+	errCh := make(chan error, 1)
+	go func() {
+		defer close(stargazerCh)
+		errCh <- ps.githubService.GetStargazers(ctx, repoDetails.Name, repoDetails.OwnerName, stargazerCh)
+	}()
+
+	var starredAt []time.Time
+	for stargazer := range stargazerCh {
+		starredAt = append(starredAt, stargazer.StarredAt)
+	}
+	if err := <-errCh; err != nil {
+		return 0, fmt.Errorf("failed to fetch stargazers: %w", err)
+	}
+
+	snapshots := starHistory(starredAt)
+	if err := ps.popularityRepo.ReplaceStarHistory(ctx, repoDetails.ID, snapshots); err != nil {
+		return 0, err
+	}
+
+	logr.Info("Star history backfilled", zap.String("repo_name", repoDetails.Name), zap.Int("stargazers", len(starredAt)), zap.Int("days", len(snapshots)))
+	return len(starredAt), nil
+}
+
+// starHistory turns the times stars were given into the star count at the last star of every day.
+func starHistory(starredAt []time.Time) []domain.RepositorySnapshot {
+	sort.Slice(starredAt, func(i, j int) bool { return starredAt[i].Before(starredAt[j]) })
+
+	var snapshots []domain.RepositorySnapshot
+	for i, at := range starredAt {
+		stars := i + 1
+		snapshot := domain.RepositorySnapshot{
+			Source:     domain.SnapshotSourceStargazers,
+			StarsCount: &stars,
+			RecordedAt: at,
+		}
+
+		last := len(snapshots) - 1
+		if last >= 0 && sameDay(snapshots[last].RecordedAt, at) {
+			snapshots[last] = snapshot
+			continue
+		}
+		snapshots = append(snapshots, snapshot)
+	}
+	return snapshots
+}
+
+func sameDay(a, b time.Time) bool {
+	ay, am, ad := a.UTC().Date()
+	by, bm, bd := b.UTC().Date()
+	return ay == by && am == bm && ad == bd
+}
+
+// GetPopularityHistory returns the popularity history of a repository bucketed by day or week.
+func (ps *popularityService) GetPopularityHistory(ctx context.Context, ownerName, repoName string, filter domain.PopularityFilter) ([]domain.PopularityPoint, error) {
+	repoDetails, err := ps.repository(ctx, ownerName, repoName)
+	if err != nil {
+		return nil, err
+	}
+
+	if filter.Interval == "" {
+		filter.Interval = domain.PopularityIntervalDay
+	}
+	return ps.popularityRepo.GetHistory(ctx, repoDetails.ID, filter)
+}
@@ -0,0 +1,143 @@
+package pullrequestservice
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/babyfaceeasy/lema/internal/domain"
+	"github.com/babyfaceeasy/lema/internal/repositories"
+	"github.com/babyfaceeasy/lema/internal/services/githubservice"
+	"github.com/babyfaceeasy/lema/pkg/pagination"
+	"go.uber.org/zap"
+)
+
+type pullRequestService struct {
+	githubService     githubservice.GitHubService
+	logger            *zap.Logger
+	pullRequestRepo   repositories.PullRequestRepository
+	repositoryService domain.RepositoryService
+}
+
+func NewPullRequestService(gitHubService githubservice.GitHubService, pullRequestRepo repositories.PullRequestRepository, logger *zap.Logger, repoSvc domain.RepositoryService) domain.PullRequestService {
+	logger = logger.With(zap.String("package", "pullrequestservice"))
+	return &pullRequestService{
+		githubService:     gitHubService,
+		pullRequestRepo:   pullRequestRepo,
+		logger:            logger,
+		repositoryService: repoSvc,
+	}
+}
+
+func (ps *pullRequestService) repository(ctx context.Context, ownerName, repoName string) (*domain.Repository, error) {
+	repoDetails, err := ps.repositoryService.GetRepository(ctx, ownerName, repoName)
+	if err != nil {
+		return nil, err
+	}
+	if repoDetails == nil {
+		return nil, fmt.Errorf("repository %s/%s does not exist in our system", ownerName, repoName)
+	}
+	return repoDetails, nil
+}
+
+// SyncPullRequests stores the pull requests of a repository updated since the last sync and links
+// merged ones to their merge commit. GitHub lists them most recently updated first, so the
+// watermark only moves once a sync completed: a sync failing midway is started over.
+func (ps *pullRequestService) SyncPullRequests(ctx context.Context, ownerName, repoName string) error {
+	logr := ps.logger.With(zap.String("method", "SyncPullRequests"))
+
+	repoDetails, err := ps.repository(ctx, ownerName, repoName)
+	if err != nil {
+		return err
+	}
+	if !repoDetails.HostedOnGithub() {
+		logr.Info("Skipping pull requests, only synced for GitHub repositories", zap.String("repo_name", repoDetails.Name), zap.String("provider", repoDetails.Provider))
+		return nil
+	}
+
+	since, err := ps.pullRequestRepo.SyncedUntil(ctx, repoDetails.ID)
+	if err != nil {
+		return err
+	}
+
+	prCh := make(chan domain.PullRequest, 100)
+	errCh := make(chan error, 1)
+	go func() {
+		defer close(prCh)
+		errCh <- ps.githubService.GetPullRequests(ctx, repoDetails.Name, repoDetails.OwnerName, since, prCh)
+	}()
+
+	var pullRequests []domain.PullRequest
+	var latest *time.Time
+	batchSize := 50
+	count := 0
+
+	for {
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("context canceled while processing pull requests")
+		case pr, ok := <-prCh:
+			if !ok {
+				if err := <-errCh; err != nil {
+					return fmt.Errorf("failed to fetch pull requests: %w", err)
+				}
+				if err := ps.pullRequestRepo.UpsertPullRequests(ctx, pullRequests); err != nil {
+					return fmt.Errorf("failed to upsert remaining pull requests: %w", err)
+				}
+				if latest != nil {
+					if err := ps.pullRequestRepo.UpdateSyncedUntil(ctx, repoDetails.ID, *latest); err != nil {
+						return err
+					}
+				}
+
+				// merge commits fetched after their pull request are linked here.
+				linked, err := ps.pullRequestRepo.LinkMergeCommits(ctx, repoDetails.ID)
+				if err != nil {
+					return err
+				}
+
+				logr.Info("Synced pull requests", zap.String("repo_name", repoDetails.Name), zap.Int("count", count), zap.Int("linked", linked))
+				return nil
+			}
+
+			count++
+			if latest == nil || pr.UpdatedAt.After(*latest) {
+				latest = &pr.UpdatedAt
+			}
+			pr.RepositoryID = repoDetails.ID
+			pullRequests = append(pullRequests, pr)
+			if len(pullRequests) >= batchSize {
+				if err := ps.pullRequestRepo.UpsertPullRequests(ctx, pullRequests); err != nil {
+					return fmt.Errorf("failed to upsert pull request batch: %w", err)
+				}
+				pullRequests = pullRequests[:0]
+			}
+		}
+	}
+}
+
+func (ps *pullRequestService) GetPullRequests(ctx context.Context, ownerName, repoName string, filter domain.PullRequestFilter, page, pageSize int) ([]domain.PullRequest, *pagination.Pagination, error) {
+	logr := ps.logger.With(zap.String("method", "GetPullRequests"))
+
+	repoDetails, err := ps.repository(ctx, ownerName, repoName)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	pullRequests, totalItems, err := ps.pullRequestRepo.GetPullRequests(ctx, repoDetails.ID, filter, page, pageSize)
+	if err != nil {
+		logr.Error("error in GetPullRequests", zap.Error(err))
+		return nil, nil, err
+	}
+
+	return pullRequests, pagination.NewPagination(page, pageSize, totalItems), nil
+}
+
+func (ps *pullRequestService) GetPullRequestStats(ctx context.Context, ownerName, repoName string, filter domain.PullRequestFilter) (*domain.PullRequestStats, error) {
+	repoDetails, err := ps.repository(ctx, ownerName, repoName)
+	if err != nil {
+		return nil, err
+	}
+
+	return ps.pullRequestRepo.GetPullRequestStats(ctx, repoDetails.ID, filter)
+}
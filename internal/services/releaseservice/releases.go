@@ -0,0 +1,184 @@
+package releaseservice
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/babyfaceeasy/lema/internal/domain"
+	"github.com/babyfaceeasy/lema/internal/repositories"
+	"github.com/babyfaceeasy/lema/internal/services/githubservice"
+	"github.com/babyfaceeasy/lema/pkg/pagination"
+	"go.uber.org/zap"
+)
+
+type releaseService struct {
+	githubService     githubservice.GitHubService
+	logger            *zap.Logger
+	releaseRepo       repositories.ReleaseRepository
+	repositoryService domain.RepositoryService
+}
+
+func NewReleaseService(gitHubService githubservice.GitHubService, releaseRepo repositories.ReleaseRepository, logger *zap.Logger, repoSvc domain.RepositoryService) domain.ReleaseService {
+	logger = logger.With(zap.String("package", "releaseservice"))
+	return &releaseService{
+		githubService:     gitHubService,
+		releaseRepo:       releaseRepo,
+		logger:            logger,
+		repositoryService: repoSvc,
+	}
+}
+
+func (rs *releaseService) repository(ctx context.Context, ownerName, repoName string) (*domain.Repository, error) {
+	repoDetails, err := rs.repositoryService.GetRepository(ctx, ownerName, repoName)
+	if err != nil {
+		return nil, err
+	}
+	if repoDetails == nil {
+		return nil, fmt.Errorf("repository %s/%s does not exist in our system", ownerName, repoName)
+	}
+	return repoDetails, nil
+}
+
+// SyncReleases stores the tags and releases of a repository. GitHub lists the newest releases
+// first, so the first page of releases is requested conditionally and neither tags nor releases
+// are fetched when it has not changed since the last sync. Tags are stored first so releases can
+// resolve their target commit through them.
+func (rs *releaseService) SyncReleases(ctx context.Context, ownerName, repoName string) error {
+	logr := rs.logger.With(zap.String("method", "SyncReleases"))
+
+	repoDetails, err := rs.repository(ctx, ownerName, repoName)
+	if err != nil {
+		return err
+	}
+	if !repoDetails.HostedOnGithub() {
+		logr.Info("Skipping releases, only synced for GitHub repositories", zap.String("repo_name", repoDetails.Name), zap.String("provider", repoDetails.Provider))
+		return nil
+	}
+
+	fetchCtx := githubservice.WithConditionalRequests(ctx)
+	releases, err := rs.fetchReleases(fetchCtx, repoDetails)
+	if errors.Is(err, domain.ErrNotModified) {
+		logr.Info("No new releases since last sync", zap.String("repo_name", repoDetails.Name))
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	tagCount, err := rs.syncTags(ctx, repoDetails)
+	if err != nil {
+		return err
+	}
+
+	batchSize := 50
+	for start := 0; start < len(releases); start += batchSize {
+		batch := releases[start:min(start+batchSize, len(releases))]
+		if err := rs.releaseRepo.UpsertReleases(ctx, batch); err != nil {
+			return fmt.Errorf("failed to upsert release batch: %w", err)
+		}
+	}
+
+	if err := githubservice.SaveValidators(fetchCtx); err != nil {
+		logr.Error("failed to save etags", zap.String("repo_name", repoDetails.Name), zap.Error(err))
+	}
+
+	logr.Info("Synced releases", zap.String("repo_name", repoDetails.Name), zap.Int("tags", tagCount), zap.Int("releases", len(releases)))
+	return nil
+}
+
+// fetchReleases returns every release of a repository. They are only stored once the tags they
+// point at are.
+func (rs *releaseService) fetchReleases(ctx context.Context, repoDetails *domain.Repository) ([]domain.Release, error) {
+	releaseCh := make(chan domain.Release, 100)
+	errCh := make(chan error, 1)
+	go func() {
+		defer close(releaseCh)
+		errCh <- rs.githubService.GetReleases(ctx, repoDetails.Name, repoDetails.OwnerName, releaseCh)
+	}()
+
+	var releases []domain.Release
+	for {
+		select {
+		case <-ctx.Done():
+			return nil, fmt.Errorf("context canceled while processing releases")
+		case release, ok := <-releaseCh:
+			if !ok {
+				if err := <-errCh; err != nil {
+					return nil, fmt.Errorf("failed to fetch releases: %w", err)
+				}
+				return releases, nil
+			}
+
+			release.RepositoryID = repoDetails.ID
+			releases = append(releases, release)
+		}
+	}
+}
+
+// syncTags stores every tag of a repository and returns how many there were.
+func (rs *releaseService) syncTags(ctx context.Context, repoDetails *domain.Repository) (int, error) {
+	tagCh := make(chan domain.Tag, 100)
+	errCh := make(chan error, 1)
+	go func() {
+		defer close(tagCh)
+		errCh <- rs.githubService.GetTags(ctx, repoDetails.Name, repoDetails.OwnerName, tagCh)
+	}()
+
+	var tags []domain.Tag
+	batchSize := 50
+	count := 0
+
+	for {
+		select {
+		case <-ctx.Done():
+			return count, fmt.Errorf("context canceled while processing tags")
+		case tag, ok := <-tagCh:
+			if !ok {
+				if err := <-errCh; err != nil {
+					return count, fmt.Errorf("failed to fetch tags: %w", err)
+				}
+				if err := rs.releaseRepo.UpsertTags(ctx, tags); err != nil {
+					return count, fmt.Errorf("failed to upsert remaining tags: %w", err)
+				}
+				return count, nil
+			}
+
+			count++
+			tag.RepositoryID = repoDetails.ID
+			tags = append(tags, tag)
+			if len(tags) >= batchSize {
+				if err := rs.releaseRepo.UpsertTags(ctx, tags); err != nil {
+					return count, fmt.Errorf("failed to upsert tag batch: %w", err)
+				}
+				tags = tags[:0]
+			}
+		}
+	}
+}
+
+func (rs *releaseService) GetReleases(ctx context.Context, ownerName, repoName string, includePrereleases bool, page, pageSize int) ([]domain.Release, *pagination.Pagination, error) {
+	logr := rs.logger.With(zap.String("method", "GetReleases"))
+
+	repoDetails, err := rs.repository(ctx, ownerName, repoName)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	releases, totalItems, err := rs.releaseRepo.GetReleases(ctx, repoDetails.ID, includePrereleases, page, pageSize)
+	if err != nil {
+		logr.Error("error in GetReleases", zap.Error(err))
+		return nil, nil, err
+	}
+
+	return releases, pagination.NewPagination(page, pageSize, totalItems), nil
+}
+
+func (rs *releaseService) GetReleaseCadence(ctx context.Context, ownerName, repoName string, includePrereleases bool) (*domain.ReleaseCadence, error) {
+	repoDetails, err := rs.repository(ctx, ownerName, repoName)
+	if err != nil {
+		return nil, err
+	}
+
+	return rs.releaseRepo.GetReleaseCadence(ctx, repoDetails.ID, includePrereleases)
+}
@@ -2,7 +2,9 @@ package repositoryservice
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"strings"
 	"time"
 
 	"github.com/babyfaceeasy/lema/internal/domain"
@@ -14,14 +16,16 @@ import (
 type repositoryService struct {
 	logger         *zap.Logger
 	repoRepository repositories.RepositoryRepository
+	branchRepo     repositories.BranchRepository
 	githubService  githubservice.GitHubService
 }
 
-func NewRepositoryService(logger *zap.Logger, repoRepository repositories.RepositoryRepository, githubService githubservice.GitHubService) domain.RepositoryService {
+func NewRepositoryService(logger *zap.Logger, repoRepository repositories.RepositoryRepository, branchRepo repositories.BranchRepository, githubService githubservice.GitHubService) domain.RepositoryService {
 	logger = logger.With(zap.String("package", "repositoryservice"))
 	return &repositoryService{
 		logger:         logger,
 		repoRepository: repoRepository,
+		branchRepo:     branchRepo,
 		githubService:  githubService,
 	}
 }
@@ -51,27 +55,32 @@ func (rs *repositoryService) GetRepository(ctx context.Context, owner, repo stri
 	return repoDetails, nil
 }
 
-// SaveRepository creates a new repository.
+// SaveRepository creates a new repository. Monitoring again a stored repository whose monitoring
+// was paused, e.g. because it was reported deleted, gives it the state its provider reports and
+// resumes its syncs from where they stopped.
 func (rs *repositoryService) SaveRepository(ctx context.Context, owner string, repo string, startTime *time.Time) error {
 	logr := rs.logger.With(zap.String("method", "SaveRepository"))
 
-	exists, err := rs.repoRepository.Exists(ctx, owner, repo)
+	stored, err := rs.repoRepository.ByName(ctx, owner, repo)
 	if err != nil {
 		return err
 	}
 
-	if exists {
+	if stored != nil && stored.Active() {
 		logr.Info("repository exists already", zap.String("repo_name", repo), zap.String("owner_name", owner))
 		return fmt.Errorf("repository name : %s/%s already in our system", owner, repo)
 	}
 
-	repoDetails, err := rs.githubService.GetRepositoryDetails(repo, owner)
+	repoDetails, err := rs.githubService.GetRepositoryDetails(ctx, repo, owner)
 	if err != nil {
 		logr.Error("error in getting repository details", zap.Error(err))
 		return err
 	}
 
 	newRepo := domain.Repository{
+		GithubID:            repoDetails.GithubID,
+		Host:                domain.HostFromContext(ctx),
+		Provider:            repoDetails.Provider,
 		Name:                repoDetails.Name,
 		OwnerName:           repoDetails.OwnerName,
 		Description:         repoDetails.Description,
@@ -81,10 +90,16 @@ func (rs *repositoryService) SaveRepository(ctx context.Context, owner string, r
 		StarsCount:          repoDetails.StarsCount,
 		WatchersCount:       repoDetails.WatchersCount,
 		OpenIssuesCount:     repoDetails.OpenIssuesCount,
+		DefaultBranch:       repoDetails.DefaultBranch,
+		State:               repoDetails.State,
 		UntilDate:           startTime,
 		// SinceDate:           time.Now(),
 		CreatedAt: time.Now(),
 	}
+	if stored != nil {
+		logr.Info("resuming monitoring of repository", zap.String("repo_name", repo), zap.String("owner_name", owner), zap.String("state", stored.State))
+		newRepo.SinceDate = stored.SinceDate
+	}
 
 	logr.Sugar().Debugf("data to be saved into db%+v\n", newRepo)
 
@@ -95,6 +110,14 @@ func (rs *repositoryService) SaveRepository(ctx context.Context, owner string, r
 
 	logr.Info("repository with name was saved successfully", zap.String("repo_name", repo))
 
+	// The default branch is synced with the repository itself, registering it lets its
+	// commits be filtered by branch like any other monitored branch.
+	if newRepo.DefaultBranch != "" {
+		if err := rs.AddRepositoryBranches(ctx, newRepo.OwnerName, newRepo.Name, []string{newRepo.DefaultBranch}); err != nil {
+			return err
+		}
+	}
+
 	// todo: create a task to start getting of the commits for the given repository, make use of the repository ID here
 	return nil
 }
@@ -122,3 +145,262 @@ func (rs *repositoryService) UpdateRepositoryStartDate(ctx context.Context, owne
 
 	return nil
 }
+
+// AddRepositoryBranches starts monitoring the given branches of a repository.
+func (rs *repositoryService) AddRepositoryBranches(ctx context.Context, ownerName string, repoName string, branches []string) error {
+	logr := rs.logger.With(zap.String("method", "AddRepositoryBranches"))
+
+	repoDetails, err := rs.repoRepository.ByName(ctx, ownerName, repoName)
+	if err != nil {
+		return err
+	}
+	if repoDetails == nil {
+		return fmt.Errorf("repository %s/%s does not exist in our system", ownerName, repoName)
+	}
+
+	for _, branch := range branches {
+		if err := rs.branchRepo.Create(ctx, domain.Branch{RepositoryID: repoDetails.ID, Name: branch}); err != nil {
+			logr.Error("error in saving branch", zap.String("branch", branch), zap.Error(err))
+			return err
+		}
+	}
+
+	logr.Info("branches added", zap.String("repo_name", repoDetails.Name), zap.Strings("branches", branches))
+	return nil
+}
+
+// GetRepositoryBranches returns the monitored branches of a repository.
+func (rs *repositoryService) GetRepositoryBranches(ctx context.Context, ownerName string, repoName string) ([]domain.Branch, error) {
+	repoDetails, err := rs.repoRepository.ByName(ctx, ownerName, repoName)
+	if err != nil {
+		return nil, err
+	}
+	if repoDetails == nil {
+		return nil, fmt.Errorf("repository %s/%s does not exist in our system", ownerName, repoName)
+	}
+
+	return rs.branchRepo.ByRepositoryID(ctx, repoDetails.ID)
+}
+
+// UpdateBranchSinceDate moves the sync watermark of a monitored branch.
+func (rs *repositoryService) UpdateBranchSinceDate(ctx context.Context, branchID int, sinceTime time.Time) error {
+	if err := rs.branchRepo.UpdateSinceDate(ctx, branchID, sinceTime); err != nil {
+		return fmt.Errorf("error in updating branch since date: %w", err)
+	}
+	return nil
+}
+
+// ResetRepositoryBranches makes the next sync of every branch of a repository start from scratch.
+func (rs *repositoryService) ResetRepositoryBranches(ctx context.Context, ownerName string, repoName string) error {
+	repoDetails, err := rs.repoRepository.ByName(ctx, ownerName, repoName)
+	if err != nil {
+		return err
+	}
+	if repoDetails == nil {
+		return fmt.Errorf("repository %s/%s does not exist in our system", ownerName, repoName)
+	}
+
+	return rs.branchRepo.ResetSinceDates(ctx, repoDetails.ID)
+}
+
+// EnsureDefaultBranch returns the monitored branch row of the default branch of a repository,
+// registering it first when missing. Repositories stored before branches were monitored do not
+// know their default branch, which is then asked to their provider, and their commits are
+// added to it.
+func (rs *repositoryService) EnsureDefaultBranch(ctx context.Context, ownerName string, repoName string) (*domain.Branch, error) {
+	logr := rs.logger.With(zap.String("method", "EnsureDefaultBranch"))
+
+	repoDetails, err := rs.repoRepository.ByName(ctx, ownerName, repoName)
+	if err != nil {
+		return nil, err
+	}
+	if repoDetails == nil {
+		return nil, fmt.Errorf("repository %s/%s does not exist in our system", ownerName, repoName)
+	}
+
+	if repoDetails.DefaultBranch == "" {
+		latest, err := rs.githubService.GetRepositoryDetails(ctx, repoDetails.Name, repoDetails.OwnerName)
+		if err != nil {
+			return nil, err
+		}
+		if latest.DefaultBranch == "" {
+			return nil, nil
+		}
+		if err := rs.repoRepository.UpdateDefaultBranch(ctx, repoDetails.ID, latest.DefaultBranch); err != nil {
+			return nil, err
+		}
+		repoDetails.DefaultBranch = latest.DefaultBranch
+	}
+
+	findBranch := func() (*domain.Branch, error) {
+		branches, err := rs.branchRepo.ByRepositoryID(ctx, repoDetails.ID)
+		if err != nil {
+			return nil, err
+		}
+		for i := range branches {
+			if branches[i].Name == repoDetails.DefaultBranch {
+				return &branches[i], nil
+			}
+		}
+		return nil, nil
+	}
+
+	branch, err := findBranch()
+	if err != nil || branch != nil {
+		return branch, err
+	}
+
+	if err := rs.branchRepo.Create(ctx, domain.Branch{RepositoryID: repoDetails.ID, Name: repoDetails.DefaultBranch}); err != nil {
+		return nil, err
+	}
+	if branch, err = findBranch(); err != nil || branch == nil {
+		return nil, err
+	}
+	if err := rs.branchRepo.AddUnbranchedCommits(ctx, branch.ID, repoDetails.ID); err != nil {
+		return nil, err
+	}
+
+	logr.Info("default branch registered", zap.String("repo_name", repoDetails.Name), zap.String("branch", branch.Name))
+	return branch, nil
+}
+
+// RenameRepository records that a repository was renamed or transferred to another owner.
+func (rs *repositoryService) RenameRepository(ctx context.Context, ownerName string, repoName string, newOwnerName string, newRepoName string, url string) error {
+	logr := rs.logger.With(zap.String("method", "RenameRepository"))
+
+	repoDetails, err := rs.repoRepository.ByName(ctx, ownerName, repoName)
+	if err != nil {
+		return err
+	}
+	if repoDetails == nil {
+		return fmt.Errorf("repository %s/%s does not exist in our system", ownerName, repoName)
+	}
+
+	if err := rs.repoRepository.Rename(ctx, repoDetails.ID, newOwnerName, newRepoName, url); err != nil {
+		logr.Error("error in renaming repository", zap.Error(err))
+		return err
+	}
+
+	logr.Info("repository renamed", zap.String("from", ownerName+"/"+repoName), zap.String("to", newOwnerName+"/"+newRepoName))
+	return nil
+}
+
+// UpdateRepositoryState records that a repository was archived, unarchived or deleted.
+func (rs *repositoryService) UpdateRepositoryState(ctx context.Context, ownerName string, repoName string, state string) error {
+	logr := rs.logger.With(zap.String("method", "UpdateRepositoryState"))
+
+	repoDetails, err := rs.repoRepository.ByName(ctx, ownerName, repoName)
+	if err != nil {
+		return err
+	}
+	if repoDetails == nil {
+		return fmt.Errorf("repository %s/%s does not exist in our system", ownerName, repoName)
+	}
+
+	if err := rs.repoRepository.UpdateState(ctx, repoDetails.ID, state); err != nil {
+		logr.Error("error in updating repository state", zap.Error(err))
+		return err
+	}
+
+	logr.Info("repository state updated", zap.String("repo_name", repoDetails.Name), zap.String("state", state))
+	return nil
+}
+
+// RefreshRepository updates the popularity counters of a stored repository from its provider and
+// returns the updated repository. Repositories GitHub renamed, transferred, archived or no longer
+// finds are renamed, or get the archived or deleted state which pauses their monitoring.
+func (rs *repositoryService) RefreshRepository(ctx context.Context, ownerName string, repoName string) (*domain.Repository, error) {
+	logr := rs.logger.With(zap.String("method", "RefreshRepository"))
+
+	repoDetails, err := rs.repoRepository.ByName(ctx, ownerName, repoName)
+	if err != nil {
+		return nil, err
+	}
+	if repoDetails == nil {
+		return nil, fmt.Errorf("repository %s/%s does not exist in our system", ownerName, repoName)
+	}
+
+	latest, err := rs.githubService.GetRepositoryDetails(ctx, repoDetails.Name, repoDetails.OwnerName)
+	if errors.Is(err, domain.ErrNotFound) && repoDetails.HostedOnGithub() {
+		if err := rs.setState(ctx, repoDetails, domain.RepositoryStateDeleted); err != nil {
+			return nil, err
+		}
+		logr.Warn("repository no longer exists, monitoring paused", zap.String("repo_name", repoDetails.Name), zap.String("owner_name", repoDetails.OwnerName))
+		return repoDetails, nil
+	}
+	if err != nil {
+		logr.Error("error in getting repository details", zap.Error(err))
+		return nil, err
+	}
+
+	if repoDetails.HostedOnGithub() {
+		if err := rs.followMove(ctx, repoDetails, latest); err != nil {
+			return nil, err
+		}
+	}
+	if latest.State != "" {
+		if err := rs.setState(ctx, repoDetails, latest.State); err != nil {
+			return nil, err
+		}
+	}
+
+	repoDetails.ForksCount = latest.ForksCount
+	repoDetails.StarsCount = latest.StarsCount
+	repoDetails.WatchersCount = latest.WatchersCount
+	repoDetails.OpenIssuesCount = latest.OpenIssuesCount
+	if err := rs.repoRepository.UpdateCounters(ctx, repoDetails.ID, *repoDetails); err != nil {
+		logr.Error("error in updating repository counters", zap.Error(err))
+		return nil, err
+	}
+
+	return repoDetails, nil
+}
+
+// followMove records the GitHub ID of repoDetails and, when GitHub redirected it to another owner
+// or name, renames it after latest.
+func (rs *repositoryService) followMove(ctx context.Context, repoDetails *domain.Repository, latest *domain.Repository) error {
+	logr := rs.logger.With(zap.String("method", "followMove"))
+
+	if latest.GithubID != nil && (repoDetails.GithubID == nil || *repoDetails.GithubID != *latest.GithubID) {
+		if err := rs.repoRepository.UpdateGithubID(ctx, repoDetails.ID, *latest.GithubID); err != nil {
+			return err
+		}
+		repoDetails.GithubID = latest.GithubID
+	}
+
+	if latest.Name == "" || latest.OwnerName == "" ||
+		(strings.EqualFold(latest.Name, repoDetails.Name) && strings.EqualFold(latest.OwnerName, repoDetails.OwnerName)) {
+		return nil
+	}
+
+	if err := rs.repoRepository.Rename(ctx, repoDetails.ID, latest.OwnerName, latest.Name, latest.URL); err != nil {
+		logr.Error("error in renaming repository", zap.Error(err))
+		return err
+	}
+	logr.Info("repository moved", zap.String("from", repoDetails.OwnerName+"/"+repoDetails.Name), zap.String("to", latest.OwnerName+"/"+latest.Name))
+
+	repoDetails.OwnerName = latest.OwnerName
+	repoDetails.Name = latest.Name
+	if latest.URL != "" {
+		repoDetails.URL = latest.URL
+	}
+	return nil
+}
+
+// setState stores state as the state of repoDetails when it differs from the current one.
+func (rs *repositoryService) setState(ctx context.Context, repoDetails *domain.Repository, state string) error {
+	current := repoDetails.State
+	if current == "" {
+		current = domain.RepositoryStateActive
+	}
+	if current == state {
+		return nil
+	}
+
+	if err := rs.repoRepository.UpdateState(ctx, repoDetails.ID, state); err != nil {
+		return err
+	}
+	rs.logger.Info("repository state updated", zap.String("repo_name", repoDetails.Name), zap.String("from", current), zap.String("state", state))
+	repoDetails.State = state
+	return nil
+}
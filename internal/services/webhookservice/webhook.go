@@ -0,0 +1,210 @@
+package webhookservice
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/babyfaceeasy/lema/internal/domain"
+	"github.com/babyfaceeasy/lema/internal/integrations/githubapi"
+	"github.com/babyfaceeasy/lema/internal/repositories"
+	"github.com/babyfaceeasy/lema/internal/tasks"
+	"github.com/bytedance/sonic"
+	"go.uber.org/zap"
+)
+
+type webhookService struct {
+	logger            *zap.Logger
+	webhookRepo       repositories.WebhookRepository
+	commitRepo        repositories.CommitRepository
+	repositoryService domain.RepositoryService
+}
+
+func NewWebhookService(webhookRepo repositories.WebhookRepository, commitRepo repositories.CommitRepository, logger *zap.Logger, repoSvc domain.RepositoryService) domain.WebhookService {
+	logger = logger.With(zap.String("package", "webhookservice"))
+	return &webhookService{
+		logger:            logger,
+		webhookRepo:       webhookRepo,
+		commitRepo:        commitRepo,
+		repositoryService: repoSvc,
+	}
+}
+
+// HandleGithubWebhook handles a delivery of a GitHub webhook, on the host ctx is scoped to. A delivery
+// is only handled once: it is forgotten when handling it fails, so that GitHub can redeliver it.
+func (ws *webhookService) HandleGithubWebhook(ctx context.Context, event, deliveryID string, payload []byte) (*domain.WebhookResult, error) {
+	logr := ws.logger.With(zap.String("method", "HandleGithubWebhook"), zap.String("event", event), zap.String("delivery_id", deliveryID))
+
+	recorded, err := ws.webhookRepo.RecordDelivery(ctx, deliveryID, event)
+	if err != nil {
+		return nil, err
+	}
+	if !recorded {
+		logr.Info("Skipping webhook delivered already")
+		return &domain.WebhookResult{Event: event, Outcome: domain.WebhookOutcomeDuplicate}, nil
+	}
+
+	var result *domain.WebhookResult
+	switch event {
+	case "ping":
+		result = &domain.WebhookResult{Outcome: domain.WebhookOutcomePong}
+	case "push":
+		result, err = ws.handlePush(ctx, payload)
+	case "repository":
+		result, err = ws.handleRepository(ctx, payload)
+	default:
+		result = &domain.WebhookResult{Outcome: domain.WebhookOutcomeIgnored}
+	}
+	if err != nil {
+		if forgetErr := ws.webhookRepo.ForgetDelivery(ctx, deliveryID); forgetErr != nil {
+			logr.Error("error in forgetting webhook delivery", zap.Error(forgetErr))
+		}
+		return nil, err
+	}
+
+	result.Event = event
+	logr.Info("Handled webhook", zap.String("outcome", result.Outcome), zap.Int("commits", result.Commits))
+	return result, nil
+}
+
+// monitoredRepository returns the stored repository an event is about, or nil when it is not monitored.
+func (ws *webhookService) monitoredRepository(ctx context.Context, owner, name string) (*domain.Repository, error) {
+	return ws.repositoryService.GetRepository(ctx, strings.ToLower(owner), strings.ToLower(name))
+}
+
+// handlePush stores the commits pushed to the default or a monitored branch of a monitored repository.
+// The commits of pushes GitHub truncated, or listed none of, are fetched by a sync instead.
+func (ws *webhookService) handlePush(ctx context.Context, payload []byte) (*domain.WebhookResult, error) {
+	var event githubapi.PushEvent
+	if err := sonic.Unmarshal(payload, &event); err != nil {
+		return nil, fmt.Errorf("%w: %v", domain.ErrInvalidWebhookPayload, err)
+	}
+
+	ignored := &domain.WebhookResult{Outcome: domain.WebhookOutcomeIgnored}
+	branchName := event.Branch()
+	if branchName == "" || event.RefDeleted() {
+		return ignored, nil
+	}
+
+	repoDetails, err := ws.monitoredRepository(ctx, event.Repository.Owner.Login, event.Repository.Name)
+	if err != nil {
+		return nil, err
+	}
+	if repoDetails == nil || !repoDetails.Active() {
+		return ignored, nil
+	}
+
+	branches, err := ws.repositoryService.GetRepositoryBranches(ctx, repoDetails.OwnerName, repoDetails.Name)
+	if err != nil {
+		return nil, err
+	}
+	var branch *domain.Branch
+	for i := range branches {
+		if branches[i].Name == branchName {
+			branch = &branches[i]
+			break
+		}
+	}
+	defaultBranch := branchName == repoDetails.DefaultBranch
+	if branch == nil && !defaultBranch {
+		return ignored, nil
+	}
+
+	if event.Truncated() || len(event.Commits) == 0 {
+		if defaultBranch {
+			err = tasks.CallLatestCommitsTask(repoDetails.Host, repoDetails.OwnerName, repoDetails.Name)
+		} else {
+			err = tasks.CallBranchCommitsTask(repoDetails.Host, repoDetails.OwnerName, repoDetails.Name, branch.Name)
+		}
+		if err != nil {
+			return nil, err
+		}
+		return &domain.WebhookResult{Outcome: domain.WebhookOutcomeEnqueued}, nil
+	}
+
+	commits := make([]domain.Commit, 0, len(event.Commits))
+	for _, pc := range event.Commits {
+		commit := convertToDomainCommit(event.Repository, pc)
+		commit.RepositoryID = repoDetails.ID
+		commit.Repository = *repoDetails
+		if branch != nil {
+			commit.BranchID = branch.ID
+		}
+		commits = append(commits, commit)
+	}
+
+	batchSize := 50
+	for start := 0; start < len(commits); start += batchSize {
+		end := min(start+batchSize, len(commits))
+		if err := ws.commitRepo.UpsertCommits(ctx, commits[start:end]); err != nil {
+			return nil, fmt.Errorf("failed to upsert pushed commits: %w", err)
+		}
+	}
+
+	return &domain.WebhookResult{Outcome: domain.WebhookOutcomeStored, Commits: len(commits)}, nil
+}
+
+// handleRepository follows monitored repositories being renamed, transferred, archived or deleted.
+func (ws *webhookService) handleRepository(ctx context.Context, payload []byte) (*domain.WebhookResult, error) {
+	var event githubapi.RepositoryEvent
+	if err := sonic.Unmarshal(payload, &event); err != nil {
+		return nil, fmt.Errorf("%w: %v", domain.ErrInvalidWebhookPayload, err)
+	}
+
+	var state string
+	switch event.Action {
+	case "renamed", "transferred":
+	case "archived":
+		state = domain.RepositoryStateArchived
+	case "unarchived":
+		state = domain.RepositoryStateActive
+	case "deleted":
+		state = domain.RepositoryStateDeleted
+	default:
+		return &domain.WebhookResult{Outcome: domain.WebhookOutcomeIgnored}, nil
+	}
+
+	owner, name := event.PreviousFullName()
+	repoDetails, err := ws.monitoredRepository(ctx, owner, name)
+	if err != nil {
+		return nil, err
+	}
+	if repoDetails == nil {
+		return &domain.WebhookResult{Outcome: domain.WebhookOutcomeIgnored}, nil
+	}
+
+	if state != "" {
+		if err := ws.repositoryService.UpdateRepositoryState(ctx, repoDetails.OwnerName, repoDetails.Name, state); err != nil {
+			return nil, err
+		}
+		return &domain.WebhookResult{Outcome: domain.WebhookOutcomeStateSaved}, nil
+	}
+
+	if err := ws.repositoryService.RenameRepository(ctx, repoDetails.OwnerName, repoDetails.Name, event.Repository.Owner.Login, event.Repository.Name, event.Repository.URL); err != nil {
+		return nil, err
+	}
+	return &domain.WebhookResult{Outcome: domain.WebhookOutcomeRenamed}, nil
+}
+
+func convertToDomainCommit(repo githubapi.WebhookRepository, pc githubapi.PushCommit) domain.Commit {
+	// Push events carry a single timestamp for both the author and the committer.
+	return domain.Commit{
+		SHA:        pc.ID,
+		URL:        repo.CommitURL(pc.ID),
+		Message:    pc.Message,
+		CommitDate: pc.Timestamp,
+		CreatedAt:  time.Now(),
+		Author: domain.Author{
+			Name:  pc.Author.Name,
+			Email: pc.Author.Email,
+			IsBot: pc.Author.IsBot(),
+		},
+		Committer: &domain.Author{
+			Name:  pc.Committer.Name,
+			Email: pc.Committer.Email,
+			IsBot: pc.Committer.IsBot(),
+		},
+		CommitterDate: pc.Timestamp,
+	}
+}
@@ -80,15 +80,15 @@ func (s *CommitStore) getOrCreateRepository(ctx context.Context, tx *sqlx.Tx, re
 	return id, nil
 }
 
-// getOrCreateAuthor checks if an author exists based on its Name Field.
+// getOrCreateAuthor checks if an author exists based on its Email Field.
 func (s *CommitStore) getOrCreateAuthor(ctx context.Context, tx *sqlx.Tx, author *Author) (int, error) {
 	if author.UID == uuid.Nil {
 		author.UID = uuid.New()
 	}
 
 	var id int
-	query := `SELECT id FROM authors WHERE name = $1 LIMIT 1`
-	err := tx.GetContext(ctx, &id, query, author.Name)
+	query := `SELECT id FROM authors WHERE email = $1 LIMIT 1`
+	err := tx.GetContext(ctx, &id, query, author.Email)
 	if err != nil {
 		if err == sql.ErrNoRows {
 			insertQuery := `
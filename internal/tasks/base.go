@@ -8,14 +8,18 @@ import (
 )
 
 type Task struct {
-	config            *config.Config
-	logger            *zap.Logger
-	store             *store.Store
-	commitService     domain.CommitService
-	repositoryService domain.RepositoryService
+	config             *config.Config
+	logger             *zap.Logger
+	store              *store.Store
+	commitService      domain.CommitService
+	repositoryService  domain.RepositoryService
+	pullRequestService domain.PullRequestService
+	issueService       domain.IssueService
+	releaseService     domain.ReleaseService
+	popularityService  domain.PopularityService
 }
 
-func New(config *config.Config, logger *zap.Logger, store *store.Store, commitSvc domain.CommitService, repoSvc domain.RepositoryService) *Task {
+func New(config *config.Config, logger *zap.Logger, store *store.Store, commitSvc domain.CommitService, repoSvc domain.RepositoryService, pullRequestSvc domain.PullRequestService, issueSvc domain.IssueService, releaseSvc domain.ReleaseService, popularitySvc domain.PopularityService) *Task {
 	logger = logger.With(zap.String("package", "tasks"))
-	return &Task{config: config, logger: logger, store: store, repositoryService: repoSvc, commitService: commitSvc}
+	return &Task{config: config, logger: logger, store: store, repositoryService: repoSvc, commitService: commitSvc, pullRequestService: pullRequestSvc, issueService: issueSvc, releaseService: releaseSvc, popularityService: popularitySvc}
 }
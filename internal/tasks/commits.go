@@ -2,6 +2,7 @@ package tasks
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log"
 	"net/http"
@@ -15,20 +16,43 @@ import (
 )
 
 type LoadCommitsTaskInput struct {
+	Host            string
 	RepositoryName  string
 	RepositoryOwner string
 }
 
 type GetLatestCommitsTaskInput struct {
+	Host            string
 	RepositoryName  string
 	RepositoryOwner string
 }
 
 type ResetCommitsTaskInput struct {
+	Host            string
 	RepositoryName  string
 	RepositoryOwner string
 }
 
+type CommitStatsTaskInput struct {
+	Host            string
+	RepositoryName  string
+	RepositoryOwner string
+}
+
+type BranchCommitsTaskInput struct {
+	Host            string
+	RepositoryName  string
+	RepositoryOwner string
+	Branch          string
+}
+
+// commitStatsBatchSize is how many commits a commit stats task fetches before checking its progress.
+const commitStatsBatchSize = 500
+
+// commitStatsUniqueFor bounds how long a commit stats backfill keeps others of its repository from
+// being enqueued, should it never complete.
+const commitStatsUniqueFor = 24 * time.Hour
+
 // cron handlers
 func (t *Task) HandleCommitsUpdateTaskOLD(ctx context.Context, a *asynq.Task) error {
 	// Get repositories by name
@@ -109,19 +133,33 @@ func (t *Task) HandleCommitsUpdateTask(ctx context.Context, a *asynq.Task) error
 	}
 
 	for _, repoDetails := range repos {
-		err := CallLatestCommitsTask(repoDetails.OwnerName, repoDetails.Name)
+		if repoDetails.Imported() || !repoDetails.Active() {
+			continue
+		}
+
+		err := CallLatestCommitsTask(repoDetails.Host, repoDetails.OwnerName, repoDetails.Name)
 		if err != nil {
 			logr.Error("error in adding repositories to get latest task", zap.Error(err))
 		}
 
+		if repoDetails.HostedOnGithub() {
+			if err := CallPullRequestsTask(repoDetails.Host, repoDetails.OwnerName, repoDetails.Name); err != nil {
+				logr.Error("error in adding repositories to pull requests task", zap.Error(err))
+			}
+
+			if err := CallIssuesTask(repoDetails.Host, repoDetails.OwnerName, repoDetails.Name); err != nil {
+				logr.Error("error in adding repositories to issues task", zap.Error(err))
+			}
+		}
+
 		logr.Debug("added repo for getting latest commits", zap.String("repo_name", repoDetails.Name))
 	}
 
 	return nil
 }
 
-func CallLoadCommitsTask(owner, name string) error {
-	i := LoadCommitsTaskInput{RepositoryOwner: owner, RepositoryName: name}
+func CallLoadCommitsTask(host, owner, name string) error {
+	i := LoadCommitsTaskInput{Host: host, RepositoryOwner: owner, RepositoryName: name}
 	payload, err := sonic.Marshal(i)
 	if err != nil {
 		return err
@@ -144,11 +182,31 @@ func (t *Task) HandleLoadCommitsTask(ctx context.Context, a *asynq.Task) error {
 		return fmt.Errorf("json.Unmarshal failed: %v: %w", err, asynq.SkipRetry)
 	}
 
-	return t.commitService.LoadCommits(ctx, p.RepositoryOwner, p.RepositoryName)
+	if err := t.commitService.LoadCommits(ctx, p.RepositoryOwner, p.RepositoryName); err != nil {
+		return err
+	}
+
+	if err := CallPullRequestsTask(p.Host, p.RepositoryOwner, p.RepositoryName); err != nil {
+		return err
+	}
+
+	if err := CallIssuesTask(p.Host, p.RepositoryOwner, p.RepositoryName); err != nil {
+		return err
+	}
+
+	if err := CallReleasesTask(p.Host, p.RepositoryOwner, p.RepositoryName); err != nil {
+		return err
+	}
+
+	if err := t.scheduleBranchCommits(ctx, p.RepositoryOwner, p.RepositoryName); err != nil {
+		return err
+	}
+
+	return t.scheduleCommitStats(p.Host, p.RepositoryOwner, p.RepositoryName)
 }
 
-func CallLatestCommitsTask(owner, name string) error {
-	i := LoadCommitsTaskInput{RepositoryOwner: owner, RepositoryName: name}
+func CallLatestCommitsTask(host, owner, name string) error {
+	i := LoadCommitsTaskInput{Host: host, RepositoryOwner: owner, RepositoryName: name}
 	payload, err := sonic.Marshal(i)
 	if err != nil {
 		return err
@@ -172,11 +230,19 @@ func (t *Task) HandleLatestCommitsTask(ctx context.Context, a *asynq.Task) error
 	}
 
 	// return t.commitService.GetLatestCommits(ctx, p.RepositoryOwner, p.RepositoryName)
-	return t.commitService.GetLatestCommitsNew(ctx, p.RepositoryOwner, p.RepositoryName)
+	if err := t.commitService.GetLatestCommitsNew(ctx, p.RepositoryOwner, p.RepositoryName); err != nil {
+		return err
+	}
+
+	if err := t.scheduleBranchCommits(ctx, p.RepositoryOwner, p.RepositoryName); err != nil {
+		return err
+	}
+
+	return t.scheduleCommitStats(p.Host, p.RepositoryOwner, p.RepositoryName)
 }
 
-func CallResetCommitsTask(owner, name string) error {
-	i := ResetCommitsTaskInput{RepositoryOwner: owner, RepositoryName: name}
+func CallResetCommitsTask(host, owner, name string) error {
+	i := ResetCommitsTaskInput{Host: host, RepositoryOwner: owner, RepositoryName: name}
 	payload, err := sonic.Marshal(i)
 	if err != nil {
 		return err
@@ -201,3 +267,111 @@ func (t *Task) HandleResetCommitsTask(ctx context.Context, a *asynq.Task) error
 
 	return t.commitService.ResetCommits(ctx, p.RepositoryOwner, p.RepositoryName)
 }
+
+// scheduleBranchCommits enqueues a sync of every monitored branch of the repository besides the
+// default one, which is synced with the repository itself.
+func (t *Task) scheduleBranchCommits(ctx context.Context, owner, name string) error {
+	repoDetails, err := t.repositoryService.GetRepository(ctx, owner, name)
+	if err != nil || repoDetails == nil {
+		return err
+	}
+
+	branches, err := t.repositoryService.GetRepositoryBranches(ctx, owner, name)
+	if err != nil {
+		return err
+	}
+
+	for _, branch := range branches {
+		if branch.Name == repoDetails.DefaultBranch {
+			continue
+		}
+		if err := CallBranchCommitsTask(repoDetails.Host, owner, name, branch.Name); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func CallBranchCommitsTask(host, owner, name, branch string) error {
+	i := BranchCommitsTaskInput{Host: host, RepositoryOwner: owner, RepositoryName: name, Branch: branch}
+	payload, err := sonic.Marshal(i)
+	if err != nil {
+		return err
+	}
+
+	info, err := client.Enqueue(asynq.NewTask("ops:branch_commits", payload), asynq.Retention(5*time.Hour), asynq.Queue(TypeQueueDefault))
+	if err != nil {
+		return err
+	}
+
+	log.Printf(" [*] Successfully enqueued task: %s\n", info.Type)
+
+	return nil
+}
+
+func (t *Task) HandleBranchCommitsTask(ctx context.Context, a *asynq.Task) error {
+	var p BranchCommitsTaskInput
+	if err := sonic.Unmarshal(a.Payload(), &p); err != nil {
+		return fmt.Errorf("json.Unmarshal failed: %v: %w", err, asynq.SkipRetry)
+	}
+
+	return t.commitService.SyncBranchCommits(ctx, p.RepositoryOwner, p.RepositoryName, p.Branch)
+}
+
+// scheduleCommitStats enqueues a commit stats backfill when line statistics are fetched per commit.
+// The GraphQL fetcher already returns them with the history.
+func (t *Task) scheduleCommitStats(host, owner, name string) error {
+	if !t.config.GetGithubFetchCommitStats() || t.config.UsesGithubGraphQL() {
+		return nil
+	}
+	return CallCommitStatsTask(host, owner, name)
+}
+
+// CallCommitStatsTask enqueues the commit stats backfill of a repository unless one is already
+// queued or running, as a single backfill walks every commit without statistics.
+func CallCommitStatsTask(host, owner, name string) error {
+	i := CommitStatsTaskInput{Host: host, RepositoryOwner: owner, RepositoryName: name}
+	payload, err := sonic.Marshal(i)
+	if err != nil {
+		return err
+	}
+
+	info, err := client.Enqueue(asynq.NewTask("ops:commit_stats", payload), asynq.Unique(commitStatsUniqueFor), asynq.Retention(5*time.Hour), asynq.Queue(TypeQueueDefault))
+	if errors.Is(err, asynq.ErrDuplicateTask) {
+		log.Printf(" [*] Commit stats backfill of %s/%s already enqueued\n", owner, name)
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	log.Printf(" [*] Successfully enqueued task: %s\n", info.Type)
+
+	return nil
+}
+
+// HandleCommitStatsTask fetches line statistics batch after batch until every commit of the
+// repository has them. When the GitHub budget runs low the task is retried once it resets.
+func (t *Task) HandleCommitStatsTask(ctx context.Context, a *asynq.Task) error {
+	logr := t.logger.With(zap.String("method", "HandleCommitStatsTask"))
+	var p CommitStatsTaskInput
+	if err := sonic.Unmarshal(a.Payload(), &p); err != nil {
+		return fmt.Errorf("json.Unmarshal failed: %v: %w", err, asynq.SkipRetry)
+	}
+
+	for {
+		progress, err := t.commitService.LoadCommitStats(ctx, p.RepositoryOwner, p.RepositoryName, commitStatsBatchSize)
+		if err != nil {
+			return err
+		}
+
+		if !progress.ResumeAt.IsZero() {
+			return &resumeLater{at: progress.ResumeAt}
+		}
+
+		if progress.Pending == 0 || progress.Processed+progress.Skipped == 0 {
+			logr.Info("commit stats backfill finished", zap.String("repo_name", p.RepositoryName), zap.Int("pending", progress.Pending))
+			return nil
+		}
+	}
+}
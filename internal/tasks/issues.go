@@ -0,0 +1,43 @@
+package tasks
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/bytedance/sonic"
+	"github.com/hibiken/asynq"
+)
+
+type IssuesTaskInput struct {
+	Host            string
+	RepositoryName  string
+	RepositoryOwner string
+}
+
+func CallIssuesTask(host, owner, name string) error {
+	i := IssuesTaskInput{Host: host, RepositoryOwner: owner, RepositoryName: name}
+	payload, err := sonic.Marshal(i)
+	if err != nil {
+		return err
+	}
+
+	info, err := client.Enqueue(asynq.NewTask("ops:issues", payload), asynq.Retention(5*time.Hour), asynq.Queue(TypeQueueDefault))
+	if err != nil {
+		return err
+	}
+
+	log.Printf(" [*] Successfully enqueued task: %s\n", info.Type)
+
+	return nil
+}
+
+func (t *Task) HandleIssuesTask(ctx context.Context, a *asynq.Task) error {
+	var p IssuesTaskInput
+	if err := sonic.Unmarshal(a.Payload(), &p); err != nil {
+		return fmt.Errorf("json.Unmarshal failed: %v: %w", err, asynq.SkipRetry)
+	}
+
+	return t.issueService.SyncIssues(ctx, p.RepositoryOwner, p.RepositoryName)
+}
@@ -4,6 +4,8 @@ import (
 	"context"
 	"time"
 
+	"github.com/babyfaceeasy/lema/internal/domain"
+	"github.com/bytedance/sonic"
 	"github.com/hibiken/asynq"
 	"go.uber.org/zap"
 )
@@ -22,3 +24,15 @@ func (tsk *Task) LoggingMiddleware(h asynq.Handler) asynq.Handler {
 		return nil
 	})
 }
+
+// HostMiddleware scopes the context of a task to the host of the repository in its payload.
+// Tasks enqueued before hosts were recorded, and cron tasks, use the default host.
+func (tsk *Task) HostMiddleware(h asynq.Handler) asynq.Handler {
+	return asynq.HandlerFunc(func(ctx context.Context, t *asynq.Task) error {
+		var p struct{ Host string }
+		if len(t.Payload()) > 0 {
+			_ = sonic.Unmarshal(t.Payload(), &p)
+		}
+		return h.ProcessTask(domain.WithHost(ctx, p.Host), t)
+	})
+}
@@ -0,0 +1,98 @@
+package tasks
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/bytedance/sonic"
+	"github.com/hibiken/asynq"
+	"go.uber.org/zap"
+)
+
+type RepositorySnapshotTaskInput struct {
+	Host            string
+	RepositoryName  string
+	RepositoryOwner string
+}
+
+type StarHistoryTaskInput struct {
+	Host            string
+	RepositoryName  string
+	RepositoryOwner string
+}
+
+func CallRepositorySnapshotTask(host, owner, name string) error {
+	i := RepositorySnapshotTaskInput{Host: host, RepositoryOwner: owner, RepositoryName: name}
+	payload, err := sonic.Marshal(i)
+	if err != nil {
+		return err
+	}
+
+	info, err := client.Enqueue(asynq.NewTask("ops:repository_snapshot", payload), asynq.Retention(5*time.Hour), asynq.Queue(TypeQueueDefault))
+	if err != nil {
+		return err
+	}
+
+	log.Printf(" [*] Successfully enqueued task: %s\n", info.Type)
+
+	return nil
+}
+
+func CallStarHistoryTask(host, owner, name string) error {
+	i := StarHistoryTaskInput{Host: host, RepositoryOwner: owner, RepositoryName: name}
+	payload, err := sonic.Marshal(i)
+	if err != nil {
+		return err
+	}
+
+	info, err := client.Enqueue(asynq.NewTask("ops:star_history", payload), asynq.Retention(5*time.Hour), asynq.Queue(TypeQueueDefault))
+	if err != nil {
+		return err
+	}
+
+	log.Printf(" [*] Successfully enqueued task: %s\n", info.Type)
+
+	return nil
+}
+
+// HandlePopularitySnapshotTask enqueues a snapshot of the counters of every synced repository.
+func (t *Task) HandlePopularitySnapshotTask(ctx context.Context, a *asynq.Task) error {
+	logr := t.logger.With(zap.String("method", "HandlePopularitySnapshotTask"))
+
+	repos, err := t.repositoryService.GetAllRepositories(ctx)
+	if err != nil {
+		return err
+	}
+
+	for _, repoDetails := range repos {
+		if repoDetails.Imported() || !repoDetails.Active() {
+			continue
+		}
+		if err := CallRepositorySnapshotTask(repoDetails.Host, repoDetails.OwnerName, repoDetails.Name); err != nil {
+			logr.Error("error in adding repositories to snapshot task", zap.Error(err))
+		}
+	}
+
+	return nil
+}
+
+func (t *Task) HandleRepositorySnapshotTask(ctx context.Context, a *asynq.Task) error {
+	var p RepositorySnapshotTaskInput
+	if err := sonic.Unmarshal(a.Payload(), &p); err != nil {
+		return fmt.Errorf("json.Unmarshal failed: %v: %w", err, asynq.SkipRetry)
+	}
+
+	return t.popularityService.SnapshotRepository(ctx, p.RepositoryOwner, p.RepositoryName)
+}
+
+func (t *Task) HandleStarHistoryTask(ctx context.Context, a *asynq.Task) error {
+	var p StarHistoryTaskInput
+	if err := sonic.Unmarshal(a.Payload(), &p); err != nil {
+		return fmt.Errorf("json.Unmarshal failed: %v: %w", err, asynq.SkipRetry)
+	}
+
+	_, err := t.popularityService.BackfillStarHistory(ctx, p.RepositoryOwner, p.RepositoryName)
+	return err
+}
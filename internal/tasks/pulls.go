@@ -0,0 +1,43 @@
+package tasks
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/bytedance/sonic"
+	"github.com/hibiken/asynq"
+)
+
+type PullRequestsTaskInput struct {
+	Host            string
+	RepositoryName  string
+	RepositoryOwner string
+}
+
+func CallPullRequestsTask(host, owner, name string) error {
+	i := PullRequestsTaskInput{Host: host, RepositoryOwner: owner, RepositoryName: name}
+	payload, err := sonic.Marshal(i)
+	if err != nil {
+		return err
+	}
+
+	info, err := client.Enqueue(asynq.NewTask("ops:pull_requests", payload), asynq.Retention(5*time.Hour), asynq.Queue(TypeQueueDefault))
+	if err != nil {
+		return err
+	}
+
+	log.Printf(" [*] Successfully enqueued task: %s\n", info.Type)
+
+	return nil
+}
+
+func (t *Task) HandlePullRequestsTask(ctx context.Context, a *asynq.Task) error {
+	var p PullRequestsTaskInput
+	if err := sonic.Unmarshal(a.Payload(), &p); err != nil {
+		return fmt.Errorf("json.Unmarshal failed: %v: %w", err, asynq.SkipRetry)
+	}
+
+	return t.pullRequestService.SyncPullRequests(ctx, p.RepositoryOwner, p.RepositoryName)
+}
@@ -0,0 +1,66 @@
+package tasks
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/bytedance/sonic"
+	"github.com/hibiken/asynq"
+	"go.uber.org/zap"
+)
+
+type ReleasesTaskInput struct {
+	Host            string
+	RepositoryName  string
+	RepositoryOwner string
+}
+
+func CallReleasesTask(host, owner, name string) error {
+	i := ReleasesTaskInput{Host: host, RepositoryOwner: owner, RepositoryName: name}
+	payload, err := sonic.Marshal(i)
+	if err != nil {
+		return err
+	}
+
+	info, err := client.Enqueue(asynq.NewTask("ops:releases", payload), asynq.Retention(5*time.Hour), asynq.Queue(TypeQueueDefault))
+	if err != nil {
+		return err
+	}
+
+	log.Printf(" [*] Successfully enqueued task: %s\n", info.Type)
+
+	return nil
+}
+
+// HandleReleasesUpdateTask enqueues a release sync for every monitored repository. Releases change
+// far less often than commits, so it runs on its own, slower schedule.
+func (t *Task) HandleReleasesUpdateTask(ctx context.Context, a *asynq.Task) error {
+	logr := t.logger.With(zap.String("method", "HandleReleasesUpdateTask"))
+
+	repos, err := t.repositoryService.GetAllRepositories(ctx)
+	if err != nil {
+		return err
+	}
+
+	for _, repoDetails := range repos {
+		if !repoDetails.HostedOnGithub() {
+			continue
+		}
+		if err := CallReleasesTask(repoDetails.Host, repoDetails.OwnerName, repoDetails.Name); err != nil {
+			logr.Error("error in adding repositories to releases task", zap.Error(err))
+		}
+	}
+
+	return nil
+}
+
+func (t *Task) HandleReleasesTask(ctx context.Context, a *asynq.Task) error {
+	var p ReleasesTaskInput
+	if err := sonic.Unmarshal(a.Payload(), &p); err != nil {
+		return fmt.Errorf("json.Unmarshal failed: %v: %w", err, asynq.SkipRetry)
+	}
+
+	return t.releaseService.SyncReleases(ctx, p.RepositoryOwner, p.RepositoryName)
+}
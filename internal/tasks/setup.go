@@ -1,6 +1,8 @@
 package tasks
 
 import (
+	"errors"
+	"fmt"
 	"os"
 	"time"
 
@@ -31,6 +33,29 @@ type Config struct {
 	TaskType string `yaml:"task_type"`
 }
 
+// resumeLater is returned by a handler that paused to preserve the GitHub budget. Its task is
+// retried at the given time, without counting as a failure.
+type resumeLater struct {
+	at time.Time
+}
+
+func (e *resumeLater) Error() string {
+	return fmt.Sprintf("paused until %s", e.at.Format(time.RFC3339))
+}
+
+func retryDelay(n int, err error, task *asynq.Task) time.Duration {
+	var resume *resumeLater
+	if errors.As(err, &resume) {
+		return max(time.Until(resume.at), 0)
+	}
+	return asynq.DefaultRetryDelayFunc(n, err, task)
+}
+
+func isFailure(err error) bool {
+	var resume *resumeLater
+	return !errors.As(err, &resume)
+}
+
 // start worker
 func StartWorker(t Task, config *config.Config) error {
 	client = asynq.NewClient(asynq.RedisClientOpt{Addr: config.RedisAddress()})
@@ -40,19 +65,29 @@ func StartWorker(t Task, config *config.Config) error {
 		asynq.Config{Concurrency: 10, Queues: map[string]int{
 			TypeQueueCritical: 3,
 			TypeQueueDefault:  1,
-		}},
+		}, RetryDelayFunc: retryDelay, IsFailure: isFailure},
 	)
 
 	mux := asynq.NewServeMux()
 	mux.Use(t.LoggingMiddleware)
+	mux.Use(t.HostMiddleware)
 
 	// tasks
 	mux.HandleFunc("ops:load_commits", t.HandleLoadCommitsTask)
 	mux.HandleFunc("ops:latest_commits", t.HandleLatestCommitsTask)
 	mux.HandleFunc("ops:reset_commits", t.HandleResetCommitsTask)
+	mux.HandleFunc("ops:commit_stats", t.HandleCommitStatsTask)
+	mux.HandleFunc("ops:branch_commits", t.HandleBranchCommitsTask)
+	mux.HandleFunc("ops:pull_requests", t.HandlePullRequestsTask)
+	mux.HandleFunc("ops:issues", t.HandleIssuesTask)
+	mux.HandleFunc("ops:releases", t.HandleReleasesTask)
+	mux.HandleFunc("ops:repository_snapshot", t.HandleRepositorySnapshotTask)
+	mux.HandleFunc("ops:star_history", t.HandleStarHistoryTask)
 
 	// cron
 	mux.HandleFunc("cron:commits_update", t.HandleCommitsUpdateTask)
+	mux.HandleFunc("cron:releases_update", t.HandleReleasesUpdateTask)
+	mux.HandleFunc("cron:popularity_snapshot", t.HandlePopularitySnapshotTask)
 
 	go func() {
 		if err := srv.Run(mux); err != nil {
@@ -0,0 +1,55 @@
+// Package botdetect tells bot and automation accounts, such as dependabot[bot] or the autorollers
+// of Chromium, from people by the name and email they commit with.
+package botdetect
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// Classifier flags GitHub App accounts, whose names end with "[bot]", and the names and emails
+// matching its patterns.
+type Classifier struct {
+	patterns []*regexp.Regexp
+}
+
+// New creates a classifier flagging the names and emails matching any of patterns, which are
+// regular expressions matched case-insensitively. Blank patterns are ignored.
+func New(patterns []string) (*Classifier, error) {
+	c := &Classifier{}
+	for _, pattern := range patterns {
+		pattern = strings.TrimSpace(pattern)
+		if pattern == "" {
+			continue
+		}
+		re, err := regexp.Compile("(?i)" + pattern)
+		if err != nil {
+			return nil, fmt.Errorf("invalid bot pattern %q: %w", pattern, err)
+		}
+		c.patterns = append(c.patterns, re)
+	}
+	return c, nil
+}
+
+// IsBot reports whether name and email belong to a bot. A nil Classifier only flags GitHub Apps.
+func (c *Classifier) IsBot(name, email string) bool {
+	if IsGithubApp(name) || IsGithubApp(strings.SplitN(email, "@", 2)[0]) {
+		return true
+	}
+	if c == nil {
+		return false
+	}
+	for _, re := range c.patterns {
+		if re.MatchString(name) || re.MatchString(email) {
+			return true
+		}
+	}
+	return false
+}
+
+// IsGithubApp reports whether a GitHub login or commit name is that of a GitHub App, e.g.
+// "dependabot[bot]".
+func IsGithubApp(login string) bool {
+	return strings.HasSuffix(strings.TrimSpace(login), "[bot]")
+}
@@ -0,0 +1,42 @@
+package botdetect_test
+
+import (
+	"testing"
+
+	"github.com/babyfaceeasy/lema/pkg/botdetect"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIsBot(t *testing.T) {
+	classifier, err := botdetect.New([]string{"autoroll", `\.gserviceaccount\.com$`, " "})
+	require.NoError(t, err)
+
+	tests := []struct {
+		name  string
+		email string
+		want  bool
+	}{
+		{"dependabot[bot]", "49699333+dependabot[bot]@users.noreply.github.com", true},
+		{"Renovate", "29139614+renovate[bot]@users.noreply.github.com", true},
+		{"chromium-autoroll", "chromium-autoroll@skia-public.iam.gserviceaccount.com", true},
+		{"Chromium AutoRoll", "roller@example.com", true},
+		{"LUCI CQ", "luci-cq@chops-service-accounts.iam.gserviceaccount.com", true},
+		{"Jane Doe", "jane@chromium.org", false},
+		{"Robot Framework Fan", "bot-lover@example.com", false},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, classifier.IsBot(tt.name, tt.email), "%s <%s>", tt.name, tt.email)
+	}
+}
+
+func TestIsBotWithoutPatterns(t *testing.T) {
+	var classifier *botdetect.Classifier
+	assert.True(t, classifier.IsBot("github-actions[bot]", "41898282+github-actions[bot]@users.noreply.github.com"))
+	assert.False(t, classifier.IsBot("chromium-autoroll", "chromium-autoroll@skia-public.iam.gserviceaccount.com"))
+}
+
+func TestNewRejectsInvalidPatterns(t *testing.T) {
+	_, err := botdetect.New([]string{"(unclosed"})
+	assert.Error(t, err)
+}
@@ -0,0 +1,32 @@
+package commitmsg
+
+import (
+	"regexp"
+	"strings"
+)
+
+// Person is someone named in a commit message, e.g. in a "Co-authored-by:" trailer.
+type Person struct {
+	Name  string
+	Email string
+}
+
+// coAuthorPattern matches "Co-authored-by: Name <email>" lines, as GitHub writes them for commits
+// made by several people.
+var coAuthorPattern = regexp.MustCompile(`(?im)^[ \t]*co-authored-by:[ \t]*(.*?)[ \t]*<([^<>\s]+@[^<>\s]+)>[ \t]*$`)
+
+// CoAuthors returns the people credited by the "Co-authored-by:" trailers of message, once per email.
+func CoAuthors(message string) []Person {
+	var people []Person
+	seen := make(map[string]bool)
+
+	for _, match := range coAuthorPattern.FindAllStringSubmatch(message, -1) {
+		email := strings.ToLower(match[2])
+		if seen[email] {
+			continue
+		}
+		seen[email] = true
+		people = append(people, Person{Name: match[1], Email: match[2]})
+	}
+	return people
+}
@@ -0,0 +1,25 @@
+package commitmsg_test
+
+import (
+	"testing"
+
+	"github.com/babyfaceeasy/lema/pkg/commitmsg"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCoAuthors(t *testing.T) {
+	message := `Pair on the new parser
+
+Co-authored-by: Ada Lovelace <ada@example.com>
+co-authored-by:Grace Hopper <grace@example.com>
+Co-authored-by: Ada L. <ADA@example.com>
+Co-authored-by: nobody
+Signed-off-by: Alan Turing <alan@example.com>`
+
+	require.Equal(t, []commitmsg.Person{
+		{Name: "Ada Lovelace", Email: "ada@example.com"},
+		{Name: "Grace Hopper", Email: "grace@example.com"},
+	}, commitmsg.CoAuthors(message))
+
+	require.Empty(t, commitmsg.CoAuthors("Fix typo"))
+}
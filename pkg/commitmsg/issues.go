@@ -0,0 +1,49 @@
+// Package commitmsg extracts structured data from commit messages.
+package commitmsg
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// IssueReference is an issue a commit message says it closes, e.g. "Fixes #123" or
+// "Closes owner/repo#45".
+type IssueReference struct {
+	Owner      string
+	Repository string
+	Number     int
+	// Keyword is the lower-cased closing keyword used, e.g. "fixes".
+	Keyword string
+}
+
+// issueReferencePattern matches GitHub's closing keywords followed by an issue, optionally
+// qualified with its repository.
+var issueReferencePattern = regexp.MustCompile(`(?i)\b(close[sd]?|fix(?:e[sd])?|resolve[sd]?)\b:?\s+(?:([\w.-]+)/([\w.-]+))?#(\d+)\b`)
+
+// IssueReferences returns the issues closed by message, once each. Unqualified references such as
+// "#123" belong to owner/repository, the repository the commit was made in.
+func IssueReferences(message, owner, repository string) []IssueReference {
+	var refs []IssueReference
+	seen := make(map[string]bool)
+
+	for _, match := range issueReferencePattern.FindAllStringSubmatch(message, -1) {
+		number, err := strconv.Atoi(match[4])
+		if err != nil || number <= 0 {
+			continue
+		}
+
+		ref := IssueReference{Owner: owner, Repository: repository, Number: number, Keyword: strings.ToLower(match[1])}
+		if match[2] != "" {
+			ref.Owner, ref.Repository = match[2], match[3]
+		}
+
+		key := strings.ToLower(ref.Owner+"/"+ref.Repository) + "#" + match[4]
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+		refs = append(refs, ref)
+	}
+	return refs
+}
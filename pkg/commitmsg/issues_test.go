@@ -0,0 +1,23 @@
+package commitmsg_test
+
+import (
+	"testing"
+
+	"github.com/babyfaceeasy/lema/pkg/commitmsg"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIssueReferences(t *testing.T) {
+	message := `Fix crash on startup
+
+This fixes #123 and Closes chromium/website#45.
+Also mentions #7 without closing it, and fixes #123 again.
+Resolved: #9`
+
+	refs := commitmsg.IssueReferences(message, "chromium", "chromium")
+	require.Equal(t, []commitmsg.IssueReference{
+		{Owner: "chromium", Repository: "chromium", Number: 123, Keyword: "fixes"},
+		{Owner: "chromium", Repository: "website", Number: 45, Keyword: "closes"},
+		{Owner: "chromium", Repository: "chromium", Number: 9, Keyword: "resolved"},
+	}, refs)
+}
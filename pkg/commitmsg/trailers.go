@@ -0,0 +1,51 @@
+package commitmsg
+
+import (
+	"regexp"
+	"strings"
+)
+
+// Trailer is a "Key: value" line of the trailer block ending a commit message, e.g.
+// "Bug: 399354986" or "Reviewed-by: Jane Doe <jane@chromium.org>".
+type Trailer struct {
+	Key   string
+	Value string
+}
+
+// trailerPattern matches the first line of a trailer. Keys are made of letters, digits and dashes.
+var trailerPattern = regexp.MustCompile(`^([A-Za-z0-9][A-Za-z0-9-]*)[ \t]*:[ \t]*(.*?)[ \t]*$`)
+
+// Trailers returns the trailers of message, in order. Like git interpret-trailers, trailers are
+// read from the last paragraph of the message, and only when every line of it is a trailer, the
+// continuation of one, or a "(cherry picked from commit ...)" note. The subject is never a trailer.
+func Trailers(message string) []Trailer {
+	paragraphs := strings.Split(strings.TrimSpace(strings.ReplaceAll(message, "\r\n", "\n")), "\n\n")
+	if len(paragraphs) < 2 {
+		return nil
+	}
+	lines := strings.Split(strings.TrimSpace(paragraphs[len(paragraphs)-1]), "\n")
+
+	var trailers []Trailer
+	for _, line := range lines {
+		switch {
+		case strings.TrimSpace(line) == "":
+			// Paragraphs separated by more than one blank line leave blank lines behind.
+			continue
+		case line[0] == ' ' || line[0] == '\t':
+			if len(trailers) == 0 {
+				return nil
+			}
+			last := &trailers[len(trailers)-1]
+			last.Value = strings.TrimSpace(last.Value + " " + strings.TrimSpace(line))
+		case strings.HasPrefix(line, "(cherry picked from commit "):
+			continue
+		default:
+			match := trailerPattern.FindStringSubmatch(line)
+			if match == nil {
+				return nil
+			}
+			trailers = append(trailers, Trailer{Key: match[1], Value: match[2]})
+		}
+	}
+	return trailers
+}
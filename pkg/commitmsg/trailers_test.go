@@ -0,0 +1,54 @@
+package commitmsg_test
+
+import (
+	"testing"
+
+	"github.com/babyfaceeasy/lema/pkg/commitmsg"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTrailers(t *testing.T) {
+	message := `[omnibox] Fix crash when the popup closes
+
+The popup could be destroyed while a match was being opened.
+Note: this only affects Linux.
+
+Bug: 399354986
+Change-Id: I4b0a6f2a8d1e4c3b9a7f5e6d8c2b1a0f9e8d7c6b
+Reviewed-on: https://chromium-review.googlesource.com/c/chromium/src/+/6312345
+Reviewed-by: Jane Doe <jane@chromium.org>
+Commit-Queue: John Roe <john@chromium.org>
+Cr-Commit-Position: refs/heads/main@{#1426789}
+Tbr: someone@chromium.org,
+  other@chromium.org
+(cherry picked from commit 0123456789abcdef0123456789abcdef01234567)
+`
+
+	require.Equal(t, []commitmsg.Trailer{
+		{Key: "Bug", Value: "399354986"},
+		{Key: "Change-Id", Value: "I4b0a6f2a8d1e4c3b9a7f5e6d8c2b1a0f9e8d7c6b"},
+		{Key: "Reviewed-on", Value: "https://chromium-review.googlesource.com/c/chromium/src/+/6312345"},
+		{Key: "Reviewed-by", Value: "Jane Doe <jane@chromium.org>"},
+		{Key: "Commit-Queue", Value: "John Roe <john@chromium.org>"},
+		{Key: "Cr-Commit-Position", Value: "refs/heads/main@{#1426789}"},
+		{Key: "Tbr", Value: "someone@chromium.org, other@chromium.org"},
+	}, commitmsg.Trailers(message))
+}
+
+func TestTrailersOnlyInTrailerBlock(t *testing.T) {
+	tests := []struct {
+		name    string
+		message string
+	}{
+		{name: "subject only", message: "Bug: 123"},
+		{name: "prose in the last paragraph", message: "Fix it\n\nBug: 123\nThis line is prose."},
+		{name: "trailers before the body", message: "Fix it\n\nBug: 123\n\nMore details follow."},
+		{name: "continuation first", message: "Fix it\n\n  continued\nBug: 123"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Empty(t, commitmsg.Trailers(tt.message))
+		})
+	}
+}
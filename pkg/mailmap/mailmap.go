@@ -0,0 +1,62 @@
+// Package mailmap reads .mailmap files, which map the names and emails people committed with to
+// their proper name and email. See gitmailmap(5).
+package mailmap
+
+import (
+	"bufio"
+	"strings"
+)
+
+// Entry is a line of a .mailmap file. Commits with CommitEmail, and CommitName unless it is empty,
+// belong to ProperName and ProperEmail. An empty ProperName or ProperEmail keeps the one committed with.
+type Entry struct {
+	ProperName  string
+	ProperEmail string
+	CommitName  string
+	CommitEmail string
+}
+
+// Parse reads the entries of a .mailmap file, in order. Blank lines, comments and lines without
+// an email are skipped, like git does.
+func Parse(data string) []Entry {
+	var entries []Entry
+
+	scanner := bufio.NewScanner(strings.NewReader(data))
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		name1, email1, rest, ok := nameAndEmail(line)
+		if !ok {
+			continue
+		}
+		name2, email2, _, ok := nameAndEmail(rest)
+		if !ok {
+			// "Proper Name <commit@email>" only fixes the name.
+			entries = append(entries, Entry{ProperName: name1, CommitEmail: email1})
+			continue
+		}
+		entries = append(entries, Entry{ProperName: name1, ProperEmail: email1, CommitName: name2, CommitEmail: email2})
+	}
+	return entries
+}
+
+// nameAndEmail reads "Name <email>" at the start of s and returns what follows it.
+func nameAndEmail(s string) (name, email, rest string, ok bool) {
+	open := strings.IndexByte(s, '<')
+	if open < 0 {
+		return "", "", s, false
+	}
+	end := strings.IndexByte(s[open:], '>')
+	if end < 0 {
+		return "", "", s, false
+	}
+	end += open
+
+	name = strings.TrimSpace(s[:open])
+	email = strings.TrimSpace(s[open+1 : end])
+	return name, email, s[end+1:], email != ""
+}
@@ -0,0 +1,32 @@
+package mailmap_test
+
+import (
+	"testing"
+
+	"github.com/babyfaceeasy/lema/pkg/mailmap"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParse(t *testing.T) {
+	data := `# Keep alphabetized
+Jane Doe <jane@chromium.org>
+<jane@chromium.org> <jane.doe@gmail.com>
+Jane Doe <jane@chromium.org> <jdoe@corp.example>
+Joe R. Developer <joe@example.com> Joe <bugs@example.com> # old laptop
+
+no email here
+Broken <unterminated
+`
+
+	assert.Equal(t, []mailmap.Entry{
+		{ProperName: "Jane Doe", CommitEmail: "jane@chromium.org"},
+		{ProperEmail: "jane@chromium.org", CommitEmail: "jane.doe@gmail.com"},
+		{ProperName: "Jane Doe", ProperEmail: "jane@chromium.org", CommitEmail: "jdoe@corp.example"},
+		{ProperName: "Joe R. Developer", ProperEmail: "joe@example.com", CommitName: "Joe", CommitEmail: "bugs@example.com"},
+	}, mailmap.Parse(data))
+}
+
+func TestParseEmpty(t *testing.T) {
+	assert.Empty(t, mailmap.Parse(""))
+	assert.Empty(t, mailmap.Parse("# nothing yet\n"))
+}
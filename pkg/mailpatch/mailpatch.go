@@ -0,0 +1,290 @@
+// Package mailpatch extracts patches from mbox files and git format-patch output.
+package mailpatch
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net/mail"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// Patch is a commit sent by email.
+type Patch struct {
+	// SHA is the commit the patch was made from, only known from git format-patch output.
+	SHA         string
+	MessageID   string
+	AuthorName  string
+	AuthorEmail string
+	Date        time.Time
+	// Subject is stripped of its [PATCH ...] prefix.
+	Subject string
+	// Body is the rest of the commit message, without the diff.
+	Body string
+}
+
+// Message returns the commit message of the patch.
+func (p Patch) Message() string {
+	if p.Body == "" {
+		return p.Subject
+	}
+	return p.Subject + "\n\n" + p.Body
+}
+
+// ParseError reports a message of the mailbox that could not be read as a patch.
+type ParseError struct {
+	// Message is the position of the message in the mailbox, starting at 1.
+	Message int
+	// Line is the line of the mailbox the message starts at.
+	Line int
+	Err  error
+}
+
+func (e ParseError) Error() string {
+	return fmt.Sprintf("message %d (line %d): %v", e.Message, e.Line, e.Err)
+}
+
+// Result is the content of a mailbox. Messages without a patch, such as replies and cover
+// letters, are only counted as skipped.
+type Result struct {
+	Patches []Patch
+	Skipped int
+	Errors  []ParseError
+}
+
+var (
+	// envelopeSHAPattern matches the first line git format-patch writes for every commit.
+	envelopeSHAPattern = regexp.MustCompile(`^From ([0-9a-f]{40}) `)
+	// subjectPrefixPattern matches the [PATCH v2 3/7] style prefixes of patch subjects.
+	subjectPrefixPattern = regexp.MustCompile(`^(?:\s*\[[^\]]*\])+\s*`)
+	// mboxrdQuotePattern matches the body lines mboxrd escapes because they start with "From ".
+	mboxrdQuotePattern = regexp.MustCompile(`^>+From `)
+
+	errNoPatch = errors.New("message carries no patch")
+)
+
+// Parse reads the messages of r, an mbox file or the output of git format-patch. A single message
+// without an mbox "From " line is accepted too.
+func Parse(r io.Reader) (*Result, error) {
+	messages, err := split(r)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &Result{}
+	for i, m := range messages {
+		patch, err := parseMessage(m)
+		if errors.Is(err, errNoPatch) {
+			result.Skipped++
+			continue
+		}
+		if err != nil {
+			result.Errors = append(result.Errors, ParseError{Message: i + 1, Line: m.line, Err: err})
+			continue
+		}
+		result.Patches = append(result.Patches, *patch)
+	}
+	return result, nil
+}
+
+type rawMessage struct {
+	line     int
+	envelope string
+	content  []byte
+}
+
+// split cuts the mailbox into messages at the "From " lines that start them, undoing the mboxrd
+// quoting of body lines.
+func split(r io.Reader) ([]rawMessage, error) {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 16*1024*1024)
+
+	var messages []rawMessage
+	var current *rawMessage
+	previousBlank := true
+	for n := 1; scanner.Scan(); n++ {
+		line := strings.TrimSuffix(scanner.Text(), "\r")
+		if strings.HasPrefix(line, "From ") && previousBlank {
+			messages = append(messages, rawMessage{line: n, envelope: line})
+			current = &messages[len(messages)-1]
+			previousBlank = false
+			continue
+		}
+		if current == nil {
+			messages = append(messages, rawMessage{line: n})
+			current = &messages[len(messages)-1]
+		}
+
+		if mboxrdQuotePattern.MatchString(line) {
+			line = line[1:]
+		}
+		current.content = append(current.content, line...)
+		current.content = append(current.content, '\n')
+		previousBlank = line == ""
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read mailbox: %w", err)
+	}
+	return messages, nil
+}
+
+var addressParser = mail.AddressParser{WordDecoder: &mime.WordDecoder{}}
+
+// parseMessage reads the patch of a message, honouring the From, Date and Subject lines git am
+// accepts at the top of the body to override the headers.
+func parseMessage(m rawMessage) (*Patch, error) {
+	msg, err := mail.ReadMessage(bytes.NewReader(m.content))
+	if err != nil {
+		return nil, fmt.Errorf("invalid headers: %w", err)
+	}
+
+	body, err := textBody(msg.Header, msg.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	patch := &Patch{MessageID: strings.Trim(msg.Header.Get("Message-Id"), "<> ")}
+	if match := envelopeSHAPattern.FindStringSubmatch(m.envelope); match != nil {
+		patch.SHA = match[1]
+	}
+	if patch.SHA == "" && !strings.Contains(body, "\ndiff --git ") && !strings.Contains(body, "\n--- a/") {
+		return nil, errNoPatch
+	}
+
+	from := msg.Header.Get("From")
+	date := msg.Header.Get("Date")
+	subject := msg.Header.Get("Subject")
+	body = inBodyHeaders(body, &from, &date, &subject)
+
+	if from == "" {
+		return nil, errors.New("missing From header")
+	}
+	author, err := addressParser.Parse(from)
+	if err != nil {
+		return nil, fmt.Errorf("invalid From header %q: %w", from, err)
+	}
+	patch.AuthorName, patch.AuthorEmail = author.Name, author.Address
+	if patch.AuthorName == "" {
+		patch.AuthorName = author.Address
+	}
+
+	if date == "" {
+		return nil, errors.New("missing Date header")
+	}
+	if patch.Date, err = mail.ParseDate(date); err != nil {
+		return nil, fmt.Errorf("invalid Date header %q: %w", date, err)
+	}
+
+	decoded, err := (&mime.WordDecoder{}).DecodeHeader(subject)
+	if err != nil {
+		return nil, fmt.Errorf("invalid Subject header: %w", err)
+	}
+	patch.Subject = strings.TrimSpace(subjectPrefixPattern.ReplaceAllString(decoded, ""))
+	if patch.Subject == "" {
+		return nil, errors.New("missing Subject header")
+	}
+
+	patch.Body = commitBody(body)
+	return patch, nil
+}
+
+// textBody returns the decoded text of a message. Patches attached to multipart messages are
+// joined to the text parts before them.
+func textBody(header mail.Header, r io.Reader) (string, error) {
+	mediaType, params, err := mime.ParseMediaType(header.Get("Content-Type"))
+	if err != nil {
+		mediaType = "text/plain"
+	}
+
+	if strings.HasPrefix(mediaType, "multipart/") {
+		reader := multipart.NewReader(r, params["boundary"])
+		var parts []string
+		for {
+			part, err := reader.NextPart()
+			if err == io.EOF {
+				break
+			}
+			if err != nil {
+				return "", fmt.Errorf("invalid multipart body: %w", err)
+			}
+			partType, _, _ := mime.ParseMediaType(part.Header.Get("Content-Type"))
+			if partType != "" && !strings.HasPrefix(partType, "text/") {
+				continue
+			}
+			text, err := decode(part.Header.Get("Content-Transfer-Encoding"), part)
+			if err != nil {
+				return "", err
+			}
+			parts = append(parts, text)
+		}
+		return strings.Join(parts, "\n"), nil
+	}
+	return decode(header.Get("Content-Transfer-Encoding"), r)
+}
+
+func decode(encoding string, r io.Reader) (string, error) {
+	switch strings.ToLower(strings.TrimSpace(encoding)) {
+	case "quoted-printable":
+		r = quotedprintable.NewReader(r)
+	case "base64":
+		r = base64.NewDecoder(base64.StdEncoding, r)
+	}
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return "", fmt.Errorf("invalid %s body: %w", encoding, err)
+	}
+	return strings.ReplaceAll(string(data), "\r\n", "\n"), nil
+}
+
+// inBodyHeaders applies the From, Date and Subject lines opening body, as sent when the author of
+// a patch is not its sender, and returns the rest of the body.
+func inBodyHeaders(body string, from, date, subject *string) string {
+	rest := strings.TrimLeft(body, "\n")
+	found := false
+	for {
+		line, remaining, _ := strings.Cut(rest, "\n")
+		key, value, ok := strings.Cut(line, ":")
+		if !ok {
+			break
+		}
+		switch key {
+		case "From":
+			*from = strings.TrimSpace(value)
+		case "Date":
+			*date = strings.TrimSpace(value)
+		case "Subject":
+			*subject = strings.TrimSpace(value)
+		default:
+			if found {
+				return rest
+			}
+			return body
+		}
+		found = true
+		rest = remaining
+	}
+	if found {
+		return rest
+	}
+	return body
+}
+
+// commitBody returns the part of body before the diffstat or diff git format-patch appends.
+func commitBody(body string) string {
+	var lines []string
+	for _, line := range strings.Split(body, "\n") {
+		if line == "---" || strings.HasPrefix(line, "diff --git ") || strings.HasPrefix(line, "Index: ") {
+			break
+		}
+		lines = append(lines, line)
+	}
+	return strings.TrimSpace(strings.Join(lines, "\n"))
+}
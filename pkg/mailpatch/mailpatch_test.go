@@ -0,0 +1,112 @@
+package mailpatch_test
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/babyfaceeasy/lema/pkg/mailpatch"
+	"github.com/stretchr/testify/require"
+)
+
+const formatPatch = `From 8f3c0e1a9b2d4c6e8f0a1b3c5d7e9f1a2b4c6d8e Mon Sep 17 00:00:00 2001
+From: Alice Smith <alice@example.com>
+Date: Mon, 3 Mar 2025 10:00:00 +0100
+Subject: [PATCH v2 1/2] net: fix socket leak
+
+The socket was never closed on error.
+>From now on it is.
+
+Signed-off-by: Alice Smith <alice@example.com>
+---
+ net/socket.c | 1 +
+ 1 file changed, 1 insertion(+)
+
+diff --git a/net/socket.c b/net/socket.c
+index 1111111..2222222 100644
+--- a/net/socket.c
++++ b/net/socket.c
+@@ -1 +1,2 @@
+ int a;
++int b;
+-- 
+2.43.0
+
+`
+
+func TestParseFormatPatch(t *testing.T) {
+	result, err := mailpatch.Parse(strings.NewReader(formatPatch))
+	require.NoError(t, err)
+	require.Empty(t, result.Errors)
+	require.Len(t, result.Patches, 1)
+
+	patch := result.Patches[0]
+	require.Equal(t, "8f3c0e1a9b2d4c6e8f0a1b3c5d7e9f1a2b4c6d8e", patch.SHA)
+	require.Equal(t, "Alice Smith", patch.AuthorName)
+	require.Equal(t, "alice@example.com", patch.AuthorEmail)
+	require.True(t, patch.Date.Equal(time.Date(2025, time.March, 3, 9, 0, 0, 0, time.UTC)))
+	require.Equal(t, "net: fix socket leak", patch.Subject)
+	require.Equal(t, "The socket was never closed on error.\nFrom now on it is.\n\nSigned-off-by: Alice Smith <alice@example.com>", patch.Body)
+}
+
+func TestParseMailingListArchive(t *testing.T) {
+	mbox := `From bob@example.com Tue Mar  4 08:00:00 2025
+From: Bob <bob@example.com>
+Date: Tue, 4 Mar 2025 08:00:00 +0000
+Message-ID: <20250304080000.1-bob@example.com>
+Subject: [PATCH] docs: typo
+Content-Type: text/plain; charset=utf-8
+Content-Transfer-Encoding: quoted-printable
+
+From: Ren=C3=A9e <renee@example.com>
+Subject: docs: fix typo in README
+
+Caf=C3=A9 is spelled with an accent.
+
+diff --git a/README b/README
+--- a/README
++++ b/README
+
+From carol@example.com Tue Mar  4 09:00:00 2025
+From: Carol <carol@example.com>
+Date: Tue, 4 Mar 2025 09:00:00 +0000
+Subject: Re: [PATCH] docs: typo
+
+Looks good to me.
+
+From dave@example.com Tue Mar  4 10:00:00 2025
+From: Dave <dave@example.com>
+Subject: [PATCH] build: bump version
+
+diff --git a/VERSION b/VERSION
+--- a/VERSION
++++ b/VERSION
+`
+
+	result, err := mailpatch.Parse(strings.NewReader(mbox))
+	require.NoError(t, err)
+	require.Len(t, result.Patches, 1)
+	require.Equal(t, 1, result.Skipped)
+
+	patch := result.Patches[0]
+	require.Empty(t, patch.SHA)
+	require.Equal(t, "20250304080000.1-bob@example.com", patch.MessageID)
+	require.Equal(t, "Renée", patch.AuthorName)
+	require.Equal(t, "docs: fix typo in README", patch.Subject)
+	require.Equal(t, "Café is spelled with an accent.", patch.Body)
+
+	require.Len(t, result.Errors, 1)
+	require.Equal(t, 3, result.Errors[0].Message)
+	require.Equal(t, 25, result.Errors[0].Line)
+	require.Contains(t, result.Errors[0].Error(), "missing Date header")
+}
+
+func TestParseSingleMessageWithoutEnvelope(t *testing.T) {
+	message := strings.SplitN(formatPatch, "\n", 2)[1]
+
+	result, err := mailpatch.Parse(strings.NewReader(message))
+	require.NoError(t, err)
+	require.Len(t, result.Patches, 1)
+	require.Empty(t, result.Patches[0].SHA)
+	require.Equal(t, "net: fix socket leak", result.Patches[0].Subject)
+}